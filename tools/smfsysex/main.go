@@ -1,13 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"gitlab.com/golang-utils/config/v2"
 	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	_ "gitlab.com/gomidi/midi/v2/drivers/midicatdrv"
 	"gitlab.com/gomidi/midi/v2/smf"
 	"gitlab.com/gomidi/midi/v2/sysex"
 )
@@ -29,6 +33,17 @@ var (
 	argReadVerbose = cmdRead.Bool("verbose", "be verbose about printing", config.Shortflag('v'))
 	argTrackRead   = cmdRead.Int("track", "number of the track to read from", config.Required(), config.Shortflag('t'), config.Default(1))
 
+	cmdSend           = cfg.Command("send", "sends the sysex data of a track to a MIDI device")
+	argSendOut        = cmdSend.Int("out", "number of the MIDI output device", config.Required(), config.Shortflag('o'))
+	argSendTrack      = cmdSend.Int("track", "number of the track to send", config.Required(), config.Shortflag('t'), config.Default(1))
+	argSendDelay      = cmdSend.Int("delay", "pause between the sysex messages (in milliseconds)", config.Shortflag('d'))
+	argSendChunk      = cmdSend.Int("chunk", "size of the chunks (in bytes) the sysex messages are split into", config.Shortflag('c'))
+	argSendChunkDelay = cmdSend.Int("chunkdelay", "pause between the chunks (in milliseconds)")
+	argSendIn         = cmdSend.Int("in", "number of the MIDI input device for the handshake replies of the device", config.Shortflag('i'))
+	argSendACK        = cmdSend.String("ack", "the handshake reply that acknowledges a message (hex string including F0 and F7), requires --in")
+	argSendNAK        = cmdSend.String("nak", "the handshake reply that rejects a message, so that it is sent again (hex string including F0 and F7)")
+	argSendTimeout    = cmdSend.Int("timeout", "timeout for the handshake reply (in milliseconds)", config.Default(2000))
+
 	cmdExample = cfg.Command("example", "write example sysex data")
 )
 
@@ -62,6 +77,13 @@ func run() error {
 	case cmdRead:
 		trno := int(argTrackRead.Get()) - 1
 		return readSysEx(argFile.Get(), trno, argRaw.Get(), argReadVerbose.Get())
+	case cmdSend:
+		trno := int(argSendTrack.Get()) - 1
+		opts, err := senderOptions()
+		if err != nil {
+			return err
+		}
+		return sendSysEx(argFile.Get(), trno, int(argSendOut.Get()), opts...)
 	case cmdExample:
 		return writeExample(argFile.Get())
 	default:
@@ -197,6 +219,99 @@ func readSysEx(file string, trackNo int, raw bool, verbose bool) error {
 	return nil
 }
 
+// senderOptions returns the options of the sysex.Sender for the arguments of the send command
+func senderOptions() (opts []sysex.SenderOption, err error) {
+	opts = append(opts,
+		sysex.MessageDelay(time.Duration(argSendDelay.Get())*time.Millisecond),
+		sysex.ChunkSize(int(argSendChunk.Get())),
+		sysex.ChunkDelay(time.Duration(argSendChunkDelay.Get())*time.Millisecond),
+	)
+
+	if !argSendIn.IsSet() {
+		if argSendACK.IsSet() || argSendNAK.IsSet() {
+			return nil, fmt.Errorf("the handshake needs the number of the MIDI input device (--in)")
+		}
+		return opts, nil
+	}
+
+	if !argSendACK.IsSet() {
+		return nil, fmt.Errorf("the handshake needs the ACK reply of the device (--ack)")
+	}
+
+	ack, err := parseSysEx(argSendACK.Get())
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACK reply: %v", err)
+	}
+
+	var nak []byte
+	if argSendNAK.IsSet() {
+		nak, err = parseSysEx(argSendNAK.Get())
+		if err != nil {
+			return nil, fmt.Errorf("invalid NAK reply: %v", err)
+		}
+	}
+
+	in, err := drivers.InByNumber(int(argSendIn.Get()))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := func(msg []byte) sysex.Reply {
+		switch {
+		case bytes.Equal(msg, ack):
+			return sysex.ACK
+		case nak != nil && bytes.Equal(msg, nak):
+			return sysex.NAK
+		default:
+			return sysex.NoReply
+		}
+	}
+
+	return append(opts, sysex.Handshake(in, time.Duration(argSendTimeout.Get())*time.Millisecond, reply)), nil
+}
+
+func sendSysEx(file string, trackNo int, outNo int, opts ...sysex.SenderOption) error {
+	defer midi.CloseDriver()
+
+	mf, err := smf.ReadFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	if trackNo < 0 || trackNo >= len(mf.Tracks) {
+		return fmt.Errorf("there is no track number %v", trackNo+1)
+	}
+
+	var msgs [][]byte
+
+	for _, ev := range mf.Tracks[trackNo] {
+		var bt []byte
+		if ev.Message.GetSysEx(&bt) {
+			msgs = append(msgs, midi.SysEx(bt))
+		}
+	}
+
+	if len(msgs) == 0 {
+		return fmt.Errorf("track number %v has no sysex data", trackNo+1)
+	}
+
+	out, err := drivers.OutByNumber(outNo)
+	if err != nil {
+		return err
+	}
+
+	opts = append(opts, sysex.Progress(func(sent, total int) {
+		fmt.Fprintf(os.Stderr, "\rsent %v of %v bytes", sent, total)
+	}))
+
+	sender := sysex.NewSender(out, opts...)
+
+	err = sender.Send(msgs...)
+	fmt.Fprintln(os.Stderr)
+	return err
+}
+
 func showTracks(file string) error {
 
 	mf, err := smf.ReadFile(file)
@@ -0,0 +1,255 @@
+package midi
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+const (
+	// ActiveSenseTimeout is the time after which the connection is regarded as lost, if no message has been received
+	// since the last message (see WatchActiveSense). It is the 300ms of the MIDI specification.
+	ActiveSenseTimeout = 300 * time.Millisecond
+
+	// ActiveSenseInterval is the default interval after which an ActiveSenseOut sends an active sense message,
+	// if nothing has been sent. It leaves some room for the latency within the ActiveSenseTimeout of the receiver.
+	ActiveSenseInterval = 250 * time.Millisecond
+)
+
+// WatchActiveSense is an option to watch the connection: once an active sense message has been received, the
+// connection is regarded as lost, if no data is received within the timeout (ActiveSenseTimeout, if timeout is 0).
+// Any data counts, including the messages that are not passed to the receiver (like timing clocks without UseTimeCode)
+// and the parts of sysex messages, as far as the driver reports them (see drivers.ListenConfig.OnReceive).
+// With a driver that does not report the received data, only the messages that the driver passes count, so that
+// e.g. a long sysex transfer without UseSysEx may be taken for a lost connection.
+// To notice them, the driver is configured to pass all active sense and timing clock messages (as if UseActiveSense
+// and UseTimeCode were set), but they are passed to the receiver only with the respective option.
+// Then onLost is called (if not nil) and the messages of SilenceChannel(-1) are sent to the silence port (if not nil),
+// to turn off all notes of the sending device. The watching starts again with the next active sense message.
+// The active sense messages are not passed to the receiver, unless UseActiveSense is set.
+func WatchActiveSense(timeout time.Duration, onLost func(), silence drivers.Out) Option {
+	return func(l *listeningOptions) {
+		if timeout <= 0 {
+			timeout = ActiveSenseTimeout
+		}
+		l.ActiveSenseTimeout = timeout
+		l.OnConnectionLost = onLost
+		l.SilenceOut = silence
+	}
+}
+
+// activeSenseWatchdog watches the connection, after an active sense message has been received
+type activeSenseWatchdog struct {
+	mx       sync.Mutex
+	timeout  time.Duration
+	onLost   func()
+	silence  drivers.Out
+	pass     bool // pass active sense messages to the receiver
+	timeCode bool // pass timecode messages to the receiver
+	timer    *time.Timer
+	last     time.Time
+	armed    bool
+	stopped  bool
+}
+
+// newWatchdog returns a watchdog for the given options or nil, if the connection is not watched.
+// Since the watchdog must notice any data that is received, the config is changed to let the driver pass all
+// realtime messages (the watchdog filters them instead) and to report the received data (see drivers.ListenConfig.OnReceive).
+func newWatchdog(opt listeningOptions, conf *drivers.ListenConfig) *activeSenseWatchdog {
+	if opt.ActiveSenseTimeout <= 0 {
+		return nil
+	}
+
+	w := &activeSenseWatchdog{
+		timeout:  opt.ActiveSenseTimeout,
+		onLost:   opt.OnConnectionLost,
+		silence:  opt.SilenceOut,
+		pass:     opt.ActiveSense,
+		timeCode: opt.TimeCode,
+	}
+
+	conf.ActiveSense = true
+	conf.TimeCode = true
+	conf.OnReceive = w.touch
+	return w
+}
+
+// received registers the received message and returns, if it should be passed to the receiver
+func (w *activeSenseWatchdog) received(msg Message) (pass bool) {
+	if w == nil {
+		return true
+	}
+
+	isActiveSense := len(msg) == 1 && msg[0] == byteActivesense
+	isTimeCode := len(msg) > 0 && (msg[0] == byteTimingClock || msg[0] == byteMIDITimingCodeMessage)
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if w.stopped {
+		return false
+	}
+
+	if isActiveSense {
+		w.armed = true
+	}
+
+	w.restart()
+
+	switch {
+	case isActiveSense:
+		return w.pass
+	case isTimeCode:
+		return w.timeCode
+	default:
+		return true
+	}
+}
+
+// touch restarts the timeout, whenever data is received from the device, even if it is not a complete message
+func (w *activeSenseWatchdog) touch() {
+	w.mx.Lock()
+	defer w.mx.Unlock()
+
+	if !w.stopped {
+		w.restart()
+	}
+}
+
+// restart restarts the timeout, if the watchdog is armed
+func (w *activeSenseWatchdog) restart() {
+	if !w.armed {
+		return
+	}
+
+	w.last = time.Now()
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.timeout, w.expired)
+	} else {
+		w.timer.Reset(w.timeout)
+	}
+}
+
+// expired is called by the timer
+func (w *activeSenseWatchdog) expired() {
+	w.mx.Lock()
+	// the timer might have been reset while it fired
+	if w.stopped || !w.armed || time.Since(w.last) < w.timeout {
+		w.mx.Unlock()
+		return
+	}
+	w.armed = false
+	w.mx.Unlock()
+
+	if w.onLost != nil {
+		w.onLost()
+	}
+
+	if w.silence != nil {
+		for _, msg := range SilenceChannel(-1) {
+			w.silence.Send(msg)
+		}
+	}
+}
+
+// wrap returns a stop function that stops the listening and the watchdog
+func (w *activeSenseWatchdog) wrap(stop func()) func() {
+	if w == nil || stop == nil {
+		return stop
+	}
+
+	return func() {
+		stop()
+
+		w.mx.Lock()
+		w.stopped = true
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+		w.mx.Unlock()
+	}
+}
+
+// ActiveSenseOut is an out port that sends an active sense message, whenever nothing has been sent for the interval,
+// so that the receiving device can detect the loss of the connection. The sending starts with NewActiveSenseOut
+// and ends when the port is closed. It starts again when the port is reopened.
+// In contrast to the other ports, an ActiveSenseOut can be used from different goroutines.
+type ActiveSenseOut struct {
+	drivers.Out
+	interval time.Duration
+	mx       sync.Mutex
+	timer    *time.Timer
+	closed   bool
+}
+
+// NewActiveSenseOut returns an ActiveSenseOut for the given port. If interval is 0, ActiveSenseInterval is used.
+func NewActiveSenseOut(out drivers.Out, interval time.Duration) *ActiveSenseOut {
+	if interval <= 0 {
+		interval = ActiveSenseInterval
+	}
+
+	o := &ActiveSenseOut{Out: out, interval: interval}
+	o.mx.Lock()
+	o.timer = time.AfterFunc(interval, o.idle)
+	o.mx.Unlock()
+	return o
+}
+
+// idle is called by the timer, when nothing has been sent for the interval
+func (o *ActiveSenseOut) idle() {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	if o.closed {
+		return
+	}
+
+	if o.Out.IsOpen() {
+		o.Out.Send(Activesense())
+	}
+
+	o.timer.Reset(o.interval)
+}
+
+// Send sends the given data and restarts the interval
+func (o *ActiveSenseOut) Send(data []byte) error {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	err := o.Out.Send(data)
+
+	if !o.closed {
+		o.timer.Reset(o.interval)
+	}
+
+	return err
+}
+
+// Open opens the port and starts the sending of active sense messages again, if the port has been closed.
+func (o *ActiveSenseOut) Open() error {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	err := o.Out.Open()
+	if err != nil {
+		return err
+	}
+
+	if o.closed {
+		o.closed = false
+		o.timer.Reset(o.interval)
+	}
+
+	return nil
+}
+
+// Close stops the sending of active sense messages and closes the port.
+func (o *ActiveSenseOut) Close() error {
+	o.mx.Lock()
+	defer o.mx.Unlock()
+
+	o.closed = true
+	o.timer.Stop()
+	return o.Out.Close()
+}
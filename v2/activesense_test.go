@@ -0,0 +1,269 @@
+package midi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+// silenceOut collects the sent messages
+type silenceOut struct {
+	drivers.Out
+	sent chan midi.Message
+}
+
+func (s *silenceOut) Send(bt []byte) error {
+	s.sent <- midi.Message(bt)
+	return nil
+}
+
+func TestWatchActiveSense(t *testing.T) {
+	drv := testdrv.New("watch-activesense")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	lost := make(chan bool, 1)
+	silence := &silenceOut{Out: outs[0], sent: make(chan midi.Message, 64)}
+	received := make(chan midi.Message, 16)
+
+	stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+		received <- msg
+	}, midi.WatchActiveSense(20*time.Millisecond, func() { lost <- true }, silence))
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	defer stop()
+
+	// the watchdog is not armed before the first active sense message
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case <-lost:
+		t.Fatalf("connection lost before active sensing")
+	default:
+	}
+
+	outs[0].Send(midi.Activesense())
+
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatalf("connection loss not detected")
+	}
+
+	var bf strings.Builder
+	for i := 0; i < 32; i++ {
+		select {
+		case msg := <-silence.sent:
+			if i < 2 {
+				fmt.Fprintf(&bf, "%s\n", msg)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("got %v silence messages, want 32", i)
+		}
+	}
+
+	want := `ControlChange channel: 0 controller: 123 value: 0
+ControlChange channel: 0 controller: 120 value: 0
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	close(received)
+	var msgs []string
+	for msg := range received {
+		msgs = append(msgs, msg.String())
+	}
+
+	// the active sense message is not passed to the receiver
+	if got, want := strings.Join(msgs, "\n"), "NoteOn channel: 1 key: 60 velocity: 100"; got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWatchActiveSenseData(t *testing.T) {
+	// a sysex dump of 40 parts
+	dump := [][]byte{{0xF0, 0x41}}
+	for i := 0; i < 38; i++ {
+		dump = append(dump, []byte{0x01, 0x02, 0x03})
+	}
+	dump = append(dump, []byte{0xF7})
+
+	var clocks [][]byte
+	for i := 0; i < 40; i++ {
+		clocks = append(clocks, midi.TimingClock())
+	}
+
+	tests := []struct {
+		descr string
+		data  [][]byte
+		opts  []midi.Option
+		want  string
+	}{
+		{"timing clocks without UseTimeCode", clocks, nil, ""},
+		{"timing clocks with UseTimeCode", clocks[:2], []midi.Option{midi.UseTimeCode()}, "TimingClock 1\nTimingClock 1"},
+		{"sysex dump", dump, []midi.Option{midi.UseSysEx()}, "SysExType 117"},
+		{"sysex dump without UseSysEx", dump, nil, ""},
+	}
+
+	for _, test := range tests {
+		drv := testdrv.New("watch-activesense-data")
+		ins, _ := drv.Ins()
+		outs, _ := drv.Outs()
+		outs[0].Open()
+
+		lost := make(chan bool, 1)
+		received := make(chan midi.Message, 16)
+
+		opts := append([]midi.Option{midi.WatchActiveSense(30*time.Millisecond, func() { lost <- true }, nil)}, test.opts...)
+
+		stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+			received <- msg
+		}, opts...)
+
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+
+		outs[0].Send(midi.Activesense())
+
+		// the data lasts 200ms, much longer than the timeout
+		for _, bt := range test.data {
+			time.Sleep(5 * time.Millisecond)
+			outs[0].Send(bt)
+		}
+
+		select {
+		case <-lost:
+			t.Errorf("[%s] connection lost while receiving data", test.descr)
+		default:
+		}
+
+		select {
+		case <-lost:
+		case <-time.After(time.Second):
+			t.Errorf("[%s] connection loss not detected", test.descr)
+		}
+
+		stop()
+		close(received)
+
+		var msgs []string
+		for msg := range received {
+			msgs = append(msgs, fmt.Sprintf("%s %v", msg.Type(), len(msg)))
+		}
+
+		if got := strings.Join(msgs, "\n"); got != test.want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, test.want)
+		}
+	}
+}
+
+// The callbackIn (see listenctx_test.go) passes the messages directly, without a drivers.Reader and without
+// calling OnReceive, like a driver that does not report the received data.
+func TestWatchActiveSenseWithoutReader(t *testing.T) {
+	in := &callbackIn{}
+
+	lost := make(chan bool, 1)
+	received := make(chan midi.Message, 64)
+
+	stop, err := midi.ListenTo(in, func(msg midi.Message, timestampms int32) {
+		received <- msg
+	}, midi.WatchActiveSense(30*time.Millisecond, func() { lost <- true }, nil))
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	defer stop()
+
+	// the driver is configured to pass the active sense and timing clock messages to the watchdog
+	if !in.conf.ActiveSense || !in.conf.TimeCode || in.conf.OnReceive == nil {
+		t.Errorf("config: ActiveSense %v, TimeCode %v, OnReceive set %v; want true, true, true",
+			in.conf.ActiveSense, in.conf.TimeCode, in.conf.OnReceive != nil)
+	}
+
+	in.recv(midi.Activesense(), 0)
+
+	// the passed messages count, even though they are filtered by the watchdog
+	for i := 0; i < 40; i++ {
+		time.Sleep(5 * time.Millisecond)
+		in.recv(midi.TimingClock(), 0)
+	}
+
+	select {
+	case <-lost:
+		t.Errorf("connection lost while receiving timing clocks")
+	default:
+	}
+
+	// data that the driver neither passes nor reports (e.g. filtered sysex) would not count
+	time.Sleep(50 * time.Millisecond)
+
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Errorf("connection loss not detected")
+	}
+
+	if len(received) != 0 {
+		t.Errorf("%v messages passed to the receiver; want 0", len(received))
+	}
+}
+
+func TestActiveSenseOut(t *testing.T) {
+	drv := testdrv.New("activesense-out")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	received := make(chan midi.Message, 16)
+
+	stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+		select {
+		case received <- msg:
+		default:
+		}
+	}, midi.UseActiveSense())
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	defer stop()
+
+	out := midi.NewActiveSenseOut(outs[0], 10*time.Millisecond)
+	out.Send(midi.NoteOn(1, 60, 100))
+
+	var bf strings.Builder
+	for i := 0; i < 3; i++ {
+		select {
+		case msg := <-received:
+			fmt.Fprintf(&bf, "%s\n", msg)
+		case <-time.After(time.Second):
+			t.Fatalf("missing active sense message")
+		}
+	}
+
+	out.Close()
+
+	want := `NoteOn channel: 1 key: 60 velocity: 100
+ActiveSense
+ActiveSense
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
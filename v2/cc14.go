@@ -0,0 +1,102 @@
+package midi
+
+import (
+	"fmt"
+)
+
+// ControlChange14 returns the control change messages that send the given 14-bit value via the controller pair
+// of the given MSB controller (0-31) and its LSB controller (MSB controller + 32), e.g. VolumeMSB and VolumeLSB.
+// The MSB is sent first, since receiving a MSB resets the LSB.
+// If the controller is > 31, the method panics.
+func ControlChange14(channel, msbController uint8, value uint16) []Message {
+	if msbController > 31 {
+		panic("invalid MSB controller number")
+	}
+
+	value &= 0x3FFF
+
+	return []Message{
+		ControlChange(channel, msbController, uint8(value>>7)),
+		ControlChange(channel, msbController+32, uint8(value&0x7F)),
+	}
+}
+
+// ControlChange14Event is a 14-bit value of a controller pair.
+type ControlChange14Event struct {
+	Channel uint8
+
+	// Controller is the MSB controller of the pair (0-31).
+	Controller uint8
+
+	// Value is the 14-bit value (0-16383).
+	Value uint16
+}
+
+// String represents the event as a string.
+func (e ControlChange14Event) String() string {
+	return fmt.Sprintf("ControlChange14 channel: %v controller: %v value: %v", e.Channel, e.Controller, e.Value)
+}
+
+// ControlChange14Decoder combines the incoming control change messages of controller pairs to 14-bit values.
+// Following the MIDI specification, a MSB resets the LSB of the pair to 0, while a LSB only changes the lower
+// 7 bits of the last MSB. A LSB is ignored, as long as no MSB has been received for the pair.
+// A ControlChange14Decoder is not threadsafe.
+type ControlChange14Decoder struct {
+	msb         [16][32]uint8
+	hasMSB      [16][32]bool
+	controllers [32]bool
+}
+
+// NewControlChange14Decoder returns a decoder for the pairs of the given MSB controllers (0-31).
+// If no controllers are given, all pairs are decoded.
+func NewControlChange14Decoder(msbControllers ...uint8) *ControlChange14Decoder {
+	var d ControlChange14Decoder
+
+	if len(msbControllers) == 0 {
+		for i := range d.controllers {
+			d.controllers[i] = true
+		}
+		return &d
+	}
+
+	for _, ctl := range msbControllers {
+		if ctl < 32 {
+			d.controllers[ctl] = true
+		}
+	}
+	return &d
+}
+
+// Decode returns the 14-bit event and true, if the given message is a MSB or LSB of a decoded controller pair.
+// Otherwise false is returned.
+// AllControllersOff and Reset messages reset the stored MSBs. SMF meta messages are ignored.
+func (d *ControlChange14Decoder) Decode(msg Message) (ev ControlChange14Event, ok bool) {
+	var ch, ctl, val uint8
+
+	if isReset(msg) {
+		d.hasMSB = [16][32]bool{}
+		return
+	}
+
+	if !msg.GetControlChange(&ch, &ctl, &val) {
+		return
+	}
+
+	switch {
+	case ctl == AllControllersOff:
+		d.hasMSB[ch] = [32]bool{}
+		return
+	case ctl < 32 && d.controllers[ctl]:
+		d.msb[ch][ctl] = val
+		d.hasMSB[ch][ctl] = true
+		return ControlChange14Event{Channel: ch, Controller: ctl, Value: uint16(val) << 7}, true
+	case ctl >= 32 && ctl < 64 && d.controllers[ctl-32]:
+		ctl -= 32
+		if !d.hasMSB[ch][ctl] {
+			return
+		}
+		return ControlChange14Event{Channel: ch, Controller: ctl, Value: uint16(d.msb[ch][ctl])<<7 | uint16(val)}, true
+	default:
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package midi
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestControlChange14(t *testing.T) {
+	tests := []struct {
+		ctl      uint8
+		value    uint16
+		expected string
+	}{
+		{VolumeMSB, 0x3FFF, "ControlChange channel: 2 controller: 7 value: 127\nControlChange channel: 2 controller: 39 value: 127\n"},
+		{ModulationWheelMSB, 1000, "ControlChange channel: 2 controller: 1 value: 7\nControlChange channel: 2 controller: 33 value: 104\n"},
+		{BankSelectMSB, 0, "ControlChange channel: 2 controller: 0 value: 0\nControlChange channel: 2 controller: 32 value: 0\n"},
+	}
+
+	for _, test := range tests {
+		var bf bytes.Buffer
+		for _, msg := range ControlChange14(2, test.ctl, test.value) {
+			fmt.Fprintf(&bf, "%s\n", msg)
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("ControlChange14(2, %v, %v)\ngot:\n%s\nwant:\n%s", test.ctl, test.value, got, want)
+		}
+	}
+}
+
+func TestControlChange14Decoder(t *testing.T) {
+	tests := []struct {
+		descr    string
+		ctls     []uint8
+		msgs     []Message
+		expected string
+	}{
+		{
+			"pair",
+			nil,
+			ControlChange14(1, VolumeMSB, 1000),
+			"ControlChange14 channel: 1 controller: 7 value: 896\nControlChange14 channel: 1 controller: 7 value: 1000\n",
+		},
+		{
+			"msb resets lsb",
+			nil,
+			[]Message{ControlChange(1, VolumeMSB, 7), ControlChange(1, VolumeLSB, 104), ControlChange(1, VolumeMSB, 8)},
+			"ControlChange14 channel: 1 controller: 7 value: 896\n" +
+				"ControlChange14 channel: 1 controller: 7 value: 1000\n" +
+				"ControlChange14 channel: 1 controller: 7 value: 1024\n",
+		},
+		{
+			"lsb without msb",
+			nil,
+			[]Message{ControlChange(1, VolumeLSB, 104), ControlChange(2, VolumeMSB, 1), ControlChange(1, VolumeLSB, 3)},
+			"ControlChange14 channel: 2 controller: 7 value: 128\n",
+		},
+		{
+			"lsb after AllControllersOff",
+			nil,
+			[]Message{ControlChange(1, VolumeMSB, 1), ControlChange(1, AllControllersOff, 0), ControlChange(1, VolumeLSB, 3)},
+			"ControlChange14 channel: 1 controller: 7 value: 128\n",
+		},
+		{
+			"lsb after meta message",
+			nil,
+			// marker meta message of a SMF track
+			[]Message{ControlChange(1, VolumeMSB, 1), Message{0xFF, 0x06, 0x01, 'A'}, ControlChange(1, VolumeLSB, 3)},
+			"ControlChange14 channel: 1 controller: 7 value: 128\nControlChange14 channel: 1 controller: 7 value: 131\n",
+		},
+		{
+			"lsb after reset",
+			nil,
+			[]Message{ControlChange(1, VolumeMSB, 1), Reset(), ControlChange(1, VolumeLSB, 3)},
+			"ControlChange14 channel: 1 controller: 7 value: 128\n",
+		},
+		{
+			"selected controllers",
+			[]uint8{ModulationWheelMSB},
+			[]Message{ControlChange(1, VolumeMSB, 1), ControlChange(1, ModulationWheelMSB, 1), ControlChange(1, ModulationWheelLSB, 1), ControlChange(1, HoldPedalSwitch, On)},
+			"ControlChange14 channel: 1 controller: 1 value: 128\nControlChange14 channel: 1 controller: 1 value: 129\n",
+		},
+	}
+
+	for _, test := range tests {
+		d := NewControlChange14Decoder(test.ctls...)
+
+		var bf bytes.Buffer
+		for _, msg := range test.msgs {
+			if ev, ok := d.Decode(msg); ok {
+				fmt.Fprintf(&bf, "%s\n", ev)
+			}
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+	}
+}
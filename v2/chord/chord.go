@@ -0,0 +1,134 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package chord recognizes chords from notes, either live from the messages of a player (see Detector)
+or offline from a SMF track (see Segment).
+
+A Chord consists of a root note, a Type (the intervals from the root, e.g. "m7") and the bass note,
+so that inversions and slash chords can be expressed, e.g. "Am7/C".
+
+Chord symbols of lead sheets can be parsed (see Parse) and voiced to notes (see Chord.Voice), e.g.
+
+	c, err := chord.Parse("Cmaj7/G")
+	notes, err := c.Voice(chord.Drop2, chord.Range(48, 72))
+	notes.AddTo(&track, 0, 960, 0, 100)
+*/
+package chord
+
+import (
+	"strings"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Type is the type of a chord, given by the intervals from the root.
+type Type struct {
+	// Symbol is the suffix of the chord symbol after the root, e.g. "m7".
+	Symbol string
+
+	// Intervals are the intervals of the chord tones from the root, starting with midi.Unison.
+	Intervals []midi.Interval
+}
+
+// Types are the known chord types. User defined types can be added.
+var Types = []Type{
+	{"5", []midi.Interval{midi.Unison, midi.Fifth}},
+	{"", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth}},
+	{"m", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth}},
+	{"dim", []midi.Interval{midi.Unison, midi.MinorThird, midi.Tritone}},
+	{"aug", []midi.Interval{midi.Unison, midi.MajorThird, midi.MinorSixth}},
+	{"sus2", []midi.Interval{midi.Unison, midi.MajorSecond, midi.Fifth}},
+	{"sus4", []midi.Interval{midi.Unison, midi.Fourth, midi.Fifth}},
+	{"6", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorSixth}},
+	{"m6", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MajorSixth}},
+	{"7", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh}},
+	{"maj7", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorSeventh}},
+	{"m7", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MinorSeventh}},
+	{"mmaj7", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MajorSeventh}},
+	{"m7b5", []midi.Interval{midi.Unison, midi.MinorThird, midi.Tritone, midi.MinorSeventh}},
+	{"dim7", []midi.Interval{midi.Unison, midi.MinorThird, midi.Tritone, midi.MajorSixth}},
+	{"7sus4", []midi.Interval{midi.Unison, midi.Fourth, midi.Fifth, midi.MinorSeventh}},
+	{"add9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorNinth}},
+	{"madd9", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MajorNinth}},
+	{"6/9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorSixth, midi.MajorNinth}},
+	{"9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh, midi.MajorNinth}},
+	{"maj9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorSeventh, midi.MajorNinth}},
+	{"m9", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MinorSeventh, midi.MajorNinth}},
+	{"7b9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh, midi.MinorNinth}},
+	{"7#9", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh, midi.MinorTenth}},
+	{"11", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh, midi.MajorNinth, midi.Eleventh}},
+	{"m11", []midi.Interval{midi.Unison, midi.MinorThird, midi.Fifth, midi.MinorSeventh, midi.MajorNinth, midi.Eleventh}},
+	{"13", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MinorSeventh, midi.MajorNinth, midi.MajorThirteenth}},
+	{"maj13", []midi.Interval{midi.Unison, midi.MajorThird, midi.Fifth, midi.MajorSeventh, midi.MajorNinth, midi.MajorThirteenth}},
+}
+
+// pitchClasses returns the pitch classes of the intervals relative to the root (0-11)
+func (t Type) pitchClasses() (pcs [12]bool) {
+	for _, iv := range t.Intervals {
+		pcs[(int(iv)%12+12)%12] = true
+	}
+	return
+}
+
+// Chord is a chord with a root, a type and a bass note.
+type Chord struct {
+	// Root is the root note of the chord.
+	Root midi.Note
+
+	// Type is the type of the chord.
+	Type Type
+
+	// Bass is the lowest note. If its pitch class differs from the root, the chord is inverted or a slash chord.
+	Bass midi.Note
+
+	// the spelling of the root and the bass in the parsed symbol, e.g. "F#" instead of "Gb"
+	rootName, bassName string
+}
+
+// Inversion returns 0 for a chord in root position, 1 if the bass is the second chord tone (e.g. the third),
+// 2 if it is the third chord tone (e.g. the fifth) and so on.
+// It returns -1, if the bass is not a chord tone (slash chord).
+func (c Chord) Inversion() int {
+	bass := (int(c.Bass.Base()) - int(c.Root.Base()) + 12) % 12
+
+	for i, iv := range c.Type.Intervals {
+		if (int(iv)%12+12)%12 == bass {
+			return i
+		}
+	}
+	return -1
+}
+
+// PitchClasses returns the pitch classes of the chord tones (0 = C - 11 = B) in the order of the intervals.
+func (c Chord) PitchClasses() []uint8 {
+	res := make([]uint8, len(c.Type.Intervals))
+	for i, iv := range c.Type.Intervals {
+		res[i] = uint8((int(c.Root.Base()) + int(iv)%12 + 12) % 12)
+	}
+	return res
+}
+
+// String returns the chord symbol, e.g. "Ebm7" or "C/E". The root and the bass are spelled like in the parsed
+// symbol, otherwise with flats.
+func (c Chord) String() string {
+	var bd strings.Builder
+	bd.WriteString(spell(c.Root, c.rootName))
+	bd.WriteString(c.Type.Symbol)
+
+	if !c.Bass.Is(c.Root) {
+		bd.WriteString("/")
+		bd.WriteString(spell(c.Bass, c.bassName))
+	}
+
+	return bd.String()
+}
+
+// spell returns the given name of the note, if it matches the pitch class, otherwise the name with flats
+func spell(n midi.Note, name string) string {
+	if p, rest, ok := parseRoot(name); ok && rest == "" && p.Is(n) {
+		return name
+	}
+	return n.Name()
+}
@@ -0,0 +1,344 @@
+package chord
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		notes      []midi.Note
+		chord      string
+		inversion  int
+		confidence float64
+	}{
+		{[]midi.Note{60, 64, 67}, "C", 0, 1},
+		{[]midi.Note{52, 60, 67}, "C/E", 1, 1},
+		{[]midi.Note{55, 64, 72}, "C/G", 2, 1},
+		{[]midi.Note{57, 60, 64, 67}, "Am7", 0, 1},
+		{[]midi.Note{60, 64, 67, 69}, "C6", 0, 1},
+		{[]midi.Note{63, 66, 70, 73}, "Ebm7", 0, 1},
+		{[]midi.Note{60, 64, 70}, "C7", 0, 0.95},
+		{[]midi.Note{60, 67}, "C5", 0, 1},
+		{[]midi.Note{50, 60, 64, 67}, "Cadd9/D", 3, 1},
+		{[]midi.Note{60, 64}, "C", 0, 2.0 / 3},
+		{[]midi.Note{62, 65, 69, 72, 76}, "Dm9", 0, 1},
+	}
+
+	for _, test := range tests {
+		candidates := Detect(test.notes...)
+
+		if len(candidates) == 0 {
+			t.Errorf("Detect(%v) returned no candidates", test.notes)
+			continue
+		}
+
+		best := candidates[0]
+
+		if got, want := best.String(), test.chord; got != want {
+			t.Errorf("Detect(%v)[0] = %q; want %q", test.notes, got, want)
+		}
+
+		if got, want := best.Inversion(), test.inversion; got != want {
+			t.Errorf("Detect(%v)[0].Inversion() = %v; want %v", test.notes, got, want)
+		}
+
+		if got, want := best.Confidence, test.confidence; got != want {
+			t.Errorf("Detect(%v)[0].Confidence = %v; want %v", test.notes, got, want)
+		}
+
+		for i := 1; i < len(candidates); i++ {
+			if candidates[i].Confidence > candidates[i-1].Confidence {
+				t.Errorf("Detect(%v) candidates are not ordered by confidence", test.notes)
+			}
+		}
+	}
+
+	if got := Detect(60, 72); got != nil {
+		t.Errorf("Detect(60, 72) = %v; want nil", got)
+	}
+}
+
+func TestDetector(t *testing.T) {
+	d := NewDetector()
+
+	tests := []struct {
+		msg     midi.Message
+		changed bool
+		chord   string
+	}{
+		{midi.NoteOn(0, 60, 100), false, ""},
+		{midi.NoteOn(0, 64, 100), true, "C"},
+		{midi.NoteOn(1, 67, 100), false, "C"},
+		{midi.NoteOn(0, 72, 100), false, "C"},
+		{midi.NoteOff(0, 60), true, "C/E"},
+		{midi.NoteOn(0, 69, 100), true, "C6/E"},
+		{midi.NoteOff(0, 64), true, "Am7/G"},
+		{midi.NoteOff(1, 67), true, "Am"},
+		{midi.NoteOff(0, 69), true, ""},
+	}
+
+	for i, test := range tests {
+		changed := d.Track(test.msg)
+
+		var got string
+		if c, ok := d.Chord(); ok {
+			got = c.String()
+		}
+
+		if got != test.chord || changed != test.changed {
+			t.Errorf("[%v] Track(%s) = %v, chord %q; want %v, chord %q", i, test.msg, changed, got, test.changed, test.chord)
+		}
+	}
+}
+
+func TestDetectorConcurrent(t *testing.T) {
+	d := NewDetector()
+
+	var wg sync.WaitGroup
+
+	for ch := uint8(0); ch < 3; ch++ {
+		wg.Add(1)
+		go func(ch uint8) {
+			defer wg.Done()
+			key := 60 + 4*ch
+			for i := 0; i < 100; i++ {
+				d.Track(midi.NoteOn(ch, key, 100))
+				d.Track(midi.NoteOff(ch, key))
+			}
+			d.Track(midi.NoteOn(ch, key, 100))
+		}(ch)
+	}
+
+	wg.Wait()
+
+	if c, ok := d.Chord(); !ok || c.String() != "Caug" {
+		t.Errorf("Chord() = %v, %v; want Caug", c, ok)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	var tr smf.Track
+	tr.Add(0, smf.MetaTrackSequenceName("piano"))
+	tr.Add(0, midi.NoteOn(0, 60, 100), midi.NoteOn(0, 64, 100), midi.NoteOn(0, 67, 100))
+	tr.Add(96, midi.NoteOff(0, 60), midi.NoteOff(0, 64), midi.NoteOff(0, 67))
+	tr.Add(0, midi.NoteOn(0, 57, 100), midi.NoteOn(0, 60, 100), midi.NoteOn(0, 64, 100), midi.NoteOn(0, 67, 100))
+	tr.Add(96, midi.NoteOff(0, 57), midi.NoteOff(0, 60), midi.NoteOff(0, 64), midi.NoteOff(0, 67))
+	tr.Add(96, midi.NoteOn(0, 65, 100), midi.NoteOn(0, 69, 100), midi.NoteOn(0, 74, 100))
+	tr.Add(48, midi.NoteOn(0, 62, 100))
+	tr.Add(48, midi.NoteOff(0, 62), midi.NoteOff(0, 65), midi.NoteOff(0, 69), midi.NoteOff(0, 74))
+	tr.Close(0)
+
+	var got []string
+	for _, r := range Segment(tr) {
+		got = append(got, fmt.Sprintf("%v-%v %s", r.Start, r.End, r.Chord))
+	}
+
+	want := []string{"0-96 C", "96-192 Am7", "288-336 Dm/F", "336-384 Dm"}
+
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Segment()\ngot:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		symbol string
+		chord  string
+		notes  string
+	}{
+		{"C", "C", "[C4 E4 G4]"},
+		{"Cm", "Cm", "[C4 Eb4 G4]"},
+		{"Cmin7", "Cm7", "[C4 Eb4 G4 Bb4]"},
+		{"Cmaj7/G", "Cmaj7/G", "[G3 B3 C4 E4]"},
+		{"CΔ", "Cmaj7", "[C4 E4 G4 B4]"},
+		{"F#m7b5", "F#m7b5", "[Gb4 A4 C5 E5]"},
+		{"F#ø", "F#m7b5", "[Gb4 A4 C5 E5]"},
+		{"Bb13(#11)", "Bb13(#11)", "[Bb4 D5 F5 Ab5 C6 E6 G6]"},
+		{"Ebsus4", "Ebsus4", "[Eb4 Ab4 Bb4]"},
+		{"Adim7", "Adim7", "[A4 C5 Eb5 Gb5]"},
+		{"D6/9", "D6/9", "[D4 Gb4 A4 B4 E5]"},
+		{"G7sus4", "G7sus4", "[G4 C5 D5 F5]"},
+		{"Cm(maj7)", "Cmmaj7", "[C4 Eb4 G4 B4]"},
+		{"E7#9", "E7#9", "[E4 Ab4 B4 D5 G5]"},
+		{"C/E", "C/E", "[E3 G3 C4]"},
+		{"C/D", "C/D", "[D3 C4 E4 G4]"},
+		{"F#/A#", "F#/A#", "[Bb3 Db4 Gb4]"},
+		{"A5", "A5", "[A4 E5]"},
+		{"Caug", "Caug", "[C4 E4 Ab4]"},
+		{"C7(b9,b13)", "C7(b9,b13)", "[C4 E4 G4 Bb4 Db5 Ab5]"},
+	}
+
+	for _, test := range tests {
+		c, err := Parse(test.symbol)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", test.symbol, err)
+			continue
+		}
+
+		if got, want := c.String(), test.chord; got != want {
+			t.Errorf("Parse(%q) = %q; want %q", test.symbol, got, want)
+		}
+
+		notes, err := c.Voice(Close)
+		if err != nil {
+			t.Errorf("Parse(%q).Voice(Close) returned error: %s", test.symbol, err)
+			continue
+		}
+
+		if got, want := fmt.Sprint(notes), test.notes; got != want {
+			t.Errorf("Parse(%q).Voice(Close) = %s; want %s", test.symbol, got, want)
+		}
+	}
+
+	for _, symbol := range []string{"", "H7", "Cfoo", "c"} {
+		if _, err := Parse(symbol); !errors.Is(err, ErrInvalidSymbol) {
+			t.Errorf("Parse(%q) = %v; want ErrInvalidSymbol", symbol, err)
+		}
+	}
+}
+
+func TestVoice(t *testing.T) {
+	cmaj7, _ := Parse("Cmaj7")
+	c, _ := Parse("C")
+	f, _ := Parse("F")
+	g7, _ := Parse("G7")
+	ce, _ := Parse("C/E")
+	cmaj7g, _ := Parse("Cmaj7/G")
+	cd, _ := Parse("C/D")
+	c7bb, _ := Parse("C7/Bb")
+
+	tests := []struct {
+		chord   Chord
+		voicing Voicing
+		opts    []VoiceOption
+		notes   string
+	}{
+		{cmaj7, Close, nil, "[C4 E4 G4 B4]"},
+		{cmaj7, Drop2, nil, "[G3 C4 E4 B4]"},
+		{cmaj7, Spread, nil, "[C4 G4 E5 B5]"},
+		{cmaj7, Close, []VoiceOption{Range(60, 72)}, "[C5 E5 G5 B5]"},
+		{cmaj7, Spread, []VoiceOption{Range(60, 72)}, "[C5 E5 G5 B5]"},
+		{f, Close, []VoiceOption{LeadFrom(60, 64, 67)}, "[C5 F5 A5]"},
+		{g7, Close, []VoiceOption{LeadFrom(60, 64, 67)}, "[B4 D5 F5 G5]"},
+		{c, Close, []VoiceOption{LeadFrom(62, 65, 71), Range(60, 84)}, "[E5 G5 C6]"},
+		{ce, Close, nil, "[E3 G3 C4]"},
+		{ce, Drop2, nil, "[E3 G3 C4]"},
+		{ce, Spread, nil, "[E3 G3 C5]"},
+		{cmaj7g, Close, nil, "[G3 B3 C4 E4]"},
+		{cmaj7g, Drop2, nil, "[G2 C3 B3 E4]"},
+		{cmaj7g, Spread, nil, "[G3 B3 E4 C5]"},
+		{cmaj7g, Close, []VoiceOption{Range(48, 67)}, "[G4 B4 C5 E5]"},
+		{cmaj7g, Drop2, []VoiceOption{Range(55, 67)}, "[G4 B4 C5 E5]"},
+		{cmaj7g, Close, []VoiceOption{Range(60, 79)}, "[G5 B5 C6 E6]"},
+		{cd, Close, nil, "[D3 C4 E4 G4]"},
+		{cd, Drop2, nil, "[D3 E3 C4 G4]"},
+		{cd, Spread, nil, "[D3 C4 G4 E5]"},
+		{cd, Drop2, []VoiceOption{LeadFrom(60, 64, 67)}, "[D4 E4 C5 G5]"},
+		{cd, Spread, []VoiceOption{Range(48, 60)}, "[D4 E4 G4 C5]"},
+	}
+
+	for i, test := range tests {
+		notes, err := test.chord.Voice(test.voicing, test.opts...)
+		if err != nil {
+			t.Errorf("[%v] %s.Voice(%v) returned error: %s", i, test.chord, test.voicing, err)
+			continue
+		}
+
+		if got, want := fmt.Sprint(notes), test.notes; got != want {
+			t.Errorf("[%v] %s.Voice(%v) = %s; want %s", i, test.chord, test.voicing, got, want)
+		}
+	}
+
+	// chords that don't fit into the range
+	errTests := []struct {
+		chord   Chord
+		voicing Voicing
+		opts    []VoiceOption
+	}{
+		{cmaj7g, Close, []VoiceOption{Range(60, 72)}},
+		{cmaj7g, Drop2, []VoiceOption{Range(48, 60)}},
+		{c7bb, Close, []VoiceOption{Range(60, 72)}},
+		{c7bb, Spread, []VoiceOption{Range(70, 75)}},
+		{cmaj7, Close, []VoiceOption{Range(60, 66)}},
+	}
+
+	for i, test := range errTests {
+		if notes, err := test.chord.Voice(test.voicing, test.opts...); !errors.Is(err, ErrRange) {
+			t.Errorf("[%v] %s.Voice(%v) = %s, %v; want ErrRange", i, test.chord, test.voicing, notes, err)
+		}
+	}
+
+	// the bass stays the lowest note with every voicing and option
+	for _, symbol := range []string{"C/E", "C/G", "Cmaj7/G", "Cmaj7/B", "C/D", "Am7/G", "G7/F"} {
+		chord, _ := Parse(symbol)
+
+		for _, v := range []Voicing{Close, Drop2, Spread} {
+			for _, opts := range [][]VoiceOption{nil, {Range(48, 60)}, {Range(60, 84)}, {LeadFrom(60, 64, 67)}} {
+				notes, err := chord.Voice(v, opts...)
+				if errors.Is(err, ErrRange) {
+					continue
+				}
+				if err != nil {
+					t.Errorf("%s.Voice(%v) returned error: %s", symbol, v, err)
+					continue
+				}
+				for _, pc := range chord.tones() {
+					if !hasPitchClass(notes, chord.Root.Base()+uint8(pc)) {
+						t.Errorf("%s.Voice(%v) = %v; missing chord tones", symbol, v, notes)
+						break
+					}
+				}
+				for _, n := range notes[1:] {
+					if !notes[0].Is(chord.Bass) || n <= notes[0] {
+						t.Errorf("%s.Voice(%v) = %s; bass %s is not the lowest note", symbol, v, notes, chord.Bass.Name())
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func hasPitchClass(notes Notes, pc uint8) bool {
+	for _, n := range notes {
+		if n.Base() == pc%12 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNotes(t *testing.T) {
+	c, _ := Parse("C")
+	notes, _ := c.Voice(Close)
+
+	var tr smf.Track
+	notes.AddTo(&tr, 10, 96, 1, 100)
+	tr.Close(0)
+
+	var got []string
+	for _, ev := range tr {
+		got = append(got, fmt.Sprintf("%v %s", ev.Delta, ev.Message))
+	}
+
+	want := []string{
+		"10 NoteOn channel: 1 key: 48 velocity: 100",
+		"0 NoteOn channel: 1 key: 52 velocity: 100",
+		"0 NoteOn channel: 1 key: 55 velocity: 100",
+		"96 NoteOff channel: 1 key: 48",
+		"0 NoteOff channel: 1 key: 52",
+		"0 NoteOff channel: 1 key: 55",
+		"0 MetaEndOfTrack",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("AddTo()\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,185 @@
+package chord
+
+import (
+	"sort"
+	"sync"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// MinConfidence is the minimal confidence of the candidates returned by Detect.
+var MinConfidence = 0.5
+
+// Candidate is a recognized chord with the confidence of the recognition (0-1).
+type Candidate struct {
+	Chord
+	Confidence float64
+}
+
+// Detect returns the chords that match the given notes, ordered by confidence (best first).
+// A chord matches better, the more of the notes are chord tones and the more chord tones are part of the notes.
+// A missing fifth is only a small penalty for chords of four or more tones.
+// On equal confidence, chords whose root is the bass are preferred, then chords with less tones.
+// At least two different pitch classes are needed to recognize a chord.
+func Detect(notes ...midi.Note) (candidates []Candidate) {
+	var held [12]bool
+	var numHeld int
+	var bass midi.Note = 127
+
+	for _, n := range notes {
+		if !held[n.Base()] {
+			held[n.Base()] = true
+			numHeld++
+		}
+		if n < bass {
+			bass = n
+		}
+	}
+
+	if numHeld < 2 {
+		return nil
+	}
+
+	for pc := 0; pc < 12; pc++ {
+		if !held[pc] {
+			continue
+		}
+
+		root := lowest(notes, uint8(pc))
+
+		for _, typ := range Types {
+			conf := confidence(held, numHeld, pc, typ)
+			if conf >= MinConfidence {
+				candidates = append(candidates, Candidate{Chord: Chord{Root: root, Type: typ, Bass: bass}, Confidence: conf})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		ca, cb := candidates[a], candidates[b]
+
+		if ca.Confidence != cb.Confidence {
+			return ca.Confidence > cb.Confidence
+		}
+
+		if ra, rb := ca.Bass.Is(ca.Root), cb.Bass.Is(cb.Root); ra != rb {
+			return ra
+		}
+
+		return len(ca.Type.Intervals) < len(cb.Type.Intervals)
+	})
+
+	return
+}
+
+func lowest(notes []midi.Note, pc uint8) midi.Note {
+	var res midi.Note = 127
+	for _, n := range notes {
+		if n.Base() == pc && n < res {
+			res = n
+		}
+	}
+	return res
+}
+
+func confidence(held [12]bool, numHeld int, root int, typ Type) float64 {
+	pcs := typ.pitchClasses()
+
+	var size, matched int
+	for iv, is := range pcs {
+		if !is {
+			continue
+		}
+		size++
+		if held[(root+iv)%12] {
+			matched++
+		}
+	}
+
+	extra := numHeld - matched
+	var penalty float64
+
+	// missing fifth
+	if size >= 4 && pcs[7] && !held[(root+7)%12] {
+		size--
+		penalty = 0.05
+	}
+
+	return float64(matched)/float64(size+extra) - penalty
+}
+
+// Detector recognizes the chord of the sounding notes of a live stream of messages (see midi.NoteTracker).
+// A Detector is threadsafe.
+type Detector struct {
+	mx      sync.Mutex
+	tracker *midi.NoteTracker
+	last    string
+}
+
+// NewDetector returns a new Detector.
+func NewDetector() *Detector {
+	return &Detector{tracker: midi.NewNoteTracker()}
+}
+
+// Track updates the sounding notes with the given message and returns true, if the best chord has changed.
+func (d *Detector) Track(msg midi.Message) (changed bool) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	d.tracker.Track(msg)
+
+	var s string
+	if c, ok := d.chord(); ok {
+		s = c.String()
+	}
+
+	changed = s != d.last
+	d.last = s
+	return
+}
+
+// Notes returns the sounding notes of all channels in ascending order.
+func (d *Detector) Notes() []midi.Note {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return d.notes()
+}
+
+// Candidates returns the chord candidates of the sounding notes (see Detect).
+func (d *Detector) Candidates() []Candidate {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return Detect(d.notes()...)
+}
+
+// Chord returns the best chord of the sounding notes and true, if there is one.
+func (d *Detector) Chord() (Chord, bool) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	return d.chord()
+}
+
+func (d *Detector) notes() (notes []midi.Note) {
+	var sounding [128]bool
+
+	for ch := uint8(0); ch < 16; ch++ {
+		for _, key := range d.tracker.Sounding(ch) {
+			sounding[key] = true
+		}
+	}
+
+	for key, is := range sounding {
+		if is {
+			notes = append(notes, midi.Note(key))
+		}
+	}
+	return
+}
+
+func (d *Detector) chord() (Chord, bool) {
+	candidates := Detect(d.notes()...)
+	if len(candidates) == 0 {
+		return Chord{}, false
+	}
+	return candidates[0].Chord, true
+}
@@ -0,0 +1,237 @@
+package chord
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// ErrInvalidSymbol is returned, if a chord symbol can't be parsed
+var ErrInvalidSymbol = errors.New("invalid chord symbol")
+
+// Parse parses a chord symbol, like "C", "Cmaj7/G", "F#m7b5", "Bb13(#11)", "Ebsus4", "Adim7" or "D6/9".
+// The root of the returned chord is within the octave below the middle C (48-59) and the bass of a
+// slash chord is the next lower note of its pitch class. If the chord tones match one of the Types,
+// its symbol is used, e.g. "Cmin7" results in "Cm7".
+//
+// Supported qualities are maj, M, Δ, m, min, -, dim, °, ø, aug and +, followed by 5, 6, 6/9, 7, 9, 11 or 13
+// and modifiers like sus2, sus4, add9, b5, #5, b9, #9, #11, b13, no3 and no5 (optionally in parentheses).
+func Parse(symbol string) (Chord, error) {
+	s := strings.TrimSpace(symbol)
+
+	root, rest, ok := parseRoot(s)
+	if !ok {
+		return Chord{}, fmt.Errorf("%w: %q", ErrInvalidSymbol, symbol)
+	}
+
+	rootName := s[:len(s)-len(rest)]
+	root = 48 + root%12
+	bass := root
+	var bassName string
+
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		if b, r, ok := parseRoot(rest[i+1:]); ok && r == "" {
+			bassName = rest[i+1:]
+			rest = rest[:i]
+			if !b.Is(root) {
+				bass = root - 12 + midi.Note((int(b)-int(root)+120)%12)
+			}
+		}
+	}
+
+	intervals, err := parseIntervals(rest)
+	if err != nil {
+		return Chord{}, fmt.Errorf("%w: %q: %s", ErrInvalidSymbol, symbol, err)
+	}
+
+	typ := Type{Symbol: rest, Intervals: intervals}
+
+	for _, t := range Types {
+		if sameIntervals(t.Intervals, intervals) {
+			typ = t
+			break
+		}
+	}
+
+	return Chord{Root: root, Type: typ, Bass: bass, rootName: rootName, bassName: bassName}, nil
+}
+
+// parseRoot parses a note name without octave, like C, F# or Bb, at the beginning of s
+func parseRoot(s string) (n midi.Note, rest string, ok bool) {
+	if s == "" {
+		return 0, s, false
+	}
+
+	letter := strings.IndexByte("CDEFGAB", s[0])
+	if letter < 0 {
+		return 0, s, false
+	}
+
+	n = midi.Note([]int{0, 2, 4, 5, 7, 9, 11}[letter] + 12)
+	rest = s[1:]
+
+	switch {
+	case consume(&rest, "#", "♯"):
+		n++
+	case consume(&rest, "b", "♭"):
+		n--
+	}
+
+	return n, rest, true
+}
+
+// consume removes the first matching of the given prefixes from s and returns, if there was one
+func consume(s *string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(*s, p) {
+			*s = (*s)[len(p):]
+			return true
+		}
+	}
+	return false
+}
+
+// parseIntervals parses the suffix of a chord symbol after the root
+func parseIntervals(s string) ([]midi.Interval, error) {
+	third, fifth, seventh := 4, 7, -1
+	var maj, dim bool
+	ext := map[int]bool{}
+
+	// quality
+	switch {
+	case consume(&s, "maj", "Maj", "ma", "M"):
+		maj = true
+	case consume(&s, "Δ", "∆"):
+		maj = true
+		seventh = 11
+	case consume(&s, "min", "mi", "m", "-"):
+		third = 3
+		if consume(&s, "maj", "Maj", "M", "Δ", "∆") {
+			maj = true
+			seventh = 11
+		}
+	case consume(&s, "dim", "°", "o"):
+		third, fifth = 3, 6
+		dim = true
+	case consume(&s, "ø"):
+		third, fifth, seventh = 3, 6, 10
+	case consume(&s, "aug", "+"):
+		fifth = 8
+	}
+
+	seventhOf := func() int {
+		switch {
+		case maj:
+			return 11
+		case dim:
+			return 9
+		default:
+			return 10
+		}
+	}
+
+	// extension
+	switch {
+	case consume(&s, "13"):
+		seventh = seventhOf()
+		ext[14], ext[21] = true, true
+	case consume(&s, "11"):
+		seventh = seventhOf()
+		ext[14], ext[17] = true, true
+	case consume(&s, "9"):
+		seventh = seventhOf()
+		ext[14] = true
+	case consume(&s, "7"):
+		seventh = seventhOf()
+	case consume(&s, "6/9", "69"):
+		ext[9], ext[14] = true, true
+	case consume(&s, "6"):
+		ext[9] = true
+	case third == 4 && fifth == 7 && !maj && consume(&s, "5"):
+		third = -1
+	}
+
+	// modifiers
+	for s != "" {
+		switch {
+		case consume(&s, "(", ")", ",", " "):
+		case consume(&s, "sus2"):
+			third = 2
+		case consume(&s, "sus4", "sus"):
+			third = 5
+		case consume(&s, "add2"):
+			ext[2] = true
+		case consume(&s, "add4"):
+			ext[5] = true
+		case consume(&s, "add9", "9"):
+			ext[14] = true
+		case consume(&s, "add11", "11"):
+			ext[17] = true
+		case consume(&s, "add13", "13"):
+			ext[21] = true
+		case consume(&s, "maj7", "M7", "Δ7", "Δ"):
+			seventh = 11
+		case consume(&s, "b5", "-5"):
+			fifth = 6
+		case consume(&s, "#5", "+5"):
+			fifth = 8
+		case consume(&s, "b9", "-9"):
+			delete(ext, 14)
+			ext[13] = true
+		case consume(&s, "#9", "+9"):
+			delete(ext, 14)
+			ext[15] = true
+		case consume(&s, "#11", "+11"):
+			delete(ext, 17)
+			ext[18] = true
+		case consume(&s, "b13", "-13"):
+			delete(ext, 21)
+			ext[20] = true
+		case consume(&s, "no3", "omit3"):
+			third = -1
+		case consume(&s, "no5", "omit5"):
+			fifth = -1
+		default:
+			return nil, fmt.Errorf("unknown %q", s)
+		}
+	}
+
+	ivs := []midi.Interval{midi.Unison}
+	for _, iv := range []int{third, fifth, seventh} {
+		if iv >= 0 {
+			ivs = append(ivs, midi.Interval(iv))
+		}
+	}
+
+	for iv := range ext {
+		ivs = append(ivs, midi.Interval(iv))
+	}
+
+	sort.Slice(ivs, func(a, b int) bool {
+		return ivs[a] < ivs[b]
+	})
+
+	res := ivs[:1]
+	for _, iv := range ivs[1:] {
+		if iv != res[len(res)-1] {
+			res = append(res, iv)
+		}
+	}
+
+	return res, nil
+}
+
+func sameIntervals(a, b []midi.Interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
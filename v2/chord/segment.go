@@ -0,0 +1,56 @@
+package chord
+
+import (
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// Region is a part of a track with the same chord.
+type Region struct {
+	// Start and End are the absolute ticks of the region.
+	Start, End uint64
+
+	Candidate
+}
+
+// Segment splits the given track into regions of the same chord (see Detect).
+// The chord is evaluated after the last message of each tick.
+// Parts of the track without a chord are not part of the result.
+func Segment(tr smf.Track) (regions []Region) {
+	d := NewDetector()
+
+	var abs uint64
+	var current *Region
+
+	for i, ev := range tr {
+		abs += uint64(ev.Delta)
+		d.Track(midi.Message(ev.Message))
+
+		if i+1 < len(tr) && tr[i+1].Delta == 0 {
+			continue
+		}
+
+		candidates := d.Candidates()
+
+		if current != nil && len(candidates) > 0 && candidates[0].String() == current.String() {
+			continue
+		}
+
+		if current != nil {
+			current.End = abs
+			regions = append(regions, *current)
+			current = nil
+		}
+
+		if len(candidates) > 0 {
+			current = &Region{Start: abs, Candidate: candidates[0]}
+		}
+	}
+
+	if current != nil {
+		current.End = abs
+		regions = append(regions, *current)
+	}
+
+	return
+}
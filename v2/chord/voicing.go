@@ -0,0 +1,322 @@
+package chord
+
+import (
+	"errors"
+	"sort"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// ErrRange is returned, if the chord tones don't fit into the range of the voicing (see Range)
+var ErrRange = errors.New("chord tones don't fit into the range")
+
+// Voicing is an arrangement of the chord tones.
+type Voicing int
+
+const (
+	// Close stacks the chord tones within the smallest range above the bass, e.g. C4 E4 G4 B4 for Cmaj7.
+	Close Voicing = iota
+
+	// Drop2 is the close voicing with the second highest voice dropped by an octave, e.g. G3 C4 E4 B4 for Cmaj7.
+	Drop2
+
+	// Spread is the close voicing with every second voice raised by an octave, e.g. C4 G4 E5 B5 for Cmaj7.
+	Spread
+)
+
+// voiceOptions are the options for voicing a chord
+type voiceOptions struct {
+	lowest, highest int
+	previous        []midi.Note
+}
+
+// VoiceOption is an option for voicing a chord
+type VoiceOption func(*voiceOptions)
+
+// Range sets the range of the voiced notes. The voicing is transposed by octaves to fit into the range.
+// If the voicing is wider than the range, single notes are moved by octaves into the range, keeping the bass of
+// inverted chords and slash chords the lowest note. If not every chord tone fits into the range, Voice returns ErrRange.
+func Range(lowest, highest midi.Note) VoiceOption {
+	return func(o *voiceOptions) {
+		o.lowest, o.highest = int(lowest), int(highest)
+	}
+}
+
+// LeadFrom sets the notes of the previous chord for voice leading: Of all inversions and octave positions of the
+// voicing, the one with the smallest movement of the voices is chosen. The bass of inverted chords and slash chords
+// stays the lowest note.
+func LeadFrom(previous ...midi.Note) VoiceOption {
+	return func(o *voiceOptions) {
+		o.previous = previous
+	}
+}
+
+// Voice returns the notes of the chord with the given voicing. Without options the voicing starts at the bass.
+// Tones that occur more than once in the chord type (e.g. with a 9 and a 2) are only voiced once.
+// The voicing applies to the tones above the bass of inverted chords and slash chords, the bass is always the lowest note.
+// No chord tone is left out: ErrRange is returned, if the tones don't fit into the range (see Range).
+func (c Chord) Voice(v Voicing, opts ...VoiceOption) (Notes, error) {
+	o := &voiceOptions{lowest: 0, highest: 127}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	pcs := c.tones()
+	if len(pcs) == 0 {
+		return nil, nil
+	}
+
+	inv := c.Inversion()
+
+	rotations := []int{0}
+	switch {
+	case inv > 0:
+		// the tones above the bass are voiced from the one following the bass
+	case o.previous != nil:
+		rotations = make([]int, len(pcs))
+		for i := range rotations {
+			rotations[i] = i
+		}
+	}
+
+	var best []int
+	var bestCost, bestShift int
+
+	for _, rot := range rotations {
+		notes := c.voice(v, pcs, rot)
+
+		for shift := -10; shift <= 10; shift++ {
+			if !inRange(notes, shift*12, o.lowest, o.highest) {
+				continue
+			}
+
+			cost := abs(shift)
+			if o.previous != nil {
+				cost = distance(notes, shift*12, o.previous)
+			}
+
+			if best == nil || cost < bestCost || (cost == bestCost && abs(shift) < abs(bestShift)) {
+				best, bestCost, bestShift = notes, cost, shift
+			}
+		}
+	}
+
+	if best == nil {
+		return fold(c.voice(v, pcs, rotations[0]), inv != 0, o.lowest, o.highest)
+	}
+
+	res := make(Notes, len(best))
+	for i, n := range best {
+		res[i] = midi.Note(n + bestShift*12)
+	}
+	return res, nil
+}
+
+// tones returns the different pitch classes of the chord tones relative to the root in the order of the intervals
+func (c Chord) tones() (pcs []int) {
+	var has [12]bool
+	for _, iv := range c.Type.Intervals {
+		pc := (int(iv)%12 + 12) % 12
+		if !has[pc] {
+			has[pc] = true
+			pcs = append(pcs, pc)
+		}
+	}
+	return
+}
+
+// voice returns the voicing of the chord tones, starting with the tone of the given index above the bass.
+// The bass of inverted chords and slash chords is added below the voiced upper structure.
+func (c Chord) voice(v Voicing, pcs []int, rot int) (notes []int) {
+	pinned := c.Inversion() != 0
+	root := int(c.Root)
+	bass := int(c.Bass)
+
+	upper := pcs
+	if pinned {
+		// the tones above the bass, starting with the one following the bass
+		bpc := ((bass-root)%12 + 12) % 12
+		for i, pc := range pcs {
+			if pc == bpc {
+				upper = append(append([]int{}, pcs[i+1:]...), pcs[:i]...)
+				rot = 0
+				break
+			}
+		}
+	}
+
+	// the lowest note of the first tone above (or at) the bass
+	n := root + upper[rot%len(upper)]
+	for n-12 >= bass {
+		n -= 12
+	}
+	for n < bass || (pinned && n == bass) {
+		n += 12
+	}
+	notes = append(notes, n)
+
+	for i := 1; i < len(upper); i++ {
+		pc := (root + upper[(rot+i)%len(upper)]) % 12
+		n++
+		for n%12 != pc {
+			n++
+		}
+		notes = append(notes, n)
+	}
+
+	switch v {
+	case Drop2:
+		if len(notes) >= 3 {
+			notes[len(notes)-2] -= 12
+		}
+	case Spread:
+		for i := 1; i < len(notes); i += 2 {
+			notes[i] += 12
+		}
+	}
+
+	sort.Ints(notes)
+
+	if pinned {
+		for bass >= notes[0] {
+			bass -= 12
+		}
+		for bass+12 < notes[0] {
+			bass += 12
+		}
+		notes = append([]int{bass}, notes...)
+	}
+
+	return notes
+}
+
+func inRange(notes []int, shift, lowest, highest int) bool {
+	return notes[0]+shift >= lowest && notes[len(notes)-1]+shift <= highest
+}
+
+// distance returns the sum of the distances of each note to the nearest previous note and vice versa
+func distance(notes []int, shift int, previous []midi.Note) (d int) {
+	nearest := func(n int, others []int) int {
+		min := -1
+		for _, o := range others {
+			if dist := abs(n - o); min < 0 || dist < min {
+				min = dist
+			}
+		}
+		return min
+	}
+
+	var prev, shifted []int
+	for _, p := range previous {
+		prev = append(prev, int(p))
+	}
+	for _, n := range notes {
+		shifted = append(shifted, n+shift)
+	}
+
+	for _, n := range shifted {
+		d += nearest(n, prev)
+	}
+	for _, p := range prev {
+		d += nearest(p, shifted)
+	}
+	return
+}
+
+// fold moves the notes by octaves into the range and removes duplicates. If the first note is pinned,
+// it stays the lowest note: it is placed in the lowest octave of the range that leaves room for the other notes.
+// ErrRange is returned, if a note can't be moved into the range.
+func fold(notes []int, pinned bool, lowest, highest int) (Notes, error) {
+	if !pinned {
+		return foldAbove(notes, lowest, highest)
+	}
+
+	bass := notes[0]
+	for bass > lowest {
+		bass -= 12
+	}
+	for bass < lowest {
+		bass += 12
+	}
+
+	for ; bass <= highest; bass += 12 {
+		if upper, err := foldAbove(notes[1:], bass+1, highest); err == nil {
+			return append(Notes{midi.Note(bass)}, upper...), nil
+		}
+	}
+
+	return nil, ErrRange
+}
+
+// foldAbove moves each note by octaves into the range and returns the different notes in ascending order.
+func foldAbove(notes []int, lowest, highest int) (res Notes, err error) {
+	var has [128]bool
+
+	for _, n := range notes {
+		for n > highest {
+			n -= 12
+		}
+		for n < lowest {
+			n += 12
+		}
+		if n > highest || n < 0 || n > 127 {
+			return nil, ErrRange
+		}
+		if has[n] {
+			continue
+		}
+		has[n] = true
+		res = append(res, midi.Note(n))
+	}
+
+	sort.Slice(res, func(a, b int) bool {
+		return res[a] < res[b]
+	})
+	return
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// Notes are the notes of a voiced chord.
+type Notes []midi.Note
+
+// NoteOn returns the NoteOn messages of the notes.
+func (n Notes) NoteOn(channel, velocity uint8) []midi.Message {
+	msgs := make([]midi.Message, len(n))
+	for i, key := range n {
+		msgs[i] = midi.NoteOn(channel, key.Value(), velocity)
+	}
+	return msgs
+}
+
+// NoteOff returns the NoteOff messages of the notes.
+func (n Notes) NoteOff(channel uint8) []midi.Message {
+	msgs := make([]midi.Message, len(n))
+	for i, key := range n {
+		msgs[i] = midi.NoteOff(channel, key.Value())
+	}
+	return msgs
+}
+
+// AddTo adds the NoteOn messages of the notes after deltaticks and their NoteOff messages after the given duration to the track.
+func (n Notes) AddTo(tr *smf.Track, deltaticks, duration uint32, channel, velocity uint8) {
+	if len(n) == 0 {
+		return
+	}
+
+	for _, msg := range n.NoteOn(channel, velocity) {
+		tr.Add(deltaticks, msg)
+		deltaticks = 0
+	}
+
+	for _, msg := range n.NoteOff(channel) {
+		tr.Add(duration, msg)
+		duration = 0
+	}
+}
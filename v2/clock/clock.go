@@ -0,0 +1,359 @@
+package clock
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// PPQN is the number of timing clock messages per quarter note.
+const PPQN = 24
+
+// clocksPerBeat is the number of timing clock messages per MIDI beat (a sixteenth note) of the song position pointer.
+const clocksPerBeat = PPQN / 4
+
+// ErrInvalidTempo is the error for a tempo that is not positive.
+var ErrInvalidTempo = errors.New("invalid tempo")
+
+// Option is an option for a Clock.
+type Option func(*Clock)
+
+// FreeRunning lets the Clock send the timing clock messages even while the song is stopped, so that the devices can
+// follow the tempo all the time. Then the song starts with the next timing clock message after the start message.
+func FreeRunning() Option {
+	return func(c *Clock) {
+		c.freeRunning = true
+	}
+}
+
+// HandleError sets an error handler for the errors of sending the timing clock messages.
+func HandleError(cb func(error)) Option {
+	return func(c *Clock) {
+		c.onErr = cb
+	}
+}
+
+// ramp is a linear change of the tempo, based on the scheduled time
+type ramp struct {
+	from, to      float64
+	start, length float64 // in nanoseconds since the origin
+}
+
+// Clock is a MIDI clock master that sends timing clock messages to out ports (see New).
+// The methods of a Clock may be called from different goroutines.
+type Clock struct {
+	mx          sync.Mutex
+	outs        []drivers.Out
+	freeRunning bool
+	onErr       func(error)
+
+	bpm     float64
+	ramp    *ramp
+	origin  time.Time // the start of the schedule
+	next    float64   // the scheduled time of the next timing clock in nanoseconds since the origin
+	ticking bool      // timing clocks are sent
+	running bool      // the song is playing
+	clocks  uint64    // the song position in timing clocks
+
+	wake      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns a Clock for the given tempo (in beats per minute) that sends to the given out ports.
+// The ports are opened, if necessary. The Clock must be closed after usage (see Close).
+func New(bpm float64, outs []drivers.Out, opts ...Option) (*Clock, error) {
+	if bpm <= 0 {
+		return nil, ErrInvalidTempo
+	}
+
+	for _, out := range outs {
+		if !out.IsOpen() {
+			err := out.Open()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c := &Clock{
+		outs: outs,
+		bpm:  bpm,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.freeRunning {
+		c.startTicking()
+	}
+
+	go c.run()
+	return c, nil
+}
+
+// run sends the timing clock messages at their scheduled times
+func (c *Clock) run() {
+	for {
+		var timer *time.Timer
+		var due <-chan time.Time
+
+		c.mx.Lock()
+		if c.ticking {
+			timer = time.NewTimer(time.Until(c.origin.Add(time.Duration(c.next))))
+			due = timer.C
+		}
+		c.mx.Unlock()
+
+		select {
+		case <-c.done:
+		case <-c.wake:
+		case <-due:
+			c.mx.Lock()
+			c.tick()
+			c.mx.Unlock()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+	}
+}
+
+// tick sends a timing clock message and schedules the next one
+func (c *Clock) tick() {
+	if !c.ticking {
+		return
+	}
+
+	err := c.send(midi.TimingClock())
+	if err != nil && c.onErr != nil {
+		c.onErr(err)
+	}
+
+	if c.running {
+		c.clocks++
+	}
+
+	c.advance()
+}
+
+// advance schedules the next timing clock message with the tempo at the scheduled time of the current one
+func (c *Clock) advance() {
+	c.next += float64(time.Minute) / (c.tempoAt(c.next) * PPQN)
+}
+
+// tempoAt returns the tempo at the given scheduled time
+func (c *Clock) tempoAt(t float64) float64 {
+	if c.ramp == nil {
+		return c.bpm
+	}
+
+	if t >= c.ramp.start+c.ramp.length {
+		c.bpm = c.ramp.to
+		c.ramp = nil
+		return c.bpm
+	}
+
+	return c.ramp.from + (c.ramp.to-c.ramp.from)*(t-c.ramp.start)/c.ramp.length
+}
+
+// startTicking starts the schedule with a timing clock message now, if it is not running
+func (c *Clock) startTicking() {
+	if c.ticking {
+		return
+	}
+
+	if c.ramp != nil {
+		// keep the progress of the ramp
+		c.ramp.start -= c.next
+	}
+
+	c.origin = time.Now()
+	c.next = 0
+	c.ticking = true
+	c.notify()
+}
+
+// stopTicking stops the schedule, unless the Clock is free running
+func (c *Clock) stopTicking() {
+	if c.freeRunning {
+		return
+	}
+
+	c.ticking = false
+	c.notify()
+}
+
+// notify wakes up the goroutine that sends the timing clock messages
+func (c *Clock) notify() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// send sends the message to all out ports and returns the first error
+func (c *Clock) send(msg midi.Message) (err error) {
+	for _, out := range c.outs {
+		e := out.Send(msg)
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// Tempo returns the current tempo in beats per minute.
+func (c *Clock) Tempo() float64 {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.tempoAt(c.next)
+}
+
+// SetTempo sets the tempo (in beats per minute). It applies from the next timing clock message on
+// and ends a running ramp.
+func (c *Clock) SetTempo(bpm float64) error {
+	if bpm <= 0 {
+		return ErrInvalidTempo
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.bpm = bpm
+	c.ramp = nil
+	return nil
+}
+
+// Ramp changes the tempo linearly from the current tempo to the given tempo (in beats per minute)
+// within the given duration. The tempo changes with each timing clock message. The ramp only proceeds
+// while timing clock messages are sent.
+func (c *Clock) Ramp(bpm float64, d time.Duration) error {
+	if d <= 0 {
+		return c.SetTempo(bpm)
+	}
+
+	if bpm <= 0 {
+		return ErrInvalidTempo
+	}
+
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.ramp = &ramp{
+		from:   c.tempoAt(c.next),
+		to:     bpm,
+		start:  c.next,
+		length: float64(d),
+	}
+
+	return nil
+}
+
+// Start sends a start message and starts the song at its beginning.
+func (c *Clock) Start() error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.clocks = 0
+	c.running = true
+	err := c.send(midi.Start())
+	c.startTicking()
+	return err
+}
+
+// Stop sends a stop message and stops the song at its current position.
+func (c *Clock) Stop() error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if !c.running {
+		return nil
+	}
+
+	c.running = false
+	c.stopTicking()
+	return c.send(midi.Stop())
+}
+
+// Continue sends a continue message and continues the song at its current position.
+func (c *Clock) Continue() error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.running {
+		return nil
+	}
+
+	c.running = true
+	err := c.send(midi.Continue())
+	c.startTicking()
+	return err
+}
+
+// Locate sets the song position to the given MIDI beat (a sixteenth note) and sends a song position pointer message.
+// Since the devices are only required to handle the song position pointer while they are stopped, a playing song
+// is stopped before and continued afterwards.
+func (c *Clock) Locate(beat uint16) error {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.clocks = uint64(beat) * clocksPerBeat
+
+	if !c.running {
+		return c.send(midi.SPP(beat))
+	}
+
+	err := c.send(midi.Stop())
+	if err != nil {
+		return err
+	}
+
+	err = c.send(midi.SPP(beat))
+	if err != nil {
+		return err
+	}
+
+	return c.send(midi.Continue())
+}
+
+// Position returns the current song position in MIDI beats (sixteenth notes) and the timing clocks since that beat.
+func (c *Clock) Position() (beat uint16, clocks uint8) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return uint16(c.clocks / clocksPerBeat), uint8(c.clocks % clocksPerBeat)
+}
+
+// Running returns true, if the song is playing.
+func (c *Clock) Running() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.running
+}
+
+// Close stops the song, if it is playing, and stops the sending of timing clock messages.
+// The out ports are not closed.
+func (c *Clock) Close() error {
+	err := c.Stop()
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	return err
+}
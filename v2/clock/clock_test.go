@@ -0,0 +1,134 @@
+package clock
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+// recorder passes the sent messages to a channel
+type recorder struct {
+	drivers.Out
+	sent chan midi.Message
+}
+
+func (r *recorder) Send(bt []byte) error {
+	r.sent <- midi.Message(bt)
+	return nil
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		descr        string
+		bpm          float64
+		rampTo       float64
+		ramp         time.Duration
+		clocks       int
+		wantNext     time.Duration
+		wantInterval time.Duration
+	}{
+		{"constant tempo", 120, 0, 0, 24 * 10000, 10000 * 500 * time.Millisecond, 500 * time.Millisecond / PPQN},
+		{"odd tempo", 133, 0, 0, 24 * 133, time.Minute, time.Minute / (133 * PPQN)},
+		{"ramp", 120, 240, time.Second, 24 * 100, 0, 250 * time.Millisecond / PPQN},
+	}
+
+	for _, test := range tests {
+		c := &Clock{bpm: test.bpm}
+
+		if test.ramp > 0 {
+			c.ramp = &ramp{from: test.bpm, to: test.rampTo, length: float64(test.ramp)}
+		}
+
+		last := math.Inf(1)
+		var interval float64
+
+		for i := 0; i < test.clocks; i++ {
+			prev := c.next
+			c.advance()
+			interval = c.next - prev
+
+			if interval > last+1 {
+				t.Errorf("[%s] interval of clock %v increased: %v > %v", test.descr, i, interval, last)
+			}
+			last = interval
+		}
+
+		if test.wantNext > 0 {
+			if diff := math.Abs(c.next - float64(test.wantNext)); diff > float64(time.Microsecond) {
+				t.Errorf("[%s] next: %v; want %v", test.descr, time.Duration(c.next), test.wantNext)
+			}
+		}
+
+		if diff := math.Abs(interval - float64(test.wantInterval)); diff > 1 {
+			t.Errorf("[%s] interval: %v; want %v", test.descr, time.Duration(interval), test.wantInterval)
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	drv := testdrv.New("clock")
+	outs, _ := drv.Outs()
+
+	rec := &recorder{Out: outs[0], sent: make(chan midi.Message, 1024)}
+
+	c, err := New(600, []drivers.Out{rec})
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	var msgs []string
+
+	// collect collects the sent messages until n timing clock messages have been sent, joining subsequent ones
+	collect := func(n int) {
+		for n > 0 {
+			select {
+			case msg := <-rec.sent:
+				if msg.Is(midi.TimingClockMsg) {
+					n--
+					if len(msgs) > 0 && msgs[len(msgs)-1] == msg.String() {
+						continue
+					}
+				}
+				msgs = append(msgs, msg.String())
+			case <-time.After(time.Second):
+				t.Fatalf("missing timing clock messages")
+			}
+		}
+	}
+
+	c.Start()
+	collect(12)
+	c.Locate(8)
+	collect(12)
+	c.Stop()
+	c.Close()
+
+	if beat, _ := c.Position(); beat < 9 || beat > 12 {
+		t.Errorf("position: %v; want 9-12", beat)
+	}
+
+	// remaining messages
+	close(rec.sent)
+	for msg := range rec.sent {
+		if !msg.Is(midi.TimingClockMsg) || msgs[len(msgs)-1] != msg.String() {
+			msgs = append(msgs, msg.String())
+		}
+	}
+
+	want := `Start
+TimingClock
+Stop
+SPP spp: 8
+Continue
+TimingClock
+Stop`
+
+	if got := strings.Join(msgs, "\n"); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
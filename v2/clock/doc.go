@@ -0,0 +1,22 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package clock provides a MIDI clock master that drives devices like drum machines and effects.
+
+A Clock sends 24 timing clock messages per quarter note to its out ports and controls the playing of the devices
+via start, stop and continue messages. The song position is set with a song position pointer message (see Clock.Locate).
+
+The clock messages are scheduled at absolute times since the start of the clock, so that the jitter of the
+scheduling does not accumulate. The tempo can be changed immediately or linearly within a given time (see Clock.Ramp).
+
+	c, err := clock.New(120, []drivers.Out{out})
+	...
+	c.Start()
+	c.Ramp(140, 4*time.Second)
+	...
+	c.Stop()
+	c.Close()
+*/
+package clock
@@ -27,15 +27,11 @@ func ResetChannel(ch uint8, bank uint8, prog uint8) []Message {
 	return msgs
 }
 
-// SilenceChannel sends a  note off message for every running note
-// on the given channel. If the channel is -1, every channel is affected.
-// If note consolidation is switched off, notes aren't tracked and therefor
-// every possible note will get a note off. This could also be enforced by setting
-// force to true. If force is true, additionally the cc messages AllNotesOff (123) and AllSoundOff (120)
-// are send.
+// SilenceChannel returns the cc messages AllNotesOff (123) and AllSoundOff (120)
+// for the given channel. If the channel is -1, every channel is affected.
+// Since some instruments ignore these messages, consider to use a NoteTracker,
+// which sends note off messages for exactly the sounding notes.
 // If channel is > 15, the method panics.
-// The error or not error of the last write is returned.
-// (i.e. it does not return on the first error, but tries everything instead to make it silent)
 func SilenceChannel(ch int8) (out []Message) {
 	if ch > 15 {
 		panic("invalid channel number")
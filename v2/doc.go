@@ -44,6 +44,18 @@ Therefor it is handy, that the drivers autoregister themself. This also makes it
 
 Different cross plattform implementations of the `Driver` interface can be found in the `drivers` subdirectory.
 
+Received messages are passed to a callback on the goroutine of the driver (see ListenTo). ListenContext delivers the
+messages over a buffered channel instead and stops the listening, when the context is done or the port is closed.
+ListenTimestamp passes a high resolution Timestamp (the monotonic time since the listening started and the wall clock time)
+instead of the milliseconds.
+The option WatchActiveSense detects the loss of a connection that sends active sense messages and an ActiveSenseOut
+sends active sense messages while it is idle (see NewActiveSenseOut).
+
+To read MIDI data from any io.Reader (e.g. a serial device or a dump file), use a `Reader` (see NewReader).
+
+The string representation of a message (see Message.String) can be parsed back to the message via Parse.
+The `typed` subpackage provides typed values of the messages that can be marshalled to and from JSON.
+
 The `smf` subpackage helps with writing to and reading from `Simple MIDI Files` (SMF) (see https://pkg.go.dev/gitlab.com/gomidi/midi/v2/smf).
 
 The `tools` subdirectory provides command line tools and libraries based on this library.
@@ -2,7 +2,9 @@ package drivertest
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -186,6 +188,8 @@ F8
 8. The midi.In port is responsible for buffering of sysex data. Only complete sysex data is passed to the listener.
 9. The midi In port must check the ListenConfig and act accordingly.
 10. incomplete sysex data must be cached inside the sender and flushed, if the data is complete.
+[x] 11. The timestamps of the received messages never decrease and the wall clock time (if provided) matches the monotonic time.
+[x] 12. Sysex messages larger than the initial buffer size are received as a whole (up to SysExMaxSize).
 
 */
 
@@ -483,3 +487,255 @@ func NoSysexTest(t *testing.T, in drivers.In, out drivers.Out) {
 	}
 
 }
+
+// 11. The timestamps of the received messages never decrease and the wall clock time (if provided) matches the monotonic time.
+func TimestampTest(t *testing.T, in drivers.In, out drivers.Out) {
+	var got []drivers.Timestamp
+	var mx sync.Mutex
+
+	in.Open()
+	out.Open()
+
+	var conf drivers.ListenConfig
+
+	start := time.Now()
+
+	stop, err := drivers.ListenTimestamp(in, func(msg []byte, ts drivers.Timestamp) {
+		mx.Lock()
+		got = append(got, ts)
+		mx.Unlock()
+	}, conf)
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	msgs := [][]byte{midi.NoteOn(2, 65, 120), midi.NoteOn(2, 55, 120), midi.NoteOff(2, 65)}
+
+	for i, msg := range msgs {
+		if i > 0 {
+			time.Sleep(10 * time.Millisecond)
+		}
+		err = out.Send(msg)
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	stop()
+	elapsed := time.Since(start)
+
+	err = in.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+	err = out.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if len(got) != len(msgs) {
+		t.Fatalf("expected %v timestamps, got %v", len(msgs), len(got))
+	}
+
+	const tolerance = 10 * time.Millisecond
+
+	for i, ts := range got {
+		if ts.Since < 0 || ts.Since > elapsed {
+			t.Errorf("timestamp %v: Since %v is not within the listening time of %v", i, ts.Since, elapsed)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := got[i-1]
+
+		if ts.Since < prev.Since {
+			t.Errorf("timestamp %v: Since decreased from %v to %v", i, prev.Since, ts.Since)
+		}
+
+		if ts.Time.IsZero() || prev.Time.IsZero() {
+			continue
+		}
+
+		if ts.Time.Before(prev.Time) {
+			t.Errorf("timestamp %v: Time decreased from %v to %v", i, prev.Time, ts.Time)
+		}
+
+		diff := ts.Time.Sub(prev.Time) - (ts.Since - prev.Since)
+		if diff < -tolerance || diff > tolerance {
+			t.Errorf("timestamp %v: Time and Since differ by %v", i, diff)
+		}
+	}
+}
+
+// 12. Sysex messages larger than the initial buffer size are received as a whole (up to SysExMaxSize).
+func LargeSysexTest(t *testing.T, in drivers.In, out drivers.Out) {
+	var got [][]byte
+	var errs []error
+	var mx sync.Mutex
+
+	in.Open()
+	out.Open()
+
+	var conf drivers.ListenConfig
+	conf.SysEx = true
+	conf.SysExMaxSize = 16 * 1024
+	conf.OnErr = func(err error) {
+		mx.Lock()
+		errs = append(errs, err)
+		mx.Unlock()
+	}
+
+	// a sysex message of the manufacturer 0x41 with 10000 data bytes
+	large := make([]byte, 10002)
+	for i := range large {
+		large[i] = byte(i % 128)
+	}
+	large[0], large[1], large[len(large)-1] = 0xF0, 0x41, 0xF7
+
+	// a sysex message of the manufacturer 0x00 0x20 0x33 with 20000 data bytes
+	tooLarge := make([]byte, 20002)
+	for i := range tooLarge {
+		tooLarge[i] = byte(i % 128)
+	}
+	tooLarge[0], tooLarge[1], tooLarge[2], tooLarge[3], tooLarge[len(tooLarge)-1] = 0xF0, 0x00, 0x20, 0x33, 0xF7
+
+	stop, err := in.Listen(func(msg []byte, ms int32) {
+		mx.Lock()
+		got = append(got, append([]byte(nil), msg...))
+		mx.Unlock()
+	}, conf)
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	for _, msg := range [][]byte{large, tooLarge, midi.NoteOn(2, 65, 120)} {
+		err = out.Send(msg)
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	stop()
+	err = in.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+	err = out.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 messages, got %v", len(got))
+	}
+
+	if !bytes.Equal(got[0], large) {
+		t.Errorf("large sysex message: got %v bytes, expected %v bytes", len(got[0]), len(large))
+	}
+
+	if want := "92 41 78"; fmt.Sprintf("% X", got[1]) != want {
+		t.Errorf("got % X, expected %s", got[1], want)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+
+	var tle *drivers.SysExTooLargeError
+	if !errors.As(errs[0], &tle) {
+		t.Fatalf("expected *drivers.SysExTooLargeError, got %T", errs[0])
+	}
+
+	if tle.Length != len(tooLarge) || !bytes.Equal(tle.Manufacturer, []byte{0x00, 0x20, 0x33}) {
+		t.Errorf("got length %v and manufacturer % X, expected %v and 00 20 33", tle.Length, tle.Manufacturer, len(tooLarge))
+	}
+}
+
+// 13. Out ports that implement drivers.RawOut pass the raw bytes as they are, so that a sysex message that is
+// sent in chunks is received as a whole.
+func RawSysexTest(t *testing.T, in drivers.In, out drivers.Out) {
+	raw, ok := out.(drivers.RawOut)
+	if !ok {
+		t.Skipf("%s does not implement drivers.RawOut", out)
+		return
+	}
+
+	var got bytes.Buffer
+	var mx sync.Mutex
+
+	in.Open()
+	out.Open()
+
+	var conf drivers.ListenConfig
+	conf.SysEx = true
+
+	stop, err := in.Listen(func(msg []byte, ms int32) {
+		mx.Lock()
+		got.WriteString(fmt.Sprintf("% X\n", msg))
+		mx.Unlock()
+	}, conf)
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	sys := []byte{0xF0, 0x41, 0x10, 0x42, 0x12, 0x40, 0x00, 0x7F, 0x00, 0x41, 0xF7}
+
+	for from := 0; from < len(sys); from += 4 {
+		to := from + 4
+		if to > len(sys) {
+			to = len(sys)
+		}
+
+		err = raw.SendRaw(sys[from:to])
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err = out.Send(midi.NoteOn(2, 65, 120))
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	stop()
+	err = in.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+	err = out.Close()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	expected := `F0 41 10 42 12 40 00 7F 00 41 F7
+92 41 78
+`
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if got.String() != expected {
+		t.Errorf("\nexpected: \n%s\n     got: \n%s\n", expected, got.String())
+	}
+}
@@ -68,6 +68,14 @@ func TestSpec(t *testing.T) {
 			"NoSysex",
 			drivertest.NoSysexTest,
 		},
+		{
+			"LargeSysex",
+			drivertest.LargeSysexTest,
+		},
+		{
+			"Timestamp",
+			drivertest.TimestampTest,
+		},
 	}
 
 	for _, test := range tests {
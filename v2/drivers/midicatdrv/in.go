@@ -5,6 +5,7 @@ import (
 	"io"
 	"runtime"
 	"sync"
+	"time"
 
 	"gitlab.com/gomidi/midi/v2"
 	"gitlab.com/gomidi/midi/v2/drivers"
@@ -22,6 +23,7 @@ type in struct {
 	wasKilled           chan bool
 	hasProc             bool
 	listener            func(data []byte, deltamillisecs int32)
+	onErr               func(error)
 }
 
 func (o *in) fireCmd() error {
@@ -138,6 +140,15 @@ func (i *in) Close() (err error) {
 
 	i.shouldKill <- true
 	<-i.wasKilled
+
+	i.Lock()
+	onErr := i.onErr
+	i.onErr = nil
+	i.Unlock()
+
+	if onErr != nil {
+		onErr(drivers.ErrListenStopped)
+	}
 	return
 }
 
@@ -166,6 +177,10 @@ func newIn(driver *Driver, number int, name string) drivers.In {
 
 func (i *in) Listen(onMsg func(msg []byte, absmilliseconds int32), conf drivers.ListenConfig) (stopFn func(), err error) {
 	stopFn = func() {
+		i.Lock()
+		i.onErr = nil
+		i.Unlock()
+
 		if !i.IsOpen() {
 			return
 		}
@@ -188,13 +203,20 @@ func (i *in) Listen(onMsg func(msg []byte, absmilliseconds int32), conf drivers.
 	}
 	i.RUnlock()
 
-	//var rd = drivers.NewReader(config, onMsg)
+	// sysex messages go through the reader that handles the size limit and the chunks (see drivers.ListenConfig)
+	var rd = drivers.NewReader(conf, onMsg)
 	i.Lock()
+	i.onErr = conf.OnErr
 	i.listener = func(data []byte, absmilliseconds int32) {
 		//rd.EachMessage(data, deltamillisecs)
 		//rd.EachMessage(data, -1)
 		msg := midi.Message(data)
 
+		// the data counts as received, even if it is filtered (the Reader reports the sysex data again)
+		if conf.OnReceive != nil {
+			conf.OnReceive()
+		}
+
 		if msg.Is(midi.ActiveSenseMsg) && !conf.ActiveSense {
 			return
 		}
@@ -203,7 +225,10 @@ func (i *in) Listen(onMsg func(msg []byte, absmilliseconds int32), conf drivers.
 			return
 		}
 
-		if msg.Is(midi.SysExMsg) && !conf.SysEx {
+		if msg.Is(midi.SysExMsg) {
+			if conf.SysEx {
+				rd.EachMessageAt(data, drivers.Timestamp{Since: time.Duration(absmilliseconds) * time.Millisecond})
+			}
 			return
 		}
 		onMsg(data, absmilliseconds)
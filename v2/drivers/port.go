@@ -48,13 +48,38 @@ type ListenConfig struct {
 	// SysEx lets the sysex messaes pass through, if set
 	SysEx bool
 
-	// SysExBufferSize defines the size of the buffer for sysex messages (in bytes).
-	// SysEx messages larger than this size will be ignored.
+	// SysExAbort is the policy for sysex messages that are terminated by a status byte other than 0xF7
+	// (see SysExAbortPolicy). It is only relevant, if SysEx is set.
+	SysExAbort SysExAbortPolicy
+
+	// Undefined lets the undefined system common messages (0xF4 and 0xF5, including their data bytes) pass through, if set.
+	// The undefined realtime message 0xFD always passes through.
+	Undefined bool
+
+	// SysExBufferSize defines the initial size of the buffer for sysex messages (in bytes) that grows up to SysExMaxSize.
+	// If OnSysExChunk is set, it is the size of the chunks.
 	// When SysExBufferSize is 0, the default buffersize (1024) is used.
 	SysExBufferSize uint32
 
+	// SysExMaxSize defines the maximum size of sysex messages (in bytes, including 0xF0 and 0xF7).
+	// Larger sysex messages are skipped and a *SysExTooLargeError is passed to OnErr.
+	// When SysExMaxSize is 0, DefaultSysExMaxSize is used. It does not apply, if OnSysExChunk is set.
+	SysExMaxSize uint32
+
+	// OnSysExChunk, if set, receives the sysex messages in chunks of SysExBufferSize bytes instead of the onMsg callback
+	// of the listening, so that large dumps can be handled without buffering them as a whole.
+	OnSysExChunk func(SysExChunk)
+
 	// OnErr is the callback that is called for any error happening during the listening.
+	// ErrListenStopped is passed, when the listening ends, because the port has been closed.
 	OnErr func(error)
+
+	// OnReceive, if set, is called whenever data is received from the device, before it is filtered or assembled
+	// to messages, e.g. for every part of a sysex message. It allows to watch the connection.
+	// Drivers that read via a Reader get it for free, the others have to call it themselves.
+	// Data that is already dropped by the underlying library (e.g. sysex data with rtmidi, if SysEx is not set)
+	// can't be reported.
+	OnReceive func()
 }
 
 // In is an interface for a MIDI input port
@@ -82,6 +107,15 @@ type Out interface {
 	Send(data []byte) error
 }
 
+// RawOut is an optional interface for out ports that pass raw bytes to the device as they are, without
+// interpreting them as complete messages. This allows to send messages without their status byte (running status)
+// or a sysex message in chunks, where only the first chunk starts with 0xF0 and only the last chunk ends with 0xF7
+// (see RawSysexTest of the driver tests).
+type RawOut interface {
+	// SendRaw sends the given bytes to the device. The bytes may be any part of the MIDI data stream.
+	SendRaw(data []byte) error
+}
+
 // Ins return the available MIDI in ports
 func Ins() ([]In, error) {
 	d := Get()
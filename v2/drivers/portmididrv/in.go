@@ -104,13 +104,9 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 
 	lastTimestamp := portmidi.Time()
 	var inSysEx bool
-	if config.SysExBufferSize == 0 {
-		config.SysExBufferSize = 1024
-	}
-	maxlenSysex := int(config.SysExBufferSize)
-	var sysexBf = make([]byte, maxlenSysex)
-	var sysexlen int
-	var lastSysExTimestamp int32
+
+	// the reader assembles the sysex messages
+	var rd = drivers.NewReader(config, onMsg)
 
 	var stop int32
 
@@ -198,6 +194,11 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 
 			if err2 == nil {
 
+				// the data counts as received, even if it is filtered or only a part of a sysex message
+				if len(events) > 0 && config.OnReceive != nil {
+					config.OnReceive()
+				}
+
 				for _, ev := range events {
 
 					// ev.Timestamp is in Milliseconds
@@ -211,57 +212,21 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 
 					// sysex
 					case ev.Status == 0xF0 || inSysEx:
-						if ev.Status == 0xF0 {
-							inSysEx = true
-							lastSysExTimestamp = ts
-							// really? to cancel the running status, send the 0xF7 to the onMsg callback
-							// onMsg([3]byte{0xF7, 0, 0}, ts)
-						}
-
+						inSysEx = true
 						bt := []byte{byte(ev.Status), byte(ev.Data1), byte(ev.Data2), byte(ev.Rest)}
 
-						if config.SysEx {
-							for _, b := range bt {
-								if sysexlen >= maxlenSysex {
-									if config.OnErr != nil {
-										config.OnErr(fmt.Errorf("skip sysex; sysex buffer size is too small (%v byte)", config.SysExBufferSize))
-									}
-									break
-								}
-								sysexBf[sysexlen] = b
-								sysexlen++
-								if b == 0xF7 {
-									if sysexlen >= maxlenSysex {
-										sysexlen = 0
-										inSysEx = false
-										break
-									}
-									go func(bb []byte, l int) {
-										var _bt = make([]byte, l)
-
-										for i := 0; i < l; i++ {
-											_bt[i] = bb[i]
-										}
-										onMsg(_bt, lastSysExTimestamp)
-									}(sysexBf, sysexlen)
-									sysexlen = 0
-									inSysEx = false
-									break
-								}
-							}
-						} else {
-							if pos := bytes.IndexByte(bt, 0xF7); pos >= 0 {
-								inSysEx = false
-							}
+						if pos := bytes.IndexByte(bt, 0xF7); pos >= 0 {
+							bt = bt[:pos+1]
+							inSysEx = false
 						}
 
+						// the reader handles the size limit and the chunks (see drivers.ListenConfig)
+						rd.EachMessageAt(bt, drivers.Timestamp{Since: time.Duration(ts) * time.Millisecond})
+
 					// [MIDI] permits 0xF7 octets that are not part of a (0xF0, 0xF7) pair
 					// to appear on a MIDI 1.0 DIN cable.  Unpaired 0xF7 octets have no
 					// semantic meaning in MIDI apart from cancelling running status.
 					case ev.Status == 0xF7:
-						if config.SysEx {
-							sysexlen = 0
-						}
 						// to allow cancelling running status, send the 0xF7
 						onMsg([]byte{0xF7}, ts)
 
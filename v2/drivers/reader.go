@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"fmt"
+	"time"
 
 	midilib "gitlab.com/gomidi/midi/v2/internal/utils"
 )
@@ -35,11 +36,16 @@ const (
 
 type Reader struct {
 	//	maxlenSysex     int
-	sysexBf  []byte
-	sysexlen int
-
-	ts_ms      int32
-	sysexTS    int32
+	sysexBf       []byte
+	sysexHead     [4]byte
+	sysexTotal    int
+	sysexSeq      int
+	sysexTooLarge bool
+
+	ts         Timestamp
+	sysexTS    Timestamp
+	undefined  []byte
+	undefTS    Timestamp
 	state      readerState
 	statusByte uint8
 	issetBf    bool
@@ -47,9 +53,21 @@ type Reader struct {
 	typ        uint8
 
 	SysExBufferSize uint32
+	SysExMaxSize    uint32
 	OnMsg           func([]byte, int32)
 	HandleSysex     bool
+	HandleUndefined bool
+	SysExAbort      SysExAbortPolicy
 	OnErr           func(error)
+
+	// OnTimestamp, if set, is called for every message with its Timestamp (in addition to OnMsg).
+	OnTimestamp func([]byte, Timestamp)
+
+	// OnSysExChunk, if set, is called for the chunks of the sysex messages instead of OnMsg and OnTimestamp.
+	OnSysExChunk func(SysExChunk)
+
+	// OnReceive, if set, is called for the data passed to EachMessage and EachMessageAt, before it is read.
+	OnReceive func()
 }
 
 func (r *Reader) withinChannelMessage(b byte) {
@@ -59,18 +77,18 @@ func (r *Reader) withinChannelMessage(b byte) {
 		r.issetBf = false
 		r.state = readerStateClean
 		//p.receiver.Receive(Channel(p.channel).Aftertouch(b), p.timestamp)
-		r.OnMsg([]byte{r.statusByte, b, 0}, r.ts_ms)
+		r.emit([]byte{r.statusByte, b, 0})
 	case byteProgramChange:
 		r.issetBf = false // first: is set, second: the byte
 		r.state = readerStateClean
 		//p.receiver.Receive(Channel(p.channel).ProgramChange(b), p.timestamp)
-		r.OnMsg([]byte{r.statusByte, b, 0}, r.ts_ms)
+		r.emit([]byte{r.statusByte, b, 0})
 	case byteControlChange:
 		if r.issetBf {
 			r.issetBf = false // first: is set, second: the byte
 			r.state = readerStateClean
 			//p.receiver.Receive(Channel(p.channel).ControlChange(p.getBf(), b), p.timestamp)
-			r.OnMsg([]byte{r.statusByte, r.bf, b}, r.ts_ms)
+			r.emit([]byte{r.statusByte, r.bf, b})
 		} else {
 			r.issetBf = true
 			r.bf = b
@@ -80,7 +98,7 @@ func (r *Reader) withinChannelMessage(b byte) {
 			r.issetBf = false // first: is set, second: the byte
 			r.state = readerStateClean
 			//p.receiver.Receive(Channel(p.channel).NoteOn(p.getBf(), b), p.timestamp)
-			r.OnMsg([]byte{r.statusByte, r.bf, b}, r.ts_ms)
+			r.emit([]byte{r.statusByte, r.bf, b})
 		} else {
 			r.issetBf = true
 			r.bf = b
@@ -90,7 +108,7 @@ func (r *Reader) withinChannelMessage(b byte) {
 			r.issetBf = false // first: is set, second: the byte
 			r.state = readerStateClean
 			//p.receiver.Receive(Channel(p.channel).NoteOffVelocity(p.getBf(), b), p.timestamp)
-			r.OnMsg([]byte{r.statusByte, r.bf, b}, r.ts_ms)
+			r.emit([]byte{r.statusByte, r.bf, b})
 		} else {
 			r.issetBf = true
 			r.bf = b
@@ -100,7 +118,7 @@ func (r *Reader) withinChannelMessage(b byte) {
 			r.issetBf = false // first: is set, second: the byte
 			r.state = readerStateClean
 			//p.receiver.Receive(Channel(p.channel).PolyAftertouch(p.getBf(), b), p.timestamp)
-			r.OnMsg([]byte{r.statusByte, r.bf, b}, r.ts_ms)
+			r.emit([]byte{r.statusByte, r.bf, b})
 		} else {
 			r.issetBf = true
 			r.bf = b
@@ -112,7 +130,7 @@ func (r *Reader) withinChannelMessage(b byte) {
 			r.issetBf = false // first: is set, second: the byte
 			r.state = readerStateClean
 			//p.receiver.Receive(Channel(p.channel).Pitchbend(rel), p.timestamp)
-			r.OnMsg([]byte{r.statusByte, r.bf, b}, r.ts_ms)
+			r.emit([]byte{r.statusByte, r.bf, b})
 		} else {
 			r.issetBf = true
 			r.bf = b
@@ -129,12 +147,9 @@ func (r *Reader) cleanState(b byte) {
 	/* start sysex */
 	case b == 0xF0:
 		r.statusByte = 0
-		r.sysexBf = make([]byte, r.SysExBufferSize)
-		//sysexBf.Reset()
-		//sysexBf.WriteByte(b)
-		r.sysexBf[0] = b
-		r.sysexlen = 1
-		r.sysexTS = r.ts_ms
+		if r.HandleSysex {
+			r.startSysEx()
+		}
 		r.state = readerStateInSysEx
 	// end sysex
 	// [MIDI] permits 0xF7 octets that are not part of a (0xF0, 0xF7) pair
@@ -142,9 +157,8 @@ func (r *Reader) cleanState(b byte) {
 	// semantic meaning in MIDI apart from cancelling running status.
 	case b == 0xF7:
 		r.sysexBf = nil
-		r.sysexlen = 0
 		r.statusByte = 0
-		r.OnMsg([]byte{b, 0, 0}, r.ts_ms)
+		r.emit([]byte{b, 0, 0})
 
 	// here we clear for System Common Category messages
 	case b > 0xF0 && b < 0xF7:
@@ -156,7 +170,7 @@ func (r *Reader) cleanState(b byte) {
 			r.state = readerStateWithinSysCommon
 			r.typ = b
 		case byteSysTuneRequest:
-			r.OnMsg([]byte{b, 0, 0}, r.ts_ms)
+			r.emit([]byte{b, 0, 0})
 			/*
 				if p.syscommonHander != nil {
 					p.syscommonHander(Tune(), p.timestamp)
@@ -164,8 +178,13 @@ func (r *Reader) cleanState(b byte) {
 			*/
 			return
 		default:
-			// 0xF4, 0xF5, or 0xFD
+			// 0xF4 or 0xF5
 			r.state = readerStateWithinUnknown
+			if r.HandleUndefined {
+				// the length is undefined, so the message is passed with the next status byte
+				r.undefined = []byte{b}
+				r.undefTS = r.ts
+			}
 			return
 		}
 
@@ -188,7 +207,7 @@ func (r *Reader) cleanState(b byte) {
 func (r *Reader) eachByte(b byte) {
 	if b >= 0xF8 {
 		//r.OnMsg([]byte{b, 0, 0}, r.ts_ms)
-		r.OnMsg([]byte{b}, r.ts_ms)
+		r.emit([]byte{b})
 		return
 	}
 
@@ -197,19 +216,6 @@ func (r *Reader) eachByte(b byte) {
 	switch r.state {
 	case readerStateInSysEx:
 		//fmt.Println("readerStateInSysEx")
-		/* interrupted sysex, discard old data */
-		if b == 0xF0 {
-			r.statusByte = 0
-			r.sysexBf = make([]byte, r.SysExBufferSize)
-			r.sysexTS = r.ts_ms
-			//sysexBf.Reset()
-			//sysexBf.WriteByte(b)
-			r.sysexBf[0] = b
-			r.sysexlen = 1
-			r.state = readerStateInSysEx
-			return
-		}
-
 		if b == 0xF7 {
 			/*
 				if p.sysexHandler != nil {
@@ -221,34 +227,26 @@ func (r *Reader) eachByte(b byte) {
 			*/
 			r.state = readerStateClean
 			if r.HandleSysex {
-				r.sysexBf[r.sysexlen] = b
-				r.sysexlen++
-				//go
-				func(bb []byte, l int) {
-					var _bt = make([]byte, l)
-
-					for i := 0; i < l; i++ {
-						_bt[i] = bb[i]
-					}
-					r.OnMsg(_bt, r.sysexTS)
-				}(r.sysexBf, r.sysexlen)
+				r.completeSysEx()
 			}
 			r.sysexBf = nil
-			r.sysexlen = 0
 			return
 		}
+
+		/* interrupted sysex (also by 0xF0): the status byte starts the next message */
 		if midilib.IsStatusByte(b) {
+			if r.HandleSysex {
+				r.abortSysEx(b)
+			}
 			//p.sysexBf.Reset()
 			r.sysexBf = nil
-			r.sysexlen = 0
 			r.state = readerStateClean
 			r.cleanState(b)
 			return
 		}
 
 		if r.HandleSysex {
-			r.sysexBf[r.sysexlen] = b
-			r.sysexlen++
+			r.writeSysEx(b)
 		}
 
 		/*
@@ -263,8 +261,16 @@ func (r *Reader) eachByte(b byte) {
 		//fmt.Println("readerStateWithinUnknown")
 		//p.withinUnknown(b)
 		if midilib.IsStatusByte(b) {
+			if r.undefined != nil {
+				r.emitAt(r.undefined, r.undefTS)
+				r.undefined = nil
+			}
 			r.state = readerStateClean
 			r.cleanState(b)
+			return
+		}
+		if r.undefined != nil {
+			r.undefined = append(r.undefined, b)
 		}
 	case readerStateWithinSysCommon:
 		//fmt.Println("readerStateWithinSysCommon")
@@ -277,7 +283,7 @@ func (r *Reader) eachByte(b byte) {
 			*/
 			r.issetBf = false
 			r.state = readerStateClean
-			r.OnMsg([]byte{r.typ, b, 0}, r.ts_ms)
+			r.emit([]byte{r.typ, b, 0})
 		case byteSysSongPositionPointer:
 			if r.issetBf {
 				/*
@@ -288,7 +294,7 @@ func (r *Reader) eachByte(b byte) {
 				*/
 				r.issetBf = false
 				r.state = readerStateClean
-				r.OnMsg([]byte{r.typ, r.bf, b}, r.ts_ms)
+				r.emit([]byte{r.typ, r.bf, b})
 			} else {
 				r.issetBf = true
 				r.bf = b
@@ -301,7 +307,7 @@ func (r *Reader) eachByte(b byte) {
 			*/
 			r.issetBf = false
 			r.state = readerStateClean
-			r.OnMsg([]byte{r.typ, b, 0}, r.ts_ms)
+			r.emit([]byte{r.typ, b, 0})
 		case byteSysTuneRequest:
 			//panic("must not be handled here, but within clean state")
 		default:
@@ -325,9 +331,13 @@ func (r *Reader) Reset() {
 		r.SysExBufferSize = 1024
 	}
 
-	r.sysexBf = make([]byte, r.SysExBufferSize)
-	r.sysexlen = 0
-	r.ts_ms = 0
+	if r.SysExMaxSize == 0 {
+		r.SysExMaxSize = DefaultSysExMaxSize
+	}
+
+	r.sysexBf = nil
+	r.undefined = nil
+	r.ts = Timestamp{}
 	r.statusByte = 0
 	r.issetBf = false
 	r.state = readerStateClean
@@ -339,13 +349,39 @@ func NewReader(config ListenConfig, onMsg func([]byte, int32)) *Reader {
 	r.OnErr = config.OnErr
 	//r.OnSysEx = config.OnSysEx
 	r.HandleSysex = config.SysEx
+	r.HandleUndefined = config.Undefined
+	r.SysExAbort = config.SysExAbort
 	r.SysExBufferSize = config.SysExBufferSize
+	r.SysExMaxSize = config.SysExMaxSize
+	r.OnSysExChunk = config.OnSysExChunk
+	r.OnReceive = config.OnReceive
 	r.Reset()
 	return &r
 }
 
 func (r *Reader) setDelta(deltaMilliSeconds int32) {
-	r.ts_ms += deltaMilliSeconds
+	r.ts.Since += time.Duration(deltaMilliSeconds) * time.Millisecond
+}
+
+func (r *Reader) emit(bt []byte) {
+	r.emitAt(bt, r.ts)
+}
+
+func (r *Reader) emitAt(bt []byte, ts Timestamp) {
+	if r.OnMsg != nil {
+		r.OnMsg(bt, ts.Milliseconds())
+	}
+
+	if r.OnTimestamp != nil {
+		r.OnTimestamp(bt, ts)
+	}
+}
+
+// received calls OnReceive, if there is data
+func (r *Reader) received(bt []byte) {
+	if r.OnReceive != nil && len(bt) > 0 {
+		r.OnReceive()
+	}
 }
 
 func (r *Reader) resetStatus() {
@@ -361,6 +397,7 @@ func (r *Reader) EachMessage(bt []byte, deltaMilliSeconds int32) {
 	//r.ResetStatus()
 
 	r.setDelta(deltaMilliSeconds) // int32(math.Round(deltaSeconds * 1000))
+	r.received(bt)
 
 	//fmt.Printf("got % X\n", bt)
 
@@ -371,3 +408,14 @@ func (r *Reader) EachMessage(bt []byte, deltaMilliSeconds int32) {
 	}
 
 }
+
+// EachMessageAt is like EachMessage, but for drivers that track the time themselves: the messages
+// are received at the given timestamp (see TimestampIn).
+func (r *Reader) EachMessageAt(bt []byte, ts Timestamp) {
+	r.ts = ts
+	r.received(bt)
+
+	for _, b := range bt {
+		r.eachByte(b)
+	}
+}
@@ -3,6 +3,7 @@ package rtmididrv
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"gitlab.com/gomidi/midi/v2/drivers"
 	"gitlab.com/gomidi/midi/v2/drivers/rtmididrv/imported/rtmidi"
@@ -15,6 +16,7 @@ type in struct {
 	driver *Driver
 	name   string
 	midiIn rtmidi.MIDIIn
+	onErr  func(error)
 }
 
 // IsOpen returns wether the MIDI in port is open
@@ -50,6 +52,12 @@ func (i *in) Close() (err error) {
 		return nil
 	}
 
+	// report the end of the listening, while the port is still open
+	if onErr := i.onErr; onErr != nil {
+		i.onErr = nil
+		onErr(drivers.ErrListenStopped)
+	}
+
 	//	i.StopListening()
 	//	i.Lock()
 	err = i.midiIn.Close()
@@ -128,7 +136,26 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 		return nil, fmt.Errorf("onMsg callback must not be nil")
 	}
 
+	return i.listen(func(msg []byte, ts drivers.Timestamp) {
+		onMsg(msg, ts.Milliseconds())
+	}, config)
+}
+
+// ListenTimestamp implements drivers.TimestampIn. The timestamps are the summed up deltas of rtmidi, the Time is
+// the time when the callback of rtmidi is called.
+func (i *in) ListenTimestamp(onMsg func(msg []byte, ts drivers.Timestamp), config drivers.ListenConfig) (stopFn func(), err error) {
+
+	if onMsg == nil {
+		return nil, fmt.Errorf("onMsg callback must not be nil")
+	}
+
+	return i.listen(onMsg, config)
+}
+
+func (i *in) listen(onMsg func(msg []byte, ts drivers.Timestamp), config drivers.ListenConfig) (stopFn func(), err error) {
+
 	i.midiIn.IgnoreTypes(!config.SysEx, !config.TimeCode, !config.ActiveSense)
+	i.onErr = config.OnErr
 
 	//var inSysEx bool
 	if config.SysExBufferSize == 0 {
@@ -151,7 +178,9 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 		var typ uint8
 	*/
 
-	var rd = drivers.NewReader(config, onMsg)
+	var rd = drivers.NewReader(config, nil)
+	rd.OnTimestamp = onMsg
+	var since time.Duration
 	/*
 		rd.OnErr = config.OnErr
 		rd.OnSysEx = config.OnSysEx
@@ -164,6 +193,7 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 		// lockless sync
 		//	atomic.StoreInt32(&stop, 1)
 		//	fmt.Println("stopping")
+		i.onErr = nil
 		i.midiIn.CancelCallback()
 		//time.Sleep(stopWait)
 	}
@@ -318,7 +348,8 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 			}
 		*/
 
-		rd.EachMessage(bt, int32(math.Round(deltaSeconds*1000)))
+		since += time.Duration(math.Round(deltaSeconds * float64(time.Second)))
+		rd.EachMessageAt(bt, drivers.Timestamp{Since: since, Time: time.Now()})
 
 		/*
 			// TODO: verify
@@ -0,0 +1,205 @@
+package drivers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultSysExMaxSize is the maximum size of a sysex message (in bytes, including 0xF0 and 0xF7),
+// if ListenConfig.SysExMaxSize is 0.
+const DefaultSysExMaxSize = 1 << 20
+
+var (
+	// ErrSysExAborted is the error for a sysex message that is interrupted by a status byte other than 0xF7 (see SysExAbortReport).
+	ErrSysExAborted = errors.New("sysex aborted")
+
+	// ErrSysExTooLarge is the error for a sysex message that exceeds the maximum size (see ListenConfig.SysExMaxSize).
+	ErrSysExTooLarge = errors.New("sysex message too large")
+)
+
+// SysExAbortedError is passed to the OnErr callback of the ListenConfig for an aborted sysex message,
+// if the SysExAbort policy is SysExAbortReport.
+type SysExAbortedError struct {
+	// Data is the sysex data that has been received (starting with 0xF0).
+	// When the sysex is passed in chunks, it is only the data that has not been passed yet.
+	Data []byte
+
+	// Status is the status byte that interrupted the sysex message
+	Status byte
+}
+
+// Error returns the error message
+func (e *SysExAbortedError) Error() string {
+	return fmt.Sprintf("%s by status %X: % X", ErrSysExAborted.Error(), e.Status, e.Data)
+}
+
+// Unwrap returns ErrSysExAborted, so that errors.Is can be used.
+func (e *SysExAbortedError) Unwrap() error {
+	return ErrSysExAborted
+}
+
+// SysExTooLargeError is passed to the OnErr callback of the ListenConfig for a sysex message that exceeds
+// the maximum size. The sysex message is skipped and the error is passed when the sysex message has ended.
+type SysExTooLargeError struct {
+	// Length is the length of the skipped sysex message (in bytes, including 0xF0 and 0xF7)
+	Length int
+
+	// Limit is the maximum size that has been exceeded
+	Limit int
+
+	// Manufacturer is the manufacturer ID of the sysex message (one byte or three bytes starting with 0x00)
+	Manufacturer []byte
+}
+
+// Error returns the error message
+func (e *SysExTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %v bytes (limit %v) from manufacturer % X", ErrSysExTooLarge.Error(), e.Length, e.Limit, e.Manufacturer)
+}
+
+// Unwrap returns ErrSysExTooLarge, so that errors.Is can be used.
+func (e *SysExTooLargeError) Unwrap() error {
+	return ErrSysExTooLarge
+}
+
+// SysExChunk is a part of a sysex message that is passed to ListenConfig.OnSysExChunk.
+type SysExChunk struct {
+	// Data are the bytes of the chunk. The data of the first chunk starts with 0xF0 and the data of the final chunk
+	// ends with 0xF7 (unless the sysex message is aborted).
+	Data []byte
+
+	// Seq is the sequence number of the chunk within the sysex message, starting with 0.
+	Seq int
+
+	// Final is true for the last chunk of the sysex message.
+	Final bool
+
+	// Aborted is true for the final chunk of a sysex message that has been interrupted by a status byte
+	// and that is not treated as complete (see SysExAbortPolicy).
+	Aborted bool
+
+	// Timestamp is the time when the sysex message has been started
+	Timestamp Timestamp
+}
+
+// SysExAbortPolicy is the policy for a sysex message that is terminated by a status byte other than 0xF7.
+// Sysex messages on a MIDI 1.0 DIN cable may drop the 0xF7 ("dropped 0xF7") and any status byte (except
+// realtime messages) terminates the sysex message. The status byte is then handled as the start of the next message.
+type SysExAbortPolicy int
+
+const (
+	// SysExAbortDiscard discards the interrupted sysex message (the default).
+	SysExAbortDiscard SysExAbortPolicy = iota
+
+	// SysExAbortComplete treats the interrupted sysex message as complete and passes it with an added 0xF7.
+	SysExAbortComplete
+
+	// SysExAbortReport discards the interrupted sysex message and passes a *SysExAbortedError to the OnErr callback.
+	SysExAbortReport
+)
+
+func (r *Reader) startSysEx() {
+	r.sysexBf = make([]byte, 0, r.SysExBufferSize)
+	r.sysexBf = append(r.sysexBf, 0xF0)
+	r.sysexHead = [4]byte{0xF0}
+	r.sysexTotal = 1
+	r.sysexSeq = 0
+	r.sysexTooLarge = false
+	r.sysexTS = r.ts
+}
+
+// writeSysEx adds the given byte to the sysex buffer. When the sysex is passed in chunks, the full buffer is passed
+// as chunk before. Otherwise the sysex is skipped if it exceeds the maximum size.
+func (r *Reader) writeSysEx(b byte) {
+	if r.sysexTotal < len(r.sysexHead) {
+		r.sysexHead[r.sysexTotal] = b
+	}
+	r.sysexTotal++
+
+	switch {
+	case r.sysexTooLarge:
+	case r.OnSysExChunk != nil:
+		if len(r.sysexBf) >= int(r.SysExBufferSize) {
+			r.chunkSysEx(false, false)
+		}
+		r.sysexBf = append(r.sysexBf, b)
+	case len(r.sysexBf) >= int(r.SysExMaxSize):
+		r.sysexTooLarge = true
+		r.sysexBf = nil
+	default:
+		r.sysexBf = append(r.sysexBf, b)
+	}
+}
+
+// chunkSysEx passes the buffered sysex data as chunk and starts a new buffer
+func (r *Reader) chunkSysEx(final, aborted bool) {
+	r.OnSysExChunk(SysExChunk{
+		Data:      r.sysexBf,
+		Seq:       r.sysexSeq,
+		Final:     final,
+		Aborted:   aborted,
+		Timestamp: r.sysexTS,
+	})
+
+	r.sysexSeq++
+	r.sysexBf = make([]byte, 0, r.SysExBufferSize)
+}
+
+// completeSysEx passes the buffered sysex with an added 0xF7
+func (r *Reader) completeSysEx() {
+	r.writeSysEx(0xF7)
+
+	switch {
+	case r.sysexTooLarge:
+		r.tooLargeSysEx()
+	case r.OnSysExChunk != nil:
+		r.chunkSysEx(true, false)
+	default:
+		r.emitAt(r.sysexBf, r.sysexTS)
+	}
+}
+
+// abortSysEx handles the buffered sysex that is interrupted by the given status byte, according to the SysExAbort policy
+func (r *Reader) abortSysEx(status byte) {
+	if r.sysexTooLarge {
+		r.tooLargeSysEx()
+		return
+	}
+
+	switch r.SysExAbort {
+	case SysExAbortComplete:
+		r.completeSysEx()
+		return
+	case SysExAbortReport:
+		if r.OnErr != nil {
+			var data = make([]byte, len(r.sysexBf))
+			copy(data, r.sysexBf)
+			r.OnErr(&SysExAbortedError{Data: data, Status: status})
+		}
+	}
+
+	if r.OnSysExChunk != nil {
+		r.chunkSysEx(true, true)
+	}
+}
+
+func (r *Reader) tooLargeSysEx() {
+	if r.OnErr == nil {
+		return
+	}
+
+	avail := r.sysexTotal
+	if avail > len(r.sysexHead) {
+		avail = len(r.sysexHead)
+	}
+
+	man := r.sysexHead[1:avail]
+	if len(man) > 1 && man[0] != 0 {
+		man = man[:1]
+	}
+
+	r.OnErr(&SysExTooLargeError{
+		Length:       r.sysexTotal,
+		Limit:        int(r.SysExMaxSize),
+		Manufacturer: append([]byte(nil), man...),
+	})
+}
@@ -25,10 +25,11 @@ type Driver struct {
 	in            *in
 	out           *out
 	name          string
-	last          time.Time
+	start         time.Time
 	now           time.Time
 	stopListening bool
 	rd            *drivers.Reader
+	onErr         func(error)
 	//wg            sync.WaitGroup
 }
 
@@ -36,11 +37,11 @@ func New(name string) *Driver {
 	d := &Driver{name: name}
 	d.in = &in{name: name + "-in", Driver: d, number: 0}
 	d.out = &out{name: name + "-out", Driver: d, number: 0}
-	d.last = time.Now()
-	d.now = d.last
+	d.now = time.Now()
 	return d
 }
 
+// Sleep advances the clock of the driver that is used for the timestamps of the received messages.
 func (f *Driver) Sleep(d time.Duration) {
 	f.now = f.now.Add(d)
 }
@@ -70,15 +71,29 @@ func (f *in) IsOpen() bool            { return f.isOpen }
 func (f *in) Underlying() interface{} { return nil }
 
 func (f *in) Listen(onMsg func(msg []byte, milliseconds int32), conf drivers.ListenConfig) (stopFn func(), err error) {
+	return f.listen(conf, func(m []byte, ts drivers.Timestamp) {
+		onMsg(m, ts.Milliseconds())
+	})
+}
+
+// ListenTimestamp implements drivers.TimestampIn. The timestamps are taken from the clock of the driver (see Sleep).
+func (f *in) ListenTimestamp(onMsg func(msg []byte, ts drivers.Timestamp), conf drivers.ListenConfig) (stopFn func(), err error) {
+	return f.listen(conf, onMsg)
+}
+
+func (f *in) listen(conf drivers.ListenConfig, onMsg func(msg []byte, ts drivers.Timestamp)) (stopFn func(), err error) {
 	//fmt.Printf("listeining from in port of %s\n", f.Driver.name)
 
-	f.last = time.Now()
+	f.start = f.now
 
 	stopFn = func() {
 		f.stopListening = true
 	}
 
-	f.rd = drivers.NewReader(conf, func(m []byte, ms int32) {
+	f.stopListening = false
+	f.onErr = conf.OnErr
+	f.rd = drivers.NewReader(conf, nil)
+	f.rd.OnTimestamp = func(m []byte, ts drivers.Timestamp) {
 		msg := midi.Message(m)
 
 		if msg.Is(midi.ActiveSenseMsg) && !conf.ActiveSense {
@@ -93,11 +108,11 @@ func (f *in) Listen(onMsg func(msg []byte, milliseconds int32), conf drivers.Lis
 			return
 		}
 
-		//fmt.Printf("handle message % X at [%v] in driver %q\n", m, ms, f.Driver.name)
-		onMsg(m, ms)
+		//fmt.Printf("handle message % X at [%v] in driver %q\n", m, ts, f.Driver.name)
+		onMsg(m, ts)
 		//	f.wg.Done()
 		//fmt.Println("msg handled")
-	})
+	}
 	f.rd.Reset()
 	return stopFn, nil
 }
@@ -107,6 +122,13 @@ func (f *in) Close() error {
 		return nil
 	}
 	f.isOpen = false
+
+	if f.rd != nil && !f.stopListening {
+		f.stopListening = true
+		if f.onErr != nil {
+			f.onErr(drivers.ErrListenStopped)
+		}
+	}
 	return nil
 }
 
@@ -147,12 +169,9 @@ func (f *out) Send(bt []byte) error {
 		return nil
 	}
 
-	dur := f.now.Sub(f.last)
-	ts_ms := int32(dur.Milliseconds())
-	f.last = f.now
 	//f.wg.Add(1)
-	//fmt.Printf("message added % X (len %v) at [%v] in driver %q\n", bt, len(bt), ts_ms, f.Driver.name)
-	f.rd.EachMessage(bt, ts_ms)
+	//fmt.Printf("message added % X (len %v) at [%v] in driver %q\n", bt, len(bt), f.now, f.Driver.name)
+	f.rd.EachMessageAt(bt, drivers.Timestamp{Since: f.now.Sub(f.start), Time: f.now})
 	/*
 		f.rd.SetDelta(ts_ms)
 		for _, b := range bt {
@@ -162,6 +181,11 @@ func (f *out) Send(bt []byte) error {
 	return nil
 }
 
+// SendRaw implements drivers.RawOut. Since the in port reads the data byte by byte, it is the same as Send.
+func (f *out) SendRaw(bt []byte) error {
+	return f.Send(bt)
+}
+
 func (f *out) Open() error {
 	if f.isOpen {
 		return nil
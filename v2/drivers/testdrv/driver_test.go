@@ -51,6 +51,18 @@ func TestSpec(t *testing.T) {
 			"NoSysex",
 			drivertest.NoSysexTest,
 		},
+		{
+			"LargeSysex",
+			drivertest.LargeSysexTest,
+		},
+		{
+			"RawSysex",
+			drivertest.RawSysexTest,
+		},
+		{
+			"Timestamp",
+			drivertest.TimestampTest,
+		},
 	}
 
 	for _, test := range tests {
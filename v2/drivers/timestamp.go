@@ -0,0 +1,51 @@
+package drivers
+
+import (
+	"time"
+)
+
+// Timestamp is the time when a message has been received.
+type Timestamp struct {
+	// Since is the monotonic time that has passed since the listening started.
+	// Its resolution is a microsecond or better.
+	Since time.Duration
+
+	// Time is the wall clock time of the reception. It is the zero time, if the driver can't provide it.
+	Time time.Time
+}
+
+// Milliseconds returns Since in milliseconds, as it is passed to the callback of In.Listen.
+func (ts Timestamp) Milliseconds() int32 {
+	return int32(ts.Since.Milliseconds())
+}
+
+// TimestampIn is an optional interface for in ports that provide high resolution timestamps.
+// The Since field of the timestamps must never decrease and the milliseconds passed by Listen
+// must be the Milliseconds of the corresponding timestamps.
+type TimestampIn interface {
+	// ListenTimestamp is like Listen, but passes the Timestamp of the received messages.
+	ListenTimestamp(
+		onMsg func(msg []byte, ts Timestamp),
+		config ListenConfig,
+	) (
+		stopFn func(),
+		err error,
+	)
+}
+
+// ListenTimestamp listens on the given port and passes the Timestamp of the received messages.
+// If the port does not implement TimestampIn, the timestamps are taken from the monotonic clock when the driver
+// passes the message, since the call of ListenTimestamp. They then include the latency of the driver and the Time
+// is the time.Now() of the reception.
+func ListenTimestamp(in In, onMsg func(msg []byte, ts Timestamp), config ListenConfig) (stopFn func(), err error) {
+	if tin, ok := in.(TimestampIn); ok {
+		return tin.ListenTimestamp(onMsg, config)
+	}
+
+	start := time.Now()
+
+	return in.Listen(func(msg []byte, _ int32) {
+		now := time.Now()
+		onMsg(msg, Timestamp{Since: now.Sub(start), Time: now})
+	}, config)
+}
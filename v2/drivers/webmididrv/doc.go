@@ -5,5 +5,9 @@
 /*
 Package webmididrv provides a Driver to connect to MIDI ports in the browser (via webmidi).
 See the example to get an idea how to use it.
+
+The driver requests the access to the MIDI ports including sysex messages, which needs the permission of the user.
+If it is not granted, the driver works without sysex messages. Received messages pass a drivers.Reader, so that
+the sysex options of the drivers.ListenConfig (SysExMaxSize, OnSysExChunk and SysExAbort) are supported.
 */
 package webmididrv
@@ -26,6 +26,7 @@ type Driver struct {
 	inputsJS  js.Value
 	outputsJS js.Value
 	wg        sync.WaitGroup
+	sysex     bool // the access includes sysex messages
 	Err       error
 }
 
@@ -61,23 +62,38 @@ func New() (*Driver, error) {
 		return nil, fmt.Errorf("Unable to get navigator object")
 	}
 
-	// currently sysex messages are not allowed in the browser implementations
-	var opts = map[string]interface{}{
-		"sysex": "false",
+	drv := &Driver{}
+
+	// sysex messages need the permission of the user, without it the access is requested again without sysex
+	err := drv.requestAccess(jsDoc, true)
+	if err == nil && drv.Err != nil {
+		drv.Err = nil
+		err = drv.requestAccess(jsDoc, false)
+	}
+
+	if err != nil {
+		return nil, err
 	}
 
-	jsOpts := js.ValueOf(opts)
+	return drv, nil
+}
 
-	midiaccess := jsDoc.Call("requestMIDIAccess", jsOpts)
+// requestAccess requests the access to the MIDI ports and waits for the answer
+func (d *Driver) requestAccess(jsDoc js.Value, sysex bool) error {
+	var opts = map[string]interface{}{
+		"sysex": sysex,
+	}
+
+	midiaccess := jsDoc.Call("requestMIDIAccess", js.ValueOf(opts))
 	if !midiaccess.Truthy() {
-		return nil, fmt.Errorf("unable to get requestMIDIAccess")
+		return fmt.Errorf("unable to get requestMIDIAccess")
 	}
 
-	drv := &Driver{}
-	drv.wg.Add(1)
-	midiaccess.Call("then", drv.onMIDISuccess(), drv.onMIDIFailure())
-	drv.wg.Wait()
-	return drv, nil
+	d.sysex = sysex
+	d.wg.Add(1)
+	midiaccess.Call("then", d.onMIDISuccess(), d.onMIDIFailure())
+	d.wg.Wait()
+	return nil
 }
 
 func (d *Driver) onMIDISuccess() js.Func {
@@ -75,6 +75,14 @@ func TestSpec(t *testing.T) {
 				drivertest.NoSysexTest,
 			},
 		*/
+		{
+			"LargeSysex",
+			drivertest.LargeSysexTest,
+		},
+		{
+			"Timestamp",
+			drivertest.TimestampTest,
+		},
 	}
 
 	for _, test := range tests {
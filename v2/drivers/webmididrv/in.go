@@ -4,12 +4,11 @@
 package webmididrv
 
 import (
-	"math"
 	"sync"
 	"sync/atomic"
 	"syscall/js"
+	"time"
 
-	"gitlab.com/gomidi/midi/v2"
 	"gitlab.com/gomidi/midi/v2/drivers"
 )
 
@@ -21,6 +20,7 @@ type in struct {
 	isOpen   bool
 	jsport   js.Value
 	listener func(data []byte, timestamp int32)
+	onErr    func(error)
 }
 
 // IsOpen returns wether the MIDI in port is open
@@ -57,7 +57,13 @@ func (i *in) Close() (err error) {
 	i.Lock()
 	i.isOpen = false
 	i.jsport.Call("close")
+	onErr := i.onErr
+	i.onErr = nil
 	i.Unlock()
+
+	if onErr != nil {
+		onErr(drivers.ErrListenStopped)
+	}
 	return
 }
 
@@ -118,12 +124,24 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 		// lockless sync
 		atomic.StoreInt32(&stop, 1)
 		//time.Sleep(stopWait)
+
+		i.Lock()
+		i.onErr = nil
+		i.Unlock()
 	}
 
 	i.Lock()
+	i.onErr = config.OnErr
 
 	var stopped int32
 
+	// the Reader takes care of the running status, the sysex buffering and the sysex options of the config
+	rd := drivers.NewReader(config, onMsg)
+
+	// the timestamps of the events are relative to the time origin of the page, so they are taken relative to
+	// the start of the listening
+	start := js.Global().Get("performance").Call("now").Float()
+
 	jsCallback := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
 		// lockless sync
 		stopped = atomic.LoadInt32(&stop)
@@ -133,43 +151,27 @@ func (i *in) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.L
 		}
 
 		jsdata := args[0].Get("data")
-		jstime := args[0].Get("receivedTime")
+		jstime := args[0].Get("timeStamp")
 
-		//var data = make([]byte, 3)
-		var data []byte
-		if !jsdata.Index(0).IsUndefined() {
-			//data[0] = byte(jsdata.Index(0).Int())
-			data = append(data, byte(jsdata.Index(0).Int()))
-		}
-		if !jsdata.Index(1).IsUndefined() {
-			//data[1] = byte(jsdata.Index(1).Int())
-			data = append(data, byte(jsdata.Index(1).Int()))
-		}
-		if !jsdata.Index(2).IsUndefined() {
-			//data[2] = byte(jsdata.Index(2).Int())
-			data = append(data, byte(jsdata.Index(2).Int()))
+		var data = make([]byte, jsdata.Get("length").Int())
+		for n := range data {
+			data[n] = byte(jsdata.Index(n).Int())
 		}
-		var t = int32(-1)
-		if jstime.Truthy() {
-			// round to milliseconds
-			t = int32(math.Round(jstime.Float()))
-		}
-
-		msg := midi.Message(data)
 
-		if msg.Is(midi.ActiveSenseMsg) && !config.ActiveSense {
+		if len(data) == 1 && data[0] == 0xFE && !config.ActiveSense {
 			return nil
 		}
 
-		if msg.Is(midi.TimingClockMsg) && !config.TimeCode {
+		if len(data) == 1 && data[0] == 0xF8 && !config.TimeCode {
 			return nil
 		}
 
-		if msg.Is(midi.SysExMsg) && !config.SysEx {
-			return nil
+		ts := drivers.Timestamp{Time: time.Now()}
+		if jstime.Truthy() && jstime.Float() > start {
+			ts.Since = time.Duration((jstime.Float() - start) * float64(time.Millisecond))
 		}
 
-		onMsg(data, t)
+		rd.EachMessageAt(data, ts)
 		return nil
 	})
 
@@ -99,7 +99,7 @@ func (o *out) Open() (err error) {
 	//o.running = runningstatus.NewLiveWriter(&o.bf)
 	var conf drivers.ListenConfig
 	conf.ActiveSense = true
-	conf.SysEx = false
+	conf.SysEx = o.driver.sysex
 	conf.TimeCode = true
 	o.running = drivers.NewReader(conf, func(b []byte, ms int32) {
 		o.bf.Write(b)
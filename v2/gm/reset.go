@@ -26,15 +26,7 @@ func GMProgram(ch, prog uint8) (msgs []midi.Message) {
 	 cc pan position 64
 */
 func Reset(ch, prog uint8) []midi.Message {
-	return []midi.Message{
-		midi.ControlChange(ch, midi.BankSelectMSB, 0),
-		midi.ProgramChange(ch, prog),
-		midi.ControlChange(ch, midi.AllControllersOff, 0),
-		midi.ControlChange(ch, midi.VolumeMSB, 100),
-		midi.ControlChange(ch, midi.ExpressionMSB, 127),
-		midi.ControlChange(ch, midi.HoldPedalSwitch, 0),
-		midi.ControlChange(ch, midi.PanPositionMSB, 64),
-	}
+	return midi.ResetChannel(ch, 0, prog)
 }
 
 /*
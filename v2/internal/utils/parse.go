@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fields are the labeled values of a message string, like "channel: 0 key: 60".
+type Fields map[string]string
+
+var regLabel = regexp.MustCompile(` [a-z]+: `)
+
+// ParseFields parses a string of the form "Name label: value label: value ..." as it is returned
+// by the String methods of the messages. Values may contain spaces (e.g. hex bytes) or be quoted strings.
+func ParseFields(s string) (name string, fields Fields, err error) {
+	s = strings.TrimSpace(s)
+	fields = Fields{}
+
+	// empty value at the end
+	if strings.HasSuffix(s, ":") {
+		s += " "
+	}
+
+	idx := strings.IndexByte(s, ' ')
+	if idx < 0 {
+		return s, fields, nil
+	}
+
+	name, s = s[:idx], s[idx:]
+
+	for len(s) > 0 {
+		loc := regLabel.FindStringIndex(s)
+		if loc == nil || loc[0] != 0 {
+			return "", nil, fmt.Errorf("invalid field %q", strings.TrimSpace(s))
+		}
+
+		label := s[1 : loc[1]-2]
+		s = s[loc[1]:]
+
+		if strings.HasPrefix(s, `"`) {
+			q, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid quoted value of %q: %w", label, err)
+			}
+			fields[label], _ = strconv.Unquote(q)
+			s = s[len(q):]
+			continue
+		}
+
+		next := regLabel.FindStringIndex(s)
+		if next == nil {
+			fields[label] = strings.TrimSpace(s)
+			break
+		}
+
+		fields[label] = strings.TrimSpace(s[:next[0]])
+		s = s[next[0]:]
+	}
+
+	return name, fields, nil
+}
+
+// Uint returns the value of the given label as unsigned integer, that must not be greater than max.
+// Only the first word of the value is parsed.
+func (f Fields) Uint(label string, max uint64) (uint64, error) {
+	v, has := f[label]
+	if !has {
+		return 0, fmt.Errorf("missing %q", label)
+	}
+
+	if idx := strings.IndexByte(v, ' '); idx > 0 {
+		v = v[:idx]
+	}
+
+	i, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q: %w", label, err)
+	}
+
+	if i > max {
+		return 0, fmt.Errorf("invalid %q: %v is greater than %v", label, i, max)
+	}
+
+	return i, nil
+}
+
+// Int returns the value of the given label as signed integer within the given range.
+// Only the first word of the value is parsed.
+func (f Fields) Int(label string, min, max int64) (int64, error) {
+	v, has := f[label]
+	if !has {
+		return 0, fmt.Errorf("missing %q", label)
+	}
+
+	if idx := strings.IndexByte(v, ' '); idx > 0 {
+		v = v[:idx]
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q: %w", label, err)
+	}
+
+	if i < min || i > max {
+		return 0, fmt.Errorf("invalid %q: %v is not within %v and %v", label, i, min, max)
+	}
+
+	return i, nil
+}
+
+// Bytes returns the value of the given label as bytes, that are written as hex numbers separated by spaces.
+func (f Fields) Bytes(label string) ([]byte, error) {
+	v, has := f[label]
+	if !has {
+		return nil, fmt.Errorf("missing %q", label)
+	}
+
+	bt, err := hex.DecodeString(strings.ReplaceAll(v, " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q: %w", label, err)
+	}
+	return bt, nil
+}
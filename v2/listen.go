@@ -1,6 +1,8 @@
 package midi
 
 import (
+	"time"
+
 	"gitlab.com/gomidi/midi/v2/drivers"
 	midilib "gitlab.com/gomidi/midi/v2/internal/utils"
 )
@@ -39,13 +41,40 @@ type listeningOptions struct {
 	// SysEx lets the system exclusive messages pass through, if set
 	SysEx bool
 
-	// SysExBufferSize defines the size of the buffer for sysex messages (in bytes).
-	// SysEx messages larger than this size will be ignored.
+	// Undefined lets the undefined system common messages pass through, if set
+	Undefined bool
+
+	// SysExAbort is the policy for sysex messages that are interrupted by a status byte other than 0xF7
+	SysExAbort SysExAbortPolicy
+
+	// SysExBufferSize defines the initial size of the buffer for sysex messages (in bytes) and the size of the chunks.
 	// When SysExBufferSize is 0, the default buffersize (1024) is used.
 	SysExBufferSize uint32
 
+	// SysExMaxSize defines the maximum size of sysex messages (in bytes).
+	// When SysExMaxSize is 0, drivers.DefaultSysExMaxSize is used.
+	SysExMaxSize uint32
+
+	// OnSysExChunk receives the sysex messages in chunks, if set
+	OnSysExChunk func(SysExChunk)
+
 	// OnError handles occuring errors
 	OnError func(error)
+
+	// ActiveSenseTimeout is the timeout of the connection after an active sense message, if it is watched
+	ActiveSenseTimeout time.Duration
+
+	// OnConnectionLost is called, when the watched connection is lost
+	OnConnectionLost func()
+
+	// SilenceOut receives the silence messages, when the watched connection is lost
+	SilenceOut drivers.Out
+
+	// ChannelSize is the buffer size of the channel of a Listener
+	ChannelSize int
+
+	// Overflow is the policy of a Listener, when its channel is full
+	Overflow Overflow
 }
 
 // Option is an option for listening
@@ -72,13 +101,71 @@ func UseSysEx() Option {
 	}
 }
 
-// SysExBufferSize is an option to set the buffer size for sysex messages
+// SysExBufferSize is an option to set the initial buffer size for sysex messages. The buffer grows up to the
+// maximum size (see SysExMaxSize).
 func SysExBufferSize(size uint32) Option {
 	return func(l *listeningOptions) {
 		l.SysExBufferSize = size
 	}
 }
 
+// SysExMaxSize is an option to set the maximum size for sysex messages (in bytes, including 0xF0 and 0xF7).
+// Larger sysex messages are skipped and a *drivers.SysExTooLargeError (that contains the length and the manufacturer ID
+// of the sysex message) is passed to the error handler (see HandleError).
+func SysExMaxSize(size uint32) Option {
+	return func(l *listeningOptions) {
+		l.SysExMaxSize = size
+	}
+}
+
+// SysExChunk is a part of a sysex message (see UseSysExChunks).
+type SysExChunk = drivers.SysExChunk
+
+// UseSysExChunks is an option to receive the sysex messages in chunks of the sysex buffer size (see SysExBufferSize)
+// instead of receiving them as a whole, so that large dumps don't have to be buffered. The chunks are passed to
+// the given callback instead of the receiver of the listening. The maximum size does not apply to sysex messages
+// that are passed in chunks. UseSysExChunks implies UseSysEx.
+func UseSysExChunks(cb func(chunk SysExChunk)) Option {
+	return func(l *listeningOptions) {
+		l.SysEx = true
+		l.OnSysExChunk = cb
+	}
+}
+
+// UseUndefined is an option to receive the undefined system common messages (0xF4 and 0xF5, including their data bytes).
+// The undefined realtime message 0xFD is always passed, like any other realtime message.
+// Since the length of the undefined system common messages is not defined,
+// they are passed when the next status byte is received.
+func UseUndefined() Option {
+	return func(l *listeningOptions) {
+		l.Undefined = true
+	}
+}
+
+// SysExAbortPolicy is the policy for sysex messages that are interrupted by a status byte other than 0xF7
+// (see drivers.SysExAbortPolicy).
+type SysExAbortPolicy = drivers.SysExAbortPolicy
+
+const (
+	// SysExAbortDiscard discards the interrupted sysex message (the default).
+	SysExAbortDiscard = drivers.SysExAbortDiscard
+
+	// SysExAbortComplete treats the interrupted sysex message as complete ("dropped 0xF7") and passes it with an added 0xF7.
+	SysExAbortComplete = drivers.SysExAbortComplete
+
+	// SysExAbortReport discards the interrupted sysex message and passes a *drivers.SysExAbortedError to the error handler
+	// (see HandleError).
+	SysExAbortReport = drivers.SysExAbortReport
+)
+
+// SysExAbort is an option to set the policy for sysex messages that are interrupted by a status byte other than 0xF7.
+// In any case the status byte is handled as the start of the next message.
+func SysExAbort(policy SysExAbortPolicy) Option {
+	return func(l *listeningOptions) {
+		l.SysExAbort = policy
+	}
+}
+
 // HandleError sets an error handler when receiving messages
 func HandleError(cb func(error)) Option {
 	return func(l *listeningOptions) {
@@ -88,40 +175,89 @@ func HandleError(cb func(error)) Option {
 
 var ErrPortClosed = drivers.ErrPortClosed
 var ErrListenStopped = drivers.ErrListenStopped
+var ErrSysExAborted = drivers.ErrSysExAborted
+
+// Timestamp is the time when a message has been received (see ListenTimestamp).
+type Timestamp = drivers.Timestamp
 
 // ListenTo listens on the given port and passes the received MIDI data to the given receiver.
 // It returns a stop function that may be called to stop the listening.
 func ListenTo(inPort drivers.In, recv func(msg Message, timestampms int32), opts ...Option) (stop func(), err error) {
+	opt, conf, err := prepareListening(inPort, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	convert := newConverter(conf.Undefined)
+	watchdog := newWatchdog(opt, &conf)
+
+	stop, err = inPort.Listen(func(data []byte, millisec int32) {
+		if msg, ok := convert(data); ok && watchdog.received(msg) {
+			recv(msg, millisec)
+		}
+	}, conf)
+
+	return watchdog.wrap(stop), err
+}
+
+// ListenTimestamp is like ListenTo, but passes the high resolution Timestamp of the received messages.
+// If the driver of the port does not provide timestamps, they are taken when the driver passes the message
+// (see drivers.ListenTimestamp).
+func ListenTimestamp(inPort drivers.In, recv func(msg Message, ts Timestamp), opts ...Option) (stop func(), err error) {
+	opt, conf, err := prepareListening(inPort, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	convert := newConverter(conf.Undefined)
+	watchdog := newWatchdog(opt, &conf)
+
+	stop, err = drivers.ListenTimestamp(inPort, func(data []byte, ts Timestamp) {
+		if msg, ok := convert(data); ok && watchdog.received(msg) {
+			recv(msg, ts)
+		}
+	}, conf)
+
+	return watchdog.wrap(stop), err
+}
+
+// prepareListening opens the port, if necessary, and returns the options and the ListenConfig for the options
+func prepareListening(inPort drivers.In, opts []Option) (opt listeningOptions, conf drivers.ListenConfig, err error) {
 	if !inPort.IsOpen() {
 		err = inPort.Open()
 
 		if err != nil {
-			return nil, err
+			return opt, conf, err
 		}
 	}
 
-	var opt listeningOptions
 	for _, o := range opts {
 		o(&opt)
 	}
 
-	var conf drivers.ListenConfig
 	conf.SysExBufferSize = opt.SysExBufferSize
+	conf.SysExMaxSize = opt.SysExMaxSize
+	conf.OnSysExChunk = opt.OnSysExChunk
 	conf.TimeCode = opt.TimeCode
 	conf.ActiveSense = opt.ActiveSense
 	conf.SysEx = opt.SysEx
+	conf.Undefined = opt.Undefined
+	conf.SysExAbort = opt.SysExAbort
 	conf.OnErr = opt.OnError
+	return opt, conf, nil
+}
 
+// newConverter returns a function that converts the data passed by a driver to a Message, respecting the running status.
+// The undefined system common messages are skipped (ok == false), unless they are passed through.
+func newConverter(undefined bool) func(data []byte) (msg Message, ok bool) {
 	var isStatusSet bool
 	var typ, channel byte
 
-	var onMsg = func(data []byte, millisec int32) {
+	return func(data []byte) (msg Message, ok bool) {
 		status := data[0]
-
-		var msg Message
 		switch {
 
-		// realtime message
+		// realtime message (including the undefined 0xFD)
 		case status >= 0xF8:
 			msg = []byte{status}
 
@@ -139,8 +275,11 @@ func ListenTo(inPort drivers.In, recv func(msg Message, timestampms int32), opts
 			case byteSysSongSelect:
 				msg = SongSelect(data[1])
 			default:
-				// undefined syscommon message
-				//				msg = NewUndefined()
+				// undefined syscommon message (0xF4 or 0xF5) with its data bytes
+				if !undefined {
+					return nil, false
+				}
+				msg = Message(data)
 			}
 
 		// [MIDI] permits 0xF7 octets that are not part of a (0xF0, 0xF7) pair
@@ -167,8 +306,6 @@ func ListenTo(inPort drivers.In, recv func(msg Message, timestampms int32), opts
 			}
 		}
 
-		recv(msg, millisec)
+		return msg, true
 	}
-
-	return inPort.Listen(onMsg, conf)
 }
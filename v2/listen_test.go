@@ -0,0 +1,252 @@
+package midi_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+func TestListenTimestamp(t *testing.T) {
+	drv := testdrv.New("listen-timestamp")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	var bf strings.Builder
+	var start time.Time
+
+	stop, err := midi.ListenTimestamp(ins[0], func(msg midi.Message, ts midi.Timestamp) {
+		if start.IsZero() {
+			start = ts.Time.Add(-ts.Since)
+		}
+		fmt.Fprintf(&bf, "%s since: %v ms: %v time: %v\n", msg, ts.Since, ts.Milliseconds(), ts.Time.Sub(start))
+	})
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	drv.Sleep(1500 * time.Microsecond)
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+	drv.Sleep(250 * time.Microsecond)
+	// running status
+	outs[0].Send([]byte{0x91, 62, 100, 64, 100})
+	drv.Sleep(2 * time.Second)
+	outs[0].Send(midi.NoteOff(1, 60))
+	stop()
+
+	want := `NoteOn channel: 1 key: 60 velocity: 100 since: 1.5ms ms: 1 time: 1.5ms
+NoteOn channel: 1 key: 62 velocity: 100 since: 1.75ms ms: 1 time: 1.75ms
+NoteOn channel: 1 key: 64 velocity: 100 since: 1.75ms ms: 1 time: 1.75ms
+NoteOff channel: 1 key: 60 since: 2.00175s ms: 2001 time: 2.00175s
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestListenUndefined(t *testing.T) {
+	tests := []struct {
+		undefined bool
+		want      string
+	}{
+		{
+			false,
+			`NoteOn channel: 1 key: 60 velocity: 100
+reservedRealTime8
+NoteOn channel: 1 key: 62 velocity: 100
+Start
+NoteOff channel: 1 key: 60
+`,
+		},
+		{
+			true,
+			`NoteOn channel: 1 key: 60 velocity: 100
+reservedRealTime8
+reservedSysCommon5 data: 01 02
+NoteOn channel: 1 key: 62 velocity: 100
+Start
+reservedSysCommon6
+NoteOff channel: 1 key: 60
+`,
+		},
+	}
+
+	for _, test := range tests {
+		drv := testdrv.New("listen-undefined")
+		ins, _ := drv.Ins()
+		outs, _ := drv.Outs()
+		outs[0].Open()
+
+		var opts []midi.Option
+		if test.undefined {
+			opts = append(opts, midi.UseUndefined())
+		}
+
+		var bf strings.Builder
+
+		stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+			fmt.Fprintf(&bf, "%s\n", msg)
+		}, opts...)
+
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+
+		outs[0].Send(midi.NoteOn(1, 60, 100))
+		// the realtime message 0xFD is passed immediately, the 0xF4 with the next status byte
+		outs[0].Send([]byte{0xF4, 0x01, 0xFD, 0x02})
+		outs[0].Send(midi.NoteOn(1, 62, 100))
+		outs[0].Send([]byte{0xFA, 0xF5})
+		outs[0].Send(midi.NoteOff(1, 60))
+		stop()
+
+		if got := bf.String(); got != test.want {
+			t.Errorf("[undefined: %v]\ngot:\n%s\nwant:\n%s", test.undefined, got, test.want)
+		}
+	}
+}
+
+func TestListenSysExAbort(t *testing.T) {
+	tests := []struct {
+		policy midi.SysExAbortPolicy
+		want   string
+	}{
+		{
+			midi.SysExAbortDiscard,
+			`NoteOn channel: 0 key: 60 velocity: 100
+SysExType data: 7E 06
+`,
+		},
+		{
+			midi.SysExAbortComplete,
+			`SysExType data: 41 01 02
+NoteOn channel: 0 key: 60 velocity: 100
+SysExType data: 43 05
+SysExType data: 7E 06
+`,
+		},
+		{
+			midi.SysExAbortReport,
+			`error: sysex aborted by status 90: F0 41 01 02
+NoteOn channel: 0 key: 60 velocity: 100
+error: sysex aborted by status F0: F0 43 05
+SysExType data: 7E 06
+`,
+		},
+	}
+
+	for _, test := range tests {
+		drv := testdrv.New("listen-sysex-abort")
+		ins, _ := drv.Ins()
+		outs, _ := drv.Outs()
+		outs[0].Open()
+
+		var bf strings.Builder
+
+		stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+			fmt.Fprintf(&bf, "%s\n", msg)
+		}, midi.UseSysEx(), midi.SysExAbort(test.policy), midi.HandleError(func(err error) {
+			if !errors.Is(err, midi.ErrSysExAborted) {
+				t.Errorf("unexpected error: %v", err)
+			}
+			fmt.Fprintf(&bf, "error: %s\n", err)
+		}))
+
+		if err != nil {
+			t.Fatalf("ERROR: %s", err.Error())
+		}
+
+		// dropped 0xF7, terminated by a note on
+		outs[0].Send([]byte{0xF0, 0x41, 0x01, 0x02, 0x90, 0x3C, 0x64})
+		// interrupted by the next sysex
+		outs[0].Send([]byte{0xF0, 0x43, 0x05, 0xF0, 0x7E, 0x06, 0xF7})
+		stop()
+
+		if got := bf.String(); got != test.want {
+			t.Errorf("[policy: %v]\ngot:\n%s\nwant:\n%s", test.policy, got, test.want)
+		}
+	}
+}
+
+func TestListenSysExChunks(t *testing.T) {
+	drv := testdrv.New("listen-sysex-chunks")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	var bf strings.Builder
+
+	stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	}, midi.SysExBufferSize(4), midi.UseSysExChunks(func(chunk midi.SysExChunk) {
+		fmt.Fprintf(&bf, "chunk %v: % X final: %v aborted: %v\n", chunk.Seq, chunk.Data, chunk.Final, chunk.Aborted)
+	}))
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	outs[0].Send([]byte{0xF0, 0x41, 0x01, 0x02, 0x03, 0x04, 0x05, 0xF7})
+	outs[0].Send([]byte{0xF0, 0x43, 0x01, 0x02, 0xF7})
+	outs[0].Send([]byte{0xF0, 0x43, 0x01, 0x02, 0x03, 0x04})
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+	stop()
+
+	want := `chunk 0: F0 41 01 02 final: false aborted: false
+chunk 1: 03 04 05 F7 final: true aborted: false
+chunk 0: F0 43 01 02 final: false aborted: false
+chunk 1: F7 final: true aborted: false
+chunk 0: F0 43 01 02 final: false aborted: false
+chunk 1: 03 04 final: true aborted: true
+NoteOn channel: 1 key: 60 velocity: 100
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestListenSysExMaxSize(t *testing.T) {
+	drv := testdrv.New("listen-sysex-max-size")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	var bf strings.Builder
+
+	stop, err := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	}, midi.UseSysEx(), midi.SysExBufferSize(2), midi.SysExMaxSize(6), midi.HandleError(func(err error) {
+		if !errors.Is(err, midi.ErrSysExTooLarge) {
+			t.Errorf("unexpected error: %v", err)
+		}
+		fmt.Fprintf(&bf, "error: %s\n", err)
+	}))
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	outs[0].Send([]byte{0xF0, 0x41, 0x01, 0x02, 0x03, 0xF7})
+	outs[0].Send([]byte{0xF0, 0x00, 0x20, 0x33, 0x01, 0x02, 0x03, 0xF7})
+	outs[0].Send([]byte{0xF0, 0x43, 0x01, 0x02, 0x03, 0x04, 0x05})
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+	stop()
+
+	want := `SysExType data: 41 01 02 03
+error: sysex message too large: 8 bytes (limit 6) from manufacturer 00 20 33
+error: sysex message too large: 7 bytes (limit 6) from manufacturer 43
+NoteOn channel: 1 key: 60 velocity: 100
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
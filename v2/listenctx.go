@@ -0,0 +1,212 @@
+package midi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// DefaultChannelSize is the default buffer size of the channel of a Listener (see ChannelBuffer).
+const DefaultChannelSize = 1024
+
+// Overflow is the policy of a Listener, when its channel is full.
+type Overflow int
+
+const (
+	// DropOldest removes the oldest message from the channel to make room for the new one.
+	DropOldest Overflow = iota
+
+	// DropNewest drops the new message.
+	DropNewest
+
+	// Block blocks the driver until there is room in the channel or the context is done.
+	Block
+)
+
+// String returns the name of the policy.
+func (o Overflow) String() string {
+	switch o {
+	case DropOldest:
+		return "DropOldest"
+	case DropNewest:
+		return "DropNewest"
+	case Block:
+		return "Block"
+	default:
+		return "Unknown"
+	}
+}
+
+// ChannelBuffer is an option to set the buffer size of the channel of a Listener and the policy, when it is full
+// (see ListenContext). With a size of 0 and a dropping policy, messages are dropped if nobody is waiting on the channel.
+func ChannelBuffer(size int, overflow Overflow) Option {
+	return func(l *listeningOptions) {
+		l.ChannelSize = size
+		l.Overflow = overflow
+	}
+}
+
+// Received is a message that has been received by a Listener.
+type Received struct {
+	Message   Message
+	Timestamp Timestamp
+}
+
+// Listener delivers the received messages of a port over a channel (see ListenContext).
+type Listener struct {
+	c        chan Received
+	overflow Overflow
+	stop     func()
+	done     chan struct{}
+	once     sync.Once
+	mx       sync.Mutex
+	closed   bool
+	dropped  uint64
+	received uint64
+}
+
+// ListenContext listens on the given port and delivers the received messages with their Timestamp over the channel
+// of the returned Listener (see Listener.C). The channel has a buffer of DefaultChannelSize and blocks the driver,
+// when it is full, unless another size or policy is set with ChannelBuffer.
+// The listening ends and the channel is closed, when the context is done, when Close is called or when the driver
+// reports that the listening has stopped (ErrListenStopped or ErrPortClosed), e.g. because the port has been closed.
+// So the messages can be consumed within an errgroup.Group like this:
+//
+//	g.Go(func() error {
+//		l, err := midi.ListenContext(ctx, in)
+//		if err != nil {
+//			return err
+//		}
+//		for r := range l.C() {
+//			// handle r.Message
+//		}
+//		return ctx.Err()
+//	})
+func ListenContext(ctx context.Context, inPort drivers.In, opts ...Option) (*Listener, error) {
+	opts = append([]Option{ChannelBuffer(DefaultChannelSize, Block)}, opts...)
+
+	var opt listeningOptions
+	for _, o := range opts {
+		o(&opt)
+	}
+
+	l := &Listener{c: make(chan Received, opt.ChannelSize), overflow: opt.Overflow, done: make(chan struct{})}
+
+	onErr := opt.OnError
+	opts = append(opts, HandleError(func(err error) {
+		if onErr != nil {
+			onErr(err)
+		}
+
+		if errors.Is(err, ErrListenStopped) || errors.Is(err, ErrPortClosed) {
+			l.Close()
+		}
+	}))
+
+	stop, err := ListenTimestamp(inPort, func(msg Message, ts Timestamp) {
+		if msg == nil || msg.Type() == UnknownMsg {
+			return
+		}
+		l.deliver(ctx, Received{Message: msg, Timestamp: ts})
+	}, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	l.mx.Lock()
+	l.stop = stop
+	l.mx.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-l.done:
+		}
+	}()
+
+	return l, nil
+}
+
+// C returns the channel of the received messages. It is closed, when the listening has ended.
+func (l *Listener) C() <-chan Received {
+	return l.c
+}
+
+// Close stops the listening and closes the channel. It may be called more than once.
+func (l *Listener) Close() error {
+	l.once.Do(func() {
+		// unblock a delivering driver, before the listening is stopped
+		close(l.done)
+
+		l.mx.Lock()
+		stop := l.stop
+		l.mx.Unlock()
+
+		if stop != nil {
+			stop()
+		}
+
+		l.mx.Lock()
+		l.closed = true
+		close(l.c)
+		l.mx.Unlock()
+	})
+	return nil
+}
+
+func (l *Listener) deliver(ctx context.Context, r Received) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.closed {
+		return
+	}
+
+	atomic.AddUint64(&l.received, 1)
+
+	select {
+	case l.c <- r:
+		return
+	default:
+	}
+
+	switch l.overflow {
+	case DropOldest:
+		select {
+		case <-l.c:
+			atomic.AddUint64(&l.dropped, 1)
+		default:
+		}
+
+		select {
+		case l.c <- r:
+		default:
+			atomic.AddUint64(&l.dropped, 1)
+		}
+	case Block:
+		select {
+		case l.c <- r:
+		case <-ctx.Done():
+			atomic.AddUint64(&l.dropped, 1)
+		case <-l.done:
+			atomic.AddUint64(&l.dropped, 1)
+		}
+	default:
+		atomic.AddUint64(&l.dropped, 1)
+	}
+}
+
+// Dropped returns the number of messages that have been dropped.
+func (l *Listener) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Received returns the number of messages that have been received from the port (including the dropped ones).
+func (l *Listener) Received() uint64 {
+	return atomic.LoadUint64(&l.received)
+}
@@ -0,0 +1,208 @@
+package midi_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+func TestListenContext(t *testing.T) {
+	drv := testdrv.New("listen-context")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l, err := midi.ListenContext(ctx, ins[0])
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+
+	r := <-l.C()
+
+	if got, want := r.Message.String(), "NoteOn channel: 1 key: 60 velocity: 100"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	if r.Timestamp.Time.IsZero() {
+		t.Errorf("Timestamp.Time is zero")
+	}
+
+	cancel()
+
+	if _, ok := <-l.C(); ok {
+		t.Errorf("channel not closed after the context is done")
+	}
+}
+
+func TestListenContextOverflow(t *testing.T) {
+	tests := []struct {
+		overflow midi.Overflow
+		expected string
+		dropped  uint64
+	}{
+		{midi.DropNewest, "60 61", 2},
+		{midi.DropOldest, "62 63", 2},
+		{midi.Block, "60 61 62 63", 0},
+	}
+
+	for _, test := range tests {
+		drv := testdrv.New("listen-overflow")
+		ins, _ := drv.Ins()
+		outs, _ := drv.Outs()
+		outs[0].Open()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		l, err := midi.ListenContext(ctx, ins[0], midi.ChannelBuffer(2, test.overflow))
+		if err != nil {
+			t.Fatalf("ERROR: %s", err)
+		}
+
+		var keys []string
+		done := make(chan bool)
+
+		consume := func() {
+			var key uint8
+			for r := range l.C() {
+				if r.Message.GetNoteOn(nil, &key, nil) {
+					keys = append(keys, fmt.Sprint(key))
+				}
+			}
+			close(done)
+		}
+
+		if test.overflow == midi.Block {
+			go consume()
+		}
+
+		for key := uint8(60); key < 64; key++ {
+			outs[0].Send(midi.NoteOn(0, key, 100))
+		}
+
+		if test.overflow != midi.Block {
+			go consume()
+		}
+
+		cancel()
+		<-done
+
+		if got := strings.Join(keys, " "); got != test.expected {
+			t.Errorf("[%s] got %q; want %q", test.overflow, got, test.expected)
+		}
+
+		if got := l.Dropped(); got != test.dropped {
+			t.Errorf("[%s] Dropped() = %v; want %v", test.overflow, got, test.dropped)
+		}
+
+		if got := l.Received(); got != 4 {
+			t.Errorf("[%s] Received() = %v; want 4", test.overflow, got)
+		}
+	}
+}
+
+func TestListenContextClose(t *testing.T) {
+	drv := testdrv.New("listen-context-close")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	// a context that is never done
+	l, err := midi.ListenContext(context.Background(), ins[0])
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	outs[0].Send(midi.NoteOn(1, 60, 100))
+	l.Close()
+	l.Close()
+
+	var n int
+	for range l.C() {
+		n++
+	}
+
+	if n != 1 {
+		t.Errorf("got %v messages; want 1", n)
+	}
+
+	if !ins[0].IsOpen() {
+		t.Errorf("Close must not close the port")
+	}
+}
+
+func TestListenContextPortClosed(t *testing.T) {
+	drv := testdrv.New("listen-context-port-closed")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	var errs []error
+
+	l, err := midi.ListenContext(context.Background(), ins[0], midi.HandleError(func(err error) {
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	ins[0].Close()
+
+	if _, ok := <-l.C(); ok {
+		t.Errorf("channel not closed after the port is closed")
+	}
+
+	if len(errs) != 1 || errs[0] != midi.ErrListenStopped {
+		t.Errorf("errors passed to the handler: %v; want [%v]", errs, midi.ErrListenStopped)
+	}
+}
+
+// callbackIn is an in port that passes the data directly to the listening callback, without a drivers.Reader
+type callbackIn struct {
+	recv func([]byte, int32)
+	conf drivers.ListenConfig
+}
+
+func (c *callbackIn) Open() error             { return nil }
+func (c *callbackIn) Close() error            { return nil }
+func (c *callbackIn) IsOpen() bool            { return true }
+func (c *callbackIn) Number() int             { return 0 }
+func (c *callbackIn) String() string          { return "callback-in" }
+func (c *callbackIn) Underlying() interface{} { return nil }
+
+func (c *callbackIn) Listen(recv func([]byte, int32), conf drivers.ListenConfig) (func(), error) {
+	c.recv, c.conf = recv, conf
+	return func() {}, nil
+}
+
+func TestListenContextSkipNil(t *testing.T) {
+	in := &callbackIn{}
+
+	l, err := midi.ListenContext(context.Background(), in)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	// a single 0xF7 and data bytes without running status are converted to nil messages
+	in.recv([]byte{0xF7}, 0)
+	in.recv([]byte{60, 100}, 0)
+	in.recv(midi.NoteOn(1, 60, 100), 0)
+	in.conf.OnErr(midi.ErrListenStopped)
+
+	var got []string
+	for r := range l.C() {
+		got = append(got, r.Message.String())
+	}
+
+	if s, want := strings.Join(got, ", "), "NoteOn channel: 1 key: 60 velocity: 100"; s != want {
+		t.Errorf("got %q; want %q", s, want)
+	}
+}
@@ -428,6 +428,10 @@ func (m Message) String() string {
 		fmt.Fprintf(&bf, " song: %v", val1)
 	case m.GetSysEx(&sysex):
 		fmt.Fprintf(&bf, " data: % X", sysex)
+	case m.Is(reservedSysCommonMsg5), m.Is(reservedSysCommonMsg6):
+		if len(m) > 1 {
+			fmt.Fprintf(&bf, " data: % X", []byte(m[1:]))
+		}
 	default:
 	}
 
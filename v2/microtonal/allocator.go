@@ -0,0 +1,298 @@
+package microtonal
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/rpn"
+	"gitlab.com/gomidi/midi/v2/sysex"
+)
+
+// Stealing is the policy for new notes when all channels are in use.
+type Stealing int
+
+const (
+	// StealOldest ends the note that was started first.
+	StealOldest Stealing = iota
+
+	// StealQuietest ends the note with the lowest velocity (and of those the oldest).
+	StealQuietest
+
+	// StealNone drops the new note.
+	StealNone
+)
+
+// Voice identifies a note that has been started by an Allocator. The zero value is no voice.
+type Voice uint64
+
+// Option is an option for an Allocator.
+type Option func(*Allocator)
+
+// Channels sets the pool of channels. The default is all channels except the drum channel 9.
+func Channels(channels ...uint8) Option {
+	return func(a *Allocator) {
+		a.channels = nil
+		for _, ch := range channels {
+			a.channels = append(a.channels, &channel{channel: ch & 0x0F})
+		}
+	}
+}
+
+// BendRange sets the pitch bend range of the channels in semitones (see Setup). The default is 2.
+func BendRange(semitones uint8) Option {
+	return func(a *Allocator) {
+		if semitones > 0 {
+			a.bendRange = semitones
+		}
+	}
+}
+
+// Steal sets the policy for new notes when all channels are in use. The default is StealOldest.
+func Steal(policy Stealing) Option {
+	return func(a *Allocator) {
+		a.stealing = policy
+	}
+}
+
+// ReleaseTail sets the time a channel is not used for new notes after its note has been released,
+// so that the release of the note is not bent. Channels in their release tail are only used, if no other channel is free.
+// The default is 0.
+func ReleaseTail(d time.Duration) Option {
+	return func(a *Allocator) {
+		a.releaseTail = d
+	}
+}
+
+// Tuning sets the tuning of the keys for PlayKey, e.g. from sysex.Scale.Tunings.
+// Keys with sysex.NoTuningChange are played in equal temperament.
+func Tuning(tunings [128]sysex.NoteTuning) Option {
+	return func(a *Allocator) {
+		for key, t := range tunings {
+			if t == sysex.NoTuningChange {
+				a.tuning[key] = float64(key) * 100
+			} else {
+				a.tuning[key] = t.Cents()
+			}
+		}
+	}
+}
+
+type channel struct {
+	channel    uint8
+	voice      Voice
+	key        uint8
+	velocity   uint8
+	started    uint64
+	releasedAt time.Time
+	released   uint64
+}
+
+// Allocator distributes microtonal notes over a pool of channels, so that each note can be bent to its pitch.
+// The Allocator returns the messages that have to be sent. An Allocator is threadsafe.
+type Allocator struct {
+	mx          sync.Mutex
+	channels    []*channel
+	bendRange   uint8
+	stealing    Stealing
+	releaseTail time.Duration
+	tuning      [128]float64
+	keys        [128][]Voice
+	counter     uint64
+	lastVoice   Voice
+	now         func() time.Time
+}
+
+// NewAllocator returns a new Allocator.
+func NewAllocator(opts ...Option) *Allocator {
+	a := &Allocator{bendRange: 2, now: time.Now}
+
+	for ch := uint8(0); ch < 16; ch++ {
+		if ch != 9 {
+			a.channels = append(a.channels, &channel{channel: ch})
+		}
+	}
+
+	for key := range a.tuning {
+		a.tuning[key] = float64(key) * 100
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// Setup returns the messages that set the pitch bend range of the channels (via rpn.PitchBendSensitivity)
+// and reset their pitchbend.
+func (a *Allocator) Setup() (msgs []midi.Message) {
+	for _, c := range a.channels {
+		msgs = append(msgs, rpn.PitchBendSensitivity(c.channel, a.bendRange, 0)...)
+		msgs = append(msgs, midi.Pitchbend(c.channel, 0))
+	}
+	return
+}
+
+// Play starts a note with the given pitch in cents above key 0 (key * 100 for equal temperament) and returns its Voice and
+// the messages to send. If the note is dropped (see StealNone), the Voice is 0 and no messages are returned.
+func (a *Allocator) Play(cents float64, velocity uint8) (Voice, []midi.Message) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	return a.play(cents, velocity)
+}
+
+// PlayFrequency starts a note with the given frequency in Hz (see Play).
+func (a *Allocator) PlayFrequency(hz float64, velocity uint8) (Voice, []midi.Message) {
+	return a.Play(6900+1200*math.Log2(hz/440), velocity)
+}
+
+// PlayKey starts a note with the pitch of the given key in the Tuning (see Play). It can be released with ReleaseKey.
+func (a *Allocator) PlayKey(key, velocity uint8) (Voice, []midi.Message) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	key &= 0x7F
+	v, msgs := a.play(a.tuning[key], velocity)
+	if v != 0 {
+		a.keys[key] = append(a.keys[key], v)
+	}
+	return v, msgs
+}
+
+func (a *Allocator) play(cents float64, velocity uint8) (Voice, []midi.Message) {
+	key := math.Round(cents / 100)
+	if key < 0 {
+		key = 0
+	}
+	if key > 127 {
+		key = 127
+	}
+
+	bend := math.Round((cents - key*100) / (float64(a.bendRange) * 100) * 8192)
+	if bend < midi.PitchLowest {
+		bend = midi.PitchLowest
+	}
+	if bend > midi.PitchHighest {
+		bend = midi.PitchHighest
+	}
+
+	c := a.free()
+	if c == nil {
+		return 0, nil
+	}
+
+	var msgs []midi.Message
+
+	if c.voice != 0 {
+		msgs = append(msgs, midi.NoteOff(c.channel, c.key))
+	}
+
+	a.counter++
+	a.lastVoice++
+
+	c.voice = a.lastVoice
+	c.key = uint8(key)
+	c.velocity = velocity
+	c.started = a.counter
+
+	msgs = append(msgs, midi.Pitchbend(c.channel, int16(bend)), midi.NoteOn(c.channel, c.key, velocity))
+	return c.voice, msgs
+}
+
+// free returns the channel for a new note: a free channel (the longest unused first), a channel in its release tail
+// (the longest released first) or the channel of the note that is stolen
+func (a *Allocator) free() (res *channel) {
+	now := a.now()
+
+	var tail, steal *channel
+
+	for _, c := range a.channels {
+		switch {
+		case c.voice != 0:
+			switch {
+			case steal == nil:
+				steal = c
+			case a.stealing == StealQuietest && c.velocity != steal.velocity:
+				if c.velocity < steal.velocity {
+					steal = c
+				}
+			case c.started < steal.started:
+				steal = c
+			}
+		case a.releaseTail > 0 && now.Sub(c.releasedAt) < a.releaseTail:
+			if tail == nil || c.released < tail.released {
+				tail = c
+			}
+		default:
+			if res == nil || c.released < res.released {
+				res = c
+			}
+		}
+	}
+
+	switch {
+	case res != nil:
+		return res
+	case tail != nil:
+		return tail
+	case a.stealing == StealNone:
+		return nil
+	default:
+		return steal
+	}
+}
+
+// Release ends the note of the given voice and returns the messages to send.
+// Nothing is returned, if the note has already been released or stolen.
+func (a *Allocator) Release(v Voice) []midi.Message {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	return a.release(v)
+}
+
+func (a *Allocator) release(v Voice) []midi.Message {
+	if v == 0 {
+		return nil
+	}
+
+	for _, c := range a.channels {
+		if c.voice == v {
+			a.counter++
+			c.voice = 0
+			c.released = a.counter
+			c.releasedAt = a.now()
+			return []midi.Message{midi.NoteOff(c.channel, c.key)}
+		}
+	}
+
+	return nil
+}
+
+// ReleaseKey ends the notes that were started with PlayKey for the given key and returns the messages to send.
+func (a *Allocator) ReleaseKey(key uint8) (msgs []midi.Message) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	key &= 0x7F
+	for _, v := range a.keys[key] {
+		msgs = append(msgs, a.release(v)...)
+	}
+	a.keys[key] = nil
+	return
+}
+
+// Silence ends all notes and returns the messages to send.
+func (a *Allocator) Silence() (msgs []midi.Message) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	for _, c := range a.channels {
+		msgs = append(msgs, a.release(c.voice)...)
+	}
+	a.keys = [128][]Voice{}
+	return
+}
@@ -0,0 +1,136 @@
+package microtonal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/sysex"
+)
+
+func msgsString(msgs []midi.Message) string {
+	var s []string
+	for _, m := range msgs {
+		s = append(s, m.String())
+	}
+	return strings.Join(s, "\n")
+}
+
+func TestAllocator(t *testing.T) {
+	var now time.Time
+	a := NewAllocator(Channels(1, 2), BendRange(2), ReleaseTail(time.Second))
+	a.now = func() time.Time { return now }
+
+	var msgs []midi.Message
+
+	v1, m := a.Play(6950, 100)
+	msgs = append(msgs, m...)
+
+	v2, m := a.PlayFrequency(440, 90)
+	msgs = append(msgs, m...)
+
+	// steals the oldest (v1)
+	v3, m := a.Play(5990, 80)
+	msgs = append(msgs, m...)
+
+	// already stolen
+	msgs = append(msgs, a.Release(v1)...)
+	msgs = append(msgs, a.Release(v2)...)
+
+	// channel 2 is in its release tail, but it is used instead of stealing
+	now = now.Add(500 * time.Millisecond)
+	v4, m := a.Play(6000, 70)
+	msgs = append(msgs, m...)
+
+	msgs = append(msgs, a.Release(v3)...)
+
+	// the release tail of channel 1 is over, but not the one of channel 2
+	now = now.Add(800 * time.Millisecond)
+	msgs = append(msgs, a.Release(v4)...)
+	now = now.Add(300 * time.Millisecond)
+	_, m = a.Play(6000, 60)
+	msgs = append(msgs, m...)
+
+	expected := `PitchBend channel: 1 pitch: -2048 (6144)
+NoteOn channel: 1 key: 70 velocity: 100
+PitchBend channel: 2 pitch: 0 (8192)
+NoteOn channel: 2 key: 69 velocity: 90
+NoteOff channel: 1 key: 70
+PitchBend channel: 1 pitch: -410 (7782)
+NoteOn channel: 1 key: 60 velocity: 80
+NoteOff channel: 2 key: 69
+PitchBend channel: 2 pitch: 0 (8192)
+NoteOn channel: 2 key: 60 velocity: 70
+NoteOff channel: 1 key: 60
+NoteOff channel: 2 key: 60
+PitchBend channel: 1 pitch: 0 (8192)
+NoteOn channel: 1 key: 60 velocity: 60`
+
+	if got := msgsString(msgs); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+
+	if got := a.Release(v3); got != nil {
+		t.Errorf("Release of a stolen voice must return nil, got %v", got)
+	}
+}
+
+func TestStealing(t *testing.T) {
+	tests := []struct {
+		policy   Stealing
+		expected string
+	}{
+		{StealOldest, "NoteOff channel: 0 key: 60 | PitchBend channel: 0 pitch: 0 (8192) | NoteOn channel: 0 key: 62 velocity: 100"},
+		{StealQuietest, "NoteOff channel: 1 key: 61 | PitchBend channel: 1 pitch: 0 (8192) | NoteOn channel: 1 key: 62 velocity: 100"},
+		{StealNone, ""},
+	}
+
+	for _, test := range tests {
+		a := NewAllocator(Channels(0, 1), Steal(test.policy))
+		a.Play(6000, 100)
+		a.Play(6100, 50)
+		v, msgs := a.Play(6200, 100)
+
+		if got := strings.ReplaceAll(msgsString(msgs), "\n", " | "); got != test.expected {
+			t.Errorf("[%v]\ngot:\n%s\nwant:\n%s", test.policy, got, test.expected)
+		}
+
+		if (v == 0) != (test.policy == StealNone) {
+			t.Errorf("[%v] unexpected voice %v", test.policy, v)
+		}
+	}
+}
+
+func TestTuning(t *testing.T) {
+	var tunings [128]sysex.NoteTuning
+	for i := range tunings {
+		tunings[i] = sysex.NoTuningChange
+	}
+	tunings[64] = sysex.NoteTuning{Semitone: 63, Fraction: 14000}
+
+	a := NewAllocator(Channels(0, 1, 2), Tuning(tunings), BendRange(12))
+
+	var msgs []midi.Message
+	_, m := a.PlayKey(60, 100)
+	msgs = append(msgs, m...)
+	_, m = a.PlayKey(64, 100)
+	msgs = append(msgs, m...)
+	msgs = append(msgs, a.ReleaseKey(64)...)
+	msgs = append(msgs, a.Silence()...)
+
+	expected := `PitchBend channel: 0 pitch: 0 (8192)
+NoteOn channel: 0 key: 60 velocity: 100
+PitchBend channel: 1 pitch: -99 (8093)
+NoteOn channel: 1 key: 64 velocity: 100
+NoteOff channel: 1 key: 64
+NoteOff channel: 0 key: 60`
+
+	if got := msgsString(msgs); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+
+	if got, want := len(a.Setup()), 3*7; got != want {
+		t.Errorf("len(Setup()) = %v; want %v", got, want)
+	}
+}
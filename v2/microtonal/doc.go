@@ -0,0 +1,21 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package microtonal plays microtonal notes on synths that don't support the MIDI Tuning Standard.
+
+An Allocator rotates the notes over a pool of channels and bends each channel to the pitch of its note,
+so that every sounding note has its own pitchbend:
+
+	a := microtonal.NewAllocator(microtonal.Channels(0, 1, 2, 3), microtonal.BendRange(2))
+	send(a.Setup()...)
+
+	v, msgs := a.Play(6950, 100) // a quarter tone above key 69
+	send(msgs...)
+	...
+	send(a.Release(v)...)
+
+Tunings of Scala files can be played with PlayKey (see Tuning and sysex.Scale.Tunings).
+*/
+package microtonal
@@ -0,0 +1,111 @@
+package mpe
+
+import (
+	"sync"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Expression is the per note expression of MPE.
+type Expression struct {
+	// Bend is the pitchbend of the member channel (-8192 - 8191).
+	Bend int16
+
+	// Pressure is the channel pressure of the member channel.
+	Pressure uint8
+
+	// Timbre is the value of the controller 74 (midi.SoundBrightness) of the member channel.
+	Timbre uint8
+}
+
+// DefaultExpression is the expression of a member channel, as long as nothing else has been received.
+var DefaultExpression = Expression{Timbre: 64}
+
+// Messages returns the messages that set the expression on the given channel.
+func (e Expression) Messages(channel uint8) []midi.Message {
+	return []midi.Message{
+		midi.Pitchbend(channel, e.Bend),
+		midi.ControlChange(channel, midi.SoundBrightness, e.Timbre),
+		midi.AfterTouch(channel, e.Pressure),
+	}
+}
+
+// Note is a note that has been started by an Allocator.
+type Note struct {
+	Channel uint8
+	Key     uint8
+}
+
+// PitchBend returns the message that bends the note.
+func (n Note) PitchBend(value int16) midi.Message {
+	return midi.Pitchbend(n.Channel, value)
+}
+
+// Pressure returns the message that sets the pressure of the note.
+func (n Note) Pressure(pressure uint8) midi.Message {
+	return midi.AfterTouch(n.Channel, pressure)
+}
+
+// Timbre returns the message that sets the timbre (controller 74) of the note.
+func (n Note) Timbre(value uint8) midi.Message {
+	return midi.ControlChange(n.Channel, midi.SoundBrightness, value)
+}
+
+// Allocator distributes the notes over the member channels of a zone.
+// A new note gets the member channel with the fewest sounding notes and of those the least recently used one.
+// An Allocator is threadsafe.
+type Allocator struct {
+	mx       sync.Mutex
+	zone     Zone
+	notes    [16]int
+	lastUsed [16]uint64
+	counter  uint64
+}
+
+// NewAllocator returns a new Allocator for the given zone.
+func NewAllocator(z Zone) *Allocator {
+	return &Allocator{zone: z}
+}
+
+// NoteOn returns the note and the messages that start it: the initial expression of the note, followed by the NoteOn message.
+// If the zone has no member channels, the note is played on the manager channel without expression.
+func (a *Allocator) NoteOn(key, velocity uint8, exp Expression) (Note, []midi.Message) {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	chs := a.zone.MemberChannels()
+	if len(chs) == 0 {
+		n := Note{Channel: a.zone.Manager(), Key: key}
+		return n, []midi.Message{midi.NoteOn(n.Channel, key, velocity)}
+	}
+
+	ch := chs[0]
+	for _, c := range chs[1:] {
+		if a.notes[c] < a.notes[ch] || (a.notes[c] == a.notes[ch] && a.lastUsed[c] < a.lastUsed[ch]) {
+			ch = c
+		}
+	}
+
+	a.counter++
+	a.notes[ch]++
+	a.lastUsed[ch] = a.counter
+
+	n := Note{Channel: ch, Key: key}
+	return n, append(exp.Messages(ch), midi.NoteOn(ch, key, velocity))
+}
+
+// NoteOff returns the message that ends the note.
+func (a *Allocator) NoteOff(n Note) midi.Message {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	ch := n.Channel & 0x0F
+	if a.notes[ch] > 0 {
+		a.notes[ch]--
+	}
+
+	a.counter++
+	a.lastUsed[ch] = a.counter
+
+	return midi.NoteOff(ch, n.Key)
+}
@@ -0,0 +1,279 @@
+package mpe
+
+import (
+	"fmt"
+	"sync"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Kind is the kind of a per note event.
+type Kind uint8
+
+const (
+	// NoteStart is the start of a note.
+	NoteStart Kind = iota
+
+	// NoteEnd is the end of a note.
+	NoteEnd
+
+	// PitchBend is the change of the pitchbend of the member channel or the manager channel.
+	PitchBend
+
+	// Pressure is the change of the channel pressure of the member channel.
+	Pressure
+
+	// Timbre is the change of the controller 74 of the member channel.
+	Timbre
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case NoteStart:
+		return "NoteStart"
+	case NoteEnd:
+		return "NoteEnd"
+	case PitchBend:
+		return "PitchBend"
+	case Pressure:
+		return "Pressure"
+	case Timbre:
+		return "Timbre"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is a per note event of a zone.
+type Event struct {
+	Kind Kind
+
+	// Upper is true for a note of the upper zone.
+	Upper bool
+
+	Channel uint8
+	Key     uint8
+
+	// Velocity is the velocity of the NoteOn message, or of the NoteOff message for NoteEnd.
+	Velocity uint8
+
+	// Expression is the current expression of the note.
+	Expression
+
+	// Pitch is the current pitch of the note in semitones (the key, bent by the pitchbend of the member channel
+	// and the manager channel).
+	Pitch float64
+}
+
+// String represents the event as a string.
+func (e Event) String() string {
+	return fmt.Sprintf("%s channel: %v key: %v velocity: %v pitch: %0.2f pressure: %v timbre: %v",
+		e.Kind, e.Channel, e.Key, e.Velocity, e.Pitch, e.Pressure, e.Timbre)
+}
+
+type sounding struct {
+	key, velocity uint8
+}
+
+// Decoder groups the messages of the member channels of the zones into per note events.
+// It follows the MPE Configuration Messages and the pitch bend sensitivity RPNs of the managers and members.
+// A Decoder is threadsafe.
+type Decoder struct {
+	mx          sync.Mutex
+	zones       [2]Zone
+	params      *midi.ParameterDecoder
+	expr        [16]Expression
+	managerBend [2]int16
+	notes       [16][]sounding
+}
+
+// NewDecoder returns a new Decoder for the given zones. Without zones, no zone is enabled until an
+// MPE Configuration Message is received.
+func NewDecoder(zones ...Zone) *Decoder {
+	d := &Decoder{params: midi.NewParameterDecoder()}
+	d.zones[1].Upper = true
+
+	for _, z := range zones {
+		d.configure(z)
+	}
+
+	for ch := range d.expr {
+		d.expr[ch] = DefaultExpression
+	}
+
+	return d
+}
+
+// Zones returns the current configuration of the zones.
+func (d *Decoder) Zones() (lower, upper Zone) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	return d.zones[0], d.zones[1]
+}
+
+// configure sets the zone and shrinks the other zone, if they overlap.
+func (d *Decoder) configure(z Zone) {
+	z.Members = clipMembers(z.Members)
+	idx, other := zoneIndex(z.Upper), zoneIndex(!z.Upper)
+
+	d.zones[idx] = z
+	d.managerBend[idx] = 0
+
+	if o := &d.zones[other]; o.Members+z.Members > 14 {
+		if z.Members >= 15 {
+			o.Members = 0
+		} else {
+			o.Members = 14 - z.Members
+		}
+	}
+}
+
+func zoneIndex(upper bool) int {
+	if upper {
+		return 1
+	}
+	return 0
+}
+
+// zoneOf returns the index of the zone of the channel and whether it is the manager channel.
+func (d *Decoder) zoneOf(ch uint8) (idx int, manager bool, ok bool) {
+	for i, z := range d.zones {
+		if z.Members == 0 {
+			continue
+		}
+		if z.Manager() == ch {
+			return i, true, true
+		}
+		if z.IsMember(ch) {
+			return i, false, true
+		}
+	}
+	return 0, false, false
+}
+
+// Decode returns the per note events of the given message. Messages of channels that are not part of
+// a zone and messages that don't affect notes return no events.
+func (d *Decoder) Decode(msg midi.Message) []Event {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	if ev, ok := d.params.Decode(msg); ok {
+		d.parameter(ev)
+		return nil
+	}
+
+	var ch, key, vel, ctl, val uint8
+	var rel int16
+
+	if !msg.GetChannel(&ch) {
+		return nil
+	}
+
+	idx, manager, ok := d.zoneOf(ch)
+	if !ok {
+		return nil
+	}
+
+	if manager {
+		if msg.GetPitchBend(nil, &rel, nil) {
+			d.managerBend[idx] = rel
+			return d.events(PitchBend, idx, d.zones[idx].MemberChannels()...)
+		}
+		return nil
+	}
+
+	switch {
+	case msg.GetNoteStart(nil, &key, &vel):
+		d.notes[ch] = append(d.notes[ch], sounding{key: key, velocity: vel})
+		return []Event{d.event(NoteStart, idx, ch, key, vel)}
+
+	case msg.GetNoteEnd(nil, &key):
+		msg.GetNoteOff(nil, nil, &vel)
+		for i, n := range d.notes[ch] {
+			if n.key == key {
+				d.notes[ch] = append(d.notes[ch][:i], d.notes[ch][i+1:]...)
+				return []Event{d.event(NoteEnd, idx, ch, key, vel)}
+			}
+		}
+
+	case msg.GetPitchBend(nil, &rel, nil):
+		d.expr[ch].Bend = rel
+		return d.events(PitchBend, idx, ch)
+
+	case msg.GetAfterTouch(nil, &val):
+		d.expr[ch].Pressure = val
+		return d.events(Pressure, idx, ch)
+
+	case msg.GetControlChange(nil, &ctl, &val) && ctl == midi.SoundBrightness:
+		d.expr[ch].Timbre = val
+		return d.events(Timbre, idx, ch)
+	}
+
+	return nil
+}
+
+// parameter handles the MPE Configuration Message (RPN 6) and the pitch bend sensitivity (RPN 0)
+func (d *Decoder) parameter(ev midi.ParameterEvent) {
+	if ev.NRPN || ev.Change == midi.ParameterReset {
+		return
+	}
+
+	msb := uint8(ev.Value >> 7)
+
+	switch ev.Parameter {
+	case 6:
+		if ev.Channel != 0 && ev.Channel != 15 {
+			return
+		}
+
+		z := LowerZone(msb)
+		z.Upper = ev.Channel == 15
+
+		// reset the notes and expressions of the zone
+		for _, ch := range z.MemberChannels() {
+			d.expr[ch] = DefaultExpression
+			d.notes[ch] = nil
+		}
+
+		d.configure(z)
+	case 0:
+		idx, manager, ok := d.zoneOf(ev.Channel)
+		if !ok {
+			return
+		}
+
+		if manager {
+			d.zones[idx].ManagerBendRange = msb
+		} else {
+			d.zones[idx].BendRange = msb
+		}
+	}
+}
+
+// events returns the events of the given kind for the sounding notes of the given channels
+func (d *Decoder) events(kind Kind, idx int, channels ...uint8) (evts []Event) {
+	for _, ch := range channels {
+		for _, n := range d.notes[ch] {
+			evts = append(evts, d.event(kind, idx, ch, n.key, n.velocity))
+		}
+	}
+	return
+}
+
+func (d *Decoder) event(kind Kind, idx int, ch, key, velocity uint8) Event {
+	z := d.zones[idx]
+
+	return Event{
+		Kind:       kind,
+		Upper:      z.Upper,
+		Channel:    ch,
+		Key:        key,
+		Velocity:   velocity,
+		Expression: d.expr[ch],
+		Pitch: float64(key) +
+			float64(d.expr[ch].Bend)/8192*float64(z.BendRange) +
+			float64(d.managerBend[idx])/8192*float64(z.ManagerBendRange),
+	}
+}
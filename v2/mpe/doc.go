@@ -0,0 +1,35 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package mpe supports MIDI Polyphonic Expression (MPE).
+
+An MPE Zone consists of a manager channel (channel 0 for the lower zone, channel 15 for the upper zone) and member channels.
+Every note is played on its own member channel, so that its pitchbend, channel pressure and timbre (CC74) are per note.
+
+On the sending side, a Zone is configured with the MPE Configuration Message (see Zone.Configure) and an Allocator
+distributes the notes over the member channels:
+
+	zone := mpe.LowerZone(15)
+	send(zone.Configure()...)
+
+	a := mpe.NewAllocator(zone)
+	note, msgs := a.NoteOn(60, 100, mpe.DefaultExpression)
+	send(msgs...)
+	send(note.PitchBend(1000))
+	send(a.NoteOff(note))
+
+On the receiving side, a Decoder groups the messages of the member channels into per note events:
+
+	d := mpe.NewDecoder(mpe.LowerZone(15))
+
+	midi.ListenTo(in, func(msg midi.Message, timestampms int32) {
+		for _, ev := range d.Decode(msg) {
+			fmt.Printf("key %v: pitch %0.2f pressure %v timbre %v\n", ev.Key, ev.Pitch, ev.Pressure, ev.Timbre)
+		}
+	})
+
+The Decoder also follows the MPE Configuration Messages and pitch bend sensitivity RPNs that it receives.
+*/
+package mpe
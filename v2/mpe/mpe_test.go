@@ -0,0 +1,158 @@
+package mpe
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestZone(t *testing.T) {
+	tests := []struct {
+		zone      Zone
+		manager   uint8
+		members   string
+		configure string
+	}{
+		{
+			LowerZone(3),
+			0,
+			"[1 2 3]",
+			"ControlChange channel: 0 controller: 101 value: 0 | ControlChange channel: 0 controller: 100 value: 6 | " +
+				"ControlChange channel: 0 controller: 6 value: 3 | ControlChange channel: 0 controller: 38 value: 0 | " +
+				"ControlChange channel: 0 controller: 101 value: 127 | ControlChange channel: 0 controller: 100 value: 127",
+		},
+		{
+			Zone{Upper: true, Members: 2, BendRange: 24, ManagerBendRange: 2},
+			15,
+			"[14 13]",
+			"ControlChange channel: 15 controller: 101 value: 0 | ControlChange channel: 15 controller: 100 value: 6 | " +
+				"ControlChange channel: 15 controller: 6 value: 2 | ControlChange channel: 15 controller: 38 value: 0 | " +
+				"ControlChange channel: 15 controller: 101 value: 127 | ControlChange channel: 15 controller: 100 value: 127 | " +
+				"ControlChange channel: 14 controller: 101 value: 0 | ControlChange channel: 14 controller: 100 value: 0 | " +
+				"ControlChange channel: 14 controller: 6 value: 24 | ControlChange channel: 14 controller: 38 value: 0 | " +
+				"ControlChange channel: 14 controller: 101 value: 127 | ControlChange channel: 14 controller: 100 value: 127",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.zone.Manager(); got != test.manager {
+			t.Errorf("%+v.Manager() = %v; want %v", test.zone, got, test.manager)
+		}
+
+		if got := fmt.Sprint(test.zone.MemberChannels()); got != test.members {
+			t.Errorf("%+v.MemberChannels() = %v; want %v", test.zone, got, test.members)
+		}
+
+		for _, ch := range test.zone.MemberChannels() {
+			if !test.zone.IsMember(ch) {
+				t.Errorf("%+v.IsMember(%v) = false", test.zone, ch)
+			}
+		}
+
+		if test.zone.IsMember(test.manager) {
+			t.Errorf("%+v.IsMember(%v) = true", test.zone, test.manager)
+		}
+
+		if got := msgsString(test.zone.Configure()); got != test.configure {
+			t.Errorf("%+v.Configure()\ngot:\n%s\nwant:\n%s", test.zone, got, test.configure)
+		}
+	}
+}
+
+func msgsString(msgs []midi.Message) string {
+	var s []string
+	for _, m := range msgs {
+		s = append(s, m.String())
+	}
+	return strings.Join(s, " | ")
+}
+
+func TestAllocator(t *testing.T) {
+	a := NewAllocator(LowerZone(2))
+
+	n1, msgs := a.NoteOn(60, 100, Expression{Bend: 100, Pressure: 10, Timbre: 64})
+
+	want := "PitchBend channel: 1 pitch: 100 (8292) | ControlChange channel: 1 controller: 74 value: 64 | " +
+		"AfterTouch channel: 1 pressure: 10 | NoteOn channel: 1 key: 60 velocity: 100"
+
+	if got := msgsString(msgs); got != want {
+		t.Errorf("NoteOn\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	n2, _ := a.NoteOn(64, 100, DefaultExpression)
+	a.NoteOff(n1)
+
+	// channel 1 is the least recently used
+	n3, _ := a.NoteOn(67, 100, DefaultExpression)
+
+	// both channels have a note, channel 2 is the least recently used
+	n4, _ := a.NoteOn(72, 100, DefaultExpression)
+
+	if got, want := fmt.Sprint(n1, n2, n3, n4), "{1 60} {2 64} {1 67} {2 72}"; got != want {
+		t.Errorf("notes = %s; want %s", got, want)
+	}
+
+	if got, want := a.NoteOff(n4).String(), "NoteOff channel: 2 key: 72"; got != want {
+		t.Errorf("NoteOff = %s; want %s", got, want)
+	}
+
+	if got, want := n3.Timbre(10).String(), "ControlChange channel: 1 controller: 74 value: 10"; got != want {
+		t.Errorf("Timbre = %s; want %s", got, want)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	d := NewDecoder()
+
+	var msgs []midi.Message
+	msgs = append(msgs, Zone{Members: 3, BendRange: 12, ManagerBendRange: 2}.Configure()...)
+
+	a := NewAllocator(LowerZone(3))
+	n1, m := a.NoteOn(60, 100, DefaultExpression)
+	msgs = append(msgs, m...)
+	n2, m := a.NoteOn(64, 90, Expression{Bend: -4096, Timbre: 64})
+	msgs = append(msgs, m...)
+
+	msgs = append(msgs,
+		n1.PitchBend(4096),
+		n2.Pressure(50),
+		n2.Timbre(70),
+		midi.Pitchbend(0, 8191),
+		a.NoteOff(n1),
+		midi.NoteOn(5, 60, 100),
+	)
+
+	var got []string
+	for _, msg := range msgs {
+		for _, ev := range d.Decode(msg) {
+			got = append(got, ev.String())
+		}
+	}
+
+	want := []string{
+		"NoteStart channel: 1 key: 60 velocity: 100 pitch: 60.00 pressure: 0 timbre: 64",
+		"NoteStart channel: 2 key: 64 velocity: 90 pitch: 58.00 pressure: 0 timbre: 64",
+		"PitchBend channel: 1 key: 60 velocity: 100 pitch: 66.00 pressure: 0 timbre: 64",
+		"Pressure channel: 2 key: 64 velocity: 90 pitch: 58.00 pressure: 50 timbre: 64",
+		"Timbre channel: 2 key: 64 velocity: 90 pitch: 58.00 pressure: 50 timbre: 70",
+		"PitchBend channel: 1 key: 60 velocity: 100 pitch: 68.00 pressure: 0 timbre: 64",
+		"PitchBend channel: 2 key: 64 velocity: 90 pitch: 60.00 pressure: 50 timbre: 70",
+		"NoteEnd channel: 1 key: 60 velocity: 0 pitch: 68.00 pressure: 0 timbre: 64",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// the upper zone shrinks the lower one
+	for _, msg := range UpperZone(13).Configure() {
+		d.Decode(msg)
+	}
+
+	lower, upper := d.Zones()
+	if lower.Members != 1 || upper.Members != 13 || lower.BendRange != 12 || upper.BendRange != DefaultMemberBendRange {
+		t.Errorf("Zones() = %+v, %+v", lower, upper)
+	}
+}
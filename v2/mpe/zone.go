@@ -0,0 +1,99 @@
+package mpe
+
+import (
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/rpn"
+)
+
+const (
+	// DefaultMemberBendRange is the pitch bend range of the member channels in semitones, after a zone has been configured.
+	DefaultMemberBendRange = 48
+
+	// DefaultManagerBendRange is the pitch bend range of the manager channel in semitones, after a zone has been configured.
+	DefaultManagerBendRange = 2
+)
+
+// Zone is a lower or upper MPE zone.
+type Zone struct {
+	// Upper is true for the upper zone (manager channel 15) and false for the lower zone (manager channel 0).
+	Upper bool
+
+	// Members is the number of member channels (0-15). A zone without member channels is disabled.
+	Members uint8
+
+	// BendRange is the pitch bend range of the member channels in semitones.
+	BendRange uint8
+
+	// ManagerBendRange is the pitch bend range of the manager channel in semitones.
+	ManagerBendRange uint8
+}
+
+// LowerZone returns the lower zone with the given number of member channels (1 - members) and the default bend ranges.
+func LowerZone(members uint8) Zone {
+	return Zone{Members: clipMembers(members), BendRange: DefaultMemberBendRange, ManagerBendRange: DefaultManagerBendRange}
+}
+
+// UpperZone returns the upper zone with the given number of member channels (14 - 15-members) and the default bend ranges.
+func UpperZone(members uint8) Zone {
+	z := LowerZone(members)
+	z.Upper = true
+	return z
+}
+
+func clipMembers(members uint8) uint8 {
+	if members > 15 {
+		return 15
+	}
+	return members
+}
+
+// Manager returns the manager channel of the zone.
+func (z Zone) Manager() uint8 {
+	if z.Upper {
+		return 15
+	}
+	return 0
+}
+
+// MemberChannels returns the member channels of the zone, starting with the one next to the manager channel.
+func (z Zone) MemberChannels() []uint8 {
+	chs := make([]uint8, clipMembers(z.Members))
+	for i := range chs {
+		if z.Upper {
+			chs[i] = uint8(14 - i)
+		} else {
+			chs[i] = uint8(1 + i)
+		}
+	}
+	return chs
+}
+
+// IsMember returns true, if the given channel is a member channel of the zone.
+func (z Zone) IsMember(channel uint8) bool {
+	m := clipMembers(z.Members)
+	if z.Upper {
+		return channel < 15 && channel >= 15-m
+	}
+	return channel > 0 && channel <= m
+}
+
+// Configure returns the MPE Configuration Message (RPN 6) for the zone, followed by the pitch bend sensitivity RPNs
+// for the bend ranges that differ from the defaults.
+func (z Zone) Configure() []midi.Message {
+	m := z.Manager()
+	msgs := rpn.RPN(m, 0, 6, clipMembers(z.Members), 0)
+
+	if z.Members == 0 {
+		return msgs
+	}
+
+	if z.ManagerBendRange != DefaultManagerBendRange {
+		msgs = append(msgs, rpn.PitchBendSensitivity(m, z.ManagerBendRange, 0)...)
+	}
+
+	if z.BendRange != DefaultMemberBendRange {
+		msgs = append(msgs, rpn.PitchBendSensitivity(z.MemberChannels()[0], z.BendRange, 0)...)
+	}
+
+	return msgs
+}
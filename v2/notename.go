@@ -0,0 +1,214 @@
+package midi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidNoteName is returned, if a note name can't be parsed
+var ErrInvalidNoteName = errors.New("invalid note name")
+
+// noteOptions are the options for parsing and spelling note names
+type noteOptions struct {
+	middleC     int
+	accidentals int
+}
+
+// NoteOption is an option for parsing and spelling note names
+type NoteOption func(*noteOptions)
+
+// MiddleC sets the octave of the middle C (key 60) for parsing and spelling note names.
+// The default is 5 (like Note.String and the C, Db... functions), other common conventions are 4 and 3.
+func MiddleC(octave int8) NoteOption {
+	return func(o *noteOptions) {
+		o.middleC = int(octave)
+	}
+}
+
+// KeySignature sets the number of sharps (> 0) or flats (< 0) of the key for spelling note names (see smf.Key.Spell).
+// The notes of the key are spelled as in the key (e.g. E# in F# major) and the other notes with sharps in keys with sharps
+// and with flats otherwise.
+func KeySignature(sharpsOrFlats int8) NoteOption {
+	return func(o *noteOptions) {
+		o.accidentals = int(sharpsOrFlats)
+		if o.accidentals > 7 {
+			o.accidentals = 7
+		}
+		if o.accidentals < -7 {
+			o.accidentals = -7
+		}
+	}
+}
+
+func newNoteOptions(opts []NoteOption) *noteOptions {
+	o := &noteOptions{middleC: 5}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+var (
+	noteLetters  = "CDEFGAB"
+	naturalNotes = [7]int{0, 2, 4, 5, 7, 9, 11}
+
+	// indices of the letters in the order of the sharps and flats of key signatures
+	sharpsOrder = [7]int{3, 0, 4, 1, 5, 2, 6}
+	flatsOrder  = [7]int{6, 2, 5, 1, 4, 0, 3}
+)
+
+// ParseNote parses a note name in scientific pitch notation, like "C5", "F#3", "Bb-1" or "Ebb4"
+// (the octave depends on the MiddleC option), or in Helmholtz pitch notation, like "c'", "C," or "fis" (the middle C is c').
+// Supported accidentals are #, b, x (double sharp), ## and bb, also as unicode characters (♯, ♭, 𝄪, 𝄫).
+// The letters of scientific pitch notation are case insensitive. A name without octave is Helmholtz pitch notation.
+func ParseNote(name string, opts ...NoteOption) (Note, error) {
+	o := newNoteOptions(opts)
+
+	s := strings.TrimSpace(name)
+	if s == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNoteName, name)
+	}
+
+	letter := strings.IndexByte(noteLetters, strings.ToUpper(s[:1])[0])
+	if letter < 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidNoteName, name)
+	}
+
+	lower := s[0] >= 'a'
+	s = s[1:]
+
+	acc, s := parseAccidentals(s)
+
+	var key int
+
+	switch {
+	case s == "" || strings.Trim(s, "',") == "":
+		// Helmholtz: C = 36, c = 48, c' = 60
+		key = 36
+		if lower {
+			key = 48
+		}
+		key += 12*strings.Count(s, "'") - 12*strings.Count(s, ",")
+
+		if (lower && strings.Contains(s, ",")) || (!lower && strings.Contains(s, "'")) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidNoteName, name)
+		}
+	default:
+		oct, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidNoteName, name)
+		}
+		key = 60 + (oct-o.middleC)*12
+	}
+
+	key += naturalNotes[letter] + acc
+
+	if key < 0 || key > 127 {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidNoteName, name)
+	}
+
+	return Note(key), nil
+}
+
+func parseAccidentals(s string) (acc int, rest string) {
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		switch r {
+		case '#', '♯':
+			acc++
+		case 'b', '♭':
+			acc--
+		case 'x', '𝄪':
+			acc += 2
+		case '𝄫':
+			acc -= 2
+		default:
+			// german names like fis, es and as (the german b for Bb and h for B are not supported)
+			switch {
+			case strings.HasPrefix(s, "is"):
+				acc++
+				size = 2
+			case strings.HasPrefix(s, "es"):
+				acc--
+				size = 2
+			case strings.HasPrefix(s, "s"):
+				acc--
+			default:
+				return acc, s
+			}
+		}
+		s = s[size:]
+	}
+	return acc, s
+}
+
+// Spell returns the name of the note with the octave in scientific pitch notation.
+// Without options it is the same as String. The octave convention can be set with the MiddleC option and the
+// choice between sharps and flats (e.g. F# or Gb) is based on the KeySignature option.
+func (n Note) Spell(opts ...NoteOption) string {
+	o := newNoteOptions(opts)
+
+	var sig [7]int
+	for i := 0; i < o.accidentals; i++ {
+		sig[sharpsOrder[i]] = 1
+	}
+	for i := 0; i < -o.accidentals; i++ {
+		sig[flatsOrder[i]] = -1
+	}
+
+	pc := int(n) % 12
+	letter, acc := -1, 0
+
+	// notes of the key
+	for l := range naturalNotes {
+		if (naturalNotes[l]+sig[l]+12)%12 == pc {
+			letter, acc = l, sig[l]
+			break
+		}
+	}
+
+	// natural notes
+	if letter < 0 {
+		for l := range naturalNotes {
+			if naturalNotes[l] == pc {
+				letter = l
+				break
+			}
+		}
+	}
+
+	// other notes
+	if letter < 0 {
+		acc = -1
+		if o.accidentals > 0 {
+			acc = 1
+		}
+
+		for l := range naturalNotes {
+			if naturalNotes[l]+acc == pc {
+				letter = l
+				break
+			}
+		}
+	}
+
+	var bd strings.Builder
+	bd.WriteByte(noteLetters[letter])
+
+	switch acc {
+	case 1:
+		bd.WriteString("#")
+	case -1:
+		bd.WriteString("b")
+	}
+
+	// the octave of the natural note, e.g. B#4 and Cb5 (with middle C = C5)
+	base := int(n) - acc
+	oct := (base+12)/12 - 1
+	fmt.Fprintf(&bd, "%v", oct-5+o.middleC)
+
+	return bd.String()
+}
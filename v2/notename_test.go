@@ -0,0 +1,98 @@
+package midi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseNote(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []NoteOption
+		expected Note
+	}{
+		{"C5", nil, 60},
+		{"c5", nil, 60},
+		{"C4", []NoteOption{MiddleC(4)}, 60},
+		{"C3", []NoteOption{MiddleC(3)}, 60},
+		{"F#3", []NoteOption{MiddleC(4)}, 54},
+		{"Bb-1", []NoteOption{MiddleC(4)}, 10},
+		{"Db5", nil, 61},
+		{"Ebb5", nil, 62},
+		{"Cx5", nil, 62},
+		{"C##5", nil, 62},
+		{"B#4", nil, 60},
+		{"Cb5", nil, 59},
+		{"F♯5", nil, 66},
+		{"B♭4", nil, 58},
+		{"G9", []NoteOption{MiddleC(4)}, 127},
+		{"c'", nil, 60},
+		{"c''", nil, 72},
+		{"c", nil, 48},
+		{"C", nil, 36},
+		{"C,", nil, 24},
+		{"fis'", nil, 66},
+		{"es", nil, 51},
+		{"as'", nil, 68},
+		{"bb", nil, 58},
+	}
+
+	for _, test := range tests {
+		got, err := ParseNote(test.name, test.opts...)
+		if err != nil {
+			t.Errorf("ParseNote(%q) returned error: %s", test.name, err)
+			continue
+		}
+
+		if got != test.expected {
+			t.Errorf("ParseNote(%q) = %v; want %v", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestParseNoteErrors(t *testing.T) {
+	tests := []string{"", "H4", "c,", "C'", "G#9x", "Ab9", "Cb-1", "X"}
+
+	for _, name := range tests {
+		if n, err := ParseNote(name, MiddleC(4)); !errors.Is(err, ErrInvalidNoteName) {
+			t.Errorf("ParseNote(%q) = %v, %v; expected ErrInvalidNoteName", name, n, err)
+		}
+	}
+}
+
+func TestSpell(t *testing.T) {
+	tests := []struct {
+		note     Note
+		opts     []NoteOption
+		expected string
+	}{
+		{60, nil, "C5"},
+		{61, nil, "Db5"},
+		{60, []NoteOption{MiddleC(4)}, "C4"},
+		{66, []NoteOption{KeySignature(2)}, "F#5"},
+		{66, []NoteOption{KeySignature(-5)}, "Gb5"},
+		{65, []NoteOption{KeySignature(6)}, "E#5"},
+		{59, []NoteOption{KeySignature(-6)}, "Cb5"},
+		{60, []NoteOption{KeySignature(7), MiddleC(4)}, "B#3"},
+		{65, []NoteOption{KeySignature(2)}, "F5"},
+		{63, []NoteOption{KeySignature(1)}, "D#5"},
+		{63, []NoteOption{KeySignature(-1)}, "Eb5"},
+		{0, []NoteOption{MiddleC(4)}, "C-1"},
+	}
+
+	for _, test := range tests {
+		if got := test.note.Spell(test.opts...); got != test.expected {
+			t.Errorf("Note(%v).Spell() = %q; want %q", uint8(test.note), got, test.expected)
+		}
+	}
+
+	for n := Note(0); n < 128; n++ {
+		if got, want := n.Spell(), n.String(); got != want {
+			t.Errorf("Note(%v).Spell() = %q; want %q", uint8(n), got, want)
+		}
+
+		if got, err := ParseNote(n.Spell(KeySignature(7), MiddleC(4)), MiddleC(4)); err != nil || got != n {
+			t.Errorf("ParseNote(Note(%v).Spell()) = %v, %v", uint8(n), got, err)
+		}
+	}
+}
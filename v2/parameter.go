@@ -0,0 +1,218 @@
+package midi
+
+import (
+	"fmt"
+)
+
+// ParameterChange is the kind of change of a registered (RPN) or non registered parameter (NRPN).
+type ParameterChange uint8
+
+const (
+	// ParameterSet is a change via DataEntryMSB or DataEntryLSB
+	ParameterSet ParameterChange = iota
+
+	// ParameterIncrement is a change via DataButtonIncrement
+	ParameterIncrement
+
+	// ParameterDecrement is a change via DataButtonDecrement
+	ParameterDecrement
+
+	// ParameterReset is the selection of the null parameter (MSB and LSB 127), which deselects the current parameter
+	ParameterReset
+)
+
+// String returns the name of the change.
+func (c ParameterChange) String() string {
+	switch c {
+	case ParameterSet:
+		return "set"
+	case ParameterIncrement:
+		return "increment"
+	case ParameterDecrement:
+		return "decrement"
+	case ParameterReset:
+		return "reset"
+	default:
+		return "unknown"
+	}
+}
+
+// ParameterEvent is the change of a registered (RPN) or non registered parameter (NRPN).
+type ParameterEvent struct {
+	Channel uint8
+
+	// NRPN is true for a non registered parameter and false for a registered one.
+	NRPN bool
+
+	// Change is the kind of the change.
+	Change ParameterChange
+
+	// Parameter is the parameter number (MSB<<7 | LSB), e.g. 0 for the RPN pitch bend sensitivity.
+	// It is not set for ParameterReset.
+	Parameter uint16
+
+	// Value is the 14-bit value of the parameter after the change. It is not set for ParameterReset.
+	Value uint16
+}
+
+// ParameterMSB returns the MSB of the parameter number (controller 101 for RPN, 99 for NRPN).
+func (e ParameterEvent) ParameterMSB() uint8 {
+	return uint8(e.Parameter >> 7)
+}
+
+// ParameterLSB returns the LSB of the parameter number (controller 100 for RPN, 98 for NRPN).
+func (e ParameterEvent) ParameterLSB() uint8 {
+	return uint8(e.Parameter & 0x7F)
+}
+
+// String represents the event as a string.
+func (e ParameterEvent) String() string {
+	name := "RPN"
+	if e.NRPN {
+		name = "NRPN"
+	}
+
+	if e.Change == ParameterReset {
+		return fmt.Sprintf("%s channel: %v %s", name, e.Channel, e.Change)
+	}
+
+	return fmt.Sprintf("%s channel: %v parameter: %v/%v %s value: %v", name, e.Channel, e.ParameterMSB(), e.ParameterLSB(), e.Change, e.Value)
+}
+
+// parameterSelection is the state machine of the parameter selection of a single channel
+type parameterSelection struct {
+	param [2]uint8
+	set   [2]bool
+	nrpn  bool
+}
+
+// control handles the given controller. The current value of a parameter is looked up via the given function.
+// It returns true, if the controller resulted in a ParameterEvent.
+func (p *parameterSelection) control(ch, ctl, val uint8, value func(nrpn bool, param uint16) uint16) (ev ParameterEvent, ok bool) {
+	switch ctl {
+	case RegisteredParameterMSB, RegisteredParameterLSB, NonRegisteredParameterMSB, NonRegisteredParameterLSB:
+		nrpn := ctl == NonRegisteredParameterMSB || ctl == NonRegisteredParameterLSB
+
+		if nrpn != p.nrpn {
+			p.set = [2]bool{}
+			p.nrpn = nrpn
+		}
+
+		idx := 1
+		if ctl == RegisteredParameterMSB || ctl == NonRegisteredParameterMSB {
+			idx = 0
+		}
+
+		p.param[idx] = val
+		p.set[idx] = true
+
+		if p.set == [2]bool{true, true} && p.param == [2]uint8{127, 127} {
+			p.set = [2]bool{}
+			return ParameterEvent{Channel: ch, NRPN: nrpn, Change: ParameterReset}, true
+		}
+		return
+	case DataEntryMSB, DataEntryLSB, DataButtonIncrement, DataButtonDecrement:
+	default:
+		return
+	}
+
+	if p.set != [2]bool{true, true} {
+		return
+	}
+
+	ev = ParameterEvent{Channel: ch, NRPN: p.nrpn, Parameter: uint16(p.param[0])<<7 | uint16(p.param[1])}
+	v := value(ev.NRPN, ev.Parameter)
+
+	switch ctl {
+	case DataEntryMSB:
+		// a new MSB resets the LSB
+		ev.Value = uint16(val) << 7
+	case DataEntryLSB:
+		ev.Value = v&0x3F80 | uint16(val)
+	case DataButtonIncrement:
+		ev.Change = ParameterIncrement
+		ev.Value = v
+		if v < 0x3FFF {
+			ev.Value++
+		}
+	case DataButtonDecrement:
+		ev.Change = ParameterDecrement
+		ev.Value = v
+		if v > 0 {
+			ev.Value--
+		}
+	}
+
+	return ev, true
+}
+
+// reset deselects the current parameter
+func (p *parameterSelection) reset() {
+	p.set = [2]bool{}
+}
+
+// ParameterDecoder decodes the incoming registered (RPN) and non registered parameters (NRPN) of all channels,
+// i.e. the controllers 99, 98, 101, 100, 6, 38, 96 and 97.
+// It remembers the values of the parameters, so that increments and decrements result in the new value
+// (unknown parameters start at 0).
+// It can be fed with the messages of a live stream or of a SMF track.
+// A ParameterDecoder is not threadsafe.
+type ParameterDecoder struct {
+	selections [16]parameterSelection
+	values     [16][2]map[uint16]uint16
+}
+
+// NewParameterDecoder returns a new ParameterDecoder.
+func NewParameterDecoder() *ParameterDecoder {
+	return &ParameterDecoder{}
+}
+
+// Decode returns the ParameterEvent and true, if the given message changes a parameter or selects the null parameter.
+// Otherwise false is returned.
+// AllControllersOff and Reset messages deselect the current parameter. SMF meta messages are ignored.
+func (d *ParameterDecoder) Decode(msg Message) (ev ParameterEvent, ok bool) {
+	var ch, ctl, val uint8
+
+	if isReset(msg) {
+		for c := range d.selections {
+			d.selections[c].reset()
+		}
+		return
+	}
+
+	if !msg.GetControlChange(&ch, &ctl, &val) {
+		return
+	}
+
+	if ctl == AllControllersOff {
+		d.selections[ch].reset()
+		return
+	}
+
+	ev, ok = d.selections[ch].control(ch, ctl, val, func(nrpn bool, param uint16) uint16 {
+		return d.values[ch][b2i(nrpn)][param]
+	})
+
+	if ok && ev.Change != ParameterReset {
+		idx := b2i(ev.NRPN)
+		if d.values[ch][idx] == nil {
+			d.values[ch][idx] = map[uint16]uint16{}
+		}
+		d.values[ch][idx][ev.Parameter] = ev.Value
+	}
+
+	return
+}
+
+// Value returns the last value of the given parameter and if it is known.
+func (d *ParameterDecoder) Value(channel uint8, nrpn bool, param uint16) (value uint16, known bool) {
+	value, known = d.values[channel&0x0F][b2i(nrpn)][param]
+	return
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
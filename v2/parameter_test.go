@@ -0,0 +1,139 @@
+package midi_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/nrpn"
+	"gitlab.com/gomidi/midi/v2/rpn"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func TestParameterDecoder(t *testing.T) {
+	tests := []struct {
+		descr    string
+		msgs     [][]midi.Message
+		expected string
+	}{
+		{
+			"pitch bend sensitivity",
+			[][]midi.Message{rpn.PitchBendSensitivity(2, 12, 50)},
+			"RPN channel: 2 parameter: 0/0 set value: 1536\n" +
+				"RPN channel: 2 parameter: 0/0 set value: 1586\n" +
+				"RPN channel: 2 reset\n",
+		},
+		{
+			"nrpn increment and decrement",
+			[][]midi.Message{
+				nrpn.NRPN(2, 1, 8, 0, 10),
+				nrpn.Increment(2, 1, 8),
+				nrpn.Decrement(2, 1, 9),
+			},
+			"NRPN channel: 2 parameter: 1/8 set value: 0\n" +
+				"NRPN channel: 2 parameter: 1/8 set value: 10\n" +
+				"NRPN channel: 2 reset\n" +
+				"NRPN channel: 2 parameter: 1/8 increment value: 11\n" +
+				"NRPN channel: 2 reset\n" +
+				"NRPN channel: 2 parameter: 1/9 decrement value: 0\n" +
+				"NRPN channel: 2 reset\n",
+		},
+		{
+			"no parameter selected",
+			[][]midi.Message{{
+				midi.ControlChange(2, midi.DataEntryMSB, 3),
+				midi.ControlChange(2, midi.RegisteredParameterMSB, 0),
+				midi.ControlChange(2, midi.DataEntryMSB, 3),
+				midi.ControlChange(2, midi.NonRegisteredParameterLSB, 0),
+				midi.ControlChange(2, midi.DataEntryMSB, 3),
+			}},
+			"",
+		},
+		{
+			"AllControllersOff",
+			[][]midi.Message{{
+				midi.ControlChange(2, midi.RegisteredParameterMSB, 0),
+				midi.ControlChange(2, midi.RegisteredParameterLSB, 2),
+				midi.ControlChange(2, midi.DataEntryMSB, 64),
+				midi.ControlChange(2, midi.AllControllersOff, 0),
+				midi.ControlChange(2, midi.DataEntryMSB, 3),
+			}},
+			"RPN channel: 2 parameter: 0/2 set value: 8192\n",
+		},
+	}
+
+	for _, test := range tests {
+		d := midi.NewParameterDecoder()
+
+		var bf bytes.Buffer
+		for _, msgs := range test.msgs {
+			for _, msg := range msgs {
+				if ev, ok := d.Decode(msg); ok {
+					fmt.Fprintf(&bf, "%s\n", ev)
+				}
+			}
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+	}
+}
+
+func TestParameterDecoderValue(t *testing.T) {
+	d := midi.NewParameterDecoder()
+
+	for _, msg := range rpn.PitchBendSensitivity(3, 2, 0) {
+		d.Decode(msg)
+	}
+
+	if val, known := d.Value(3, false, 0); !known || val != 256 {
+		t.Errorf("Value(3, false, 0) = %v, %v; want 256, true", val, known)
+	}
+
+	if _, known := d.Value(3, true, 0); known {
+		t.Errorf("NRPN 0 should be unknown")
+	}
+}
+
+func TestParameterDecoderTrack(t *testing.T) {
+	var tr smf.Track
+	tr.Add(0, smf.MetaTempo(100))
+	tr.Add(0, midi.ControlChange(2, midi.RegisteredParameterMSB, 0))
+	tr.Add(0, midi.ControlChange(2, midi.RegisteredParameterLSB, 0))
+	tr.Add(10, smf.MetaMarker("verse"))
+	tr.Add(0, midi.ControlChange(2, midi.DataEntryMSB, 12))
+	tr.Add(10, smf.MetaLyric("la"))
+	tr.Add(0, midi.ControlChange(2, midi.DataButtonIncrement, 0))
+	tr.Close(0)
+
+	s := smf.New()
+	s.Add(tr)
+
+	var file bytes.Buffer
+	if _, err := s.WriteTo(&file); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	rd, err := smf.ReadFrom(&file)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	d := midi.NewParameterDecoder()
+
+	var bf bytes.Buffer
+	for _, ev := range rd.Tracks[0] {
+		if pev, ok := d.Decode(midi.Message(ev.Message)); ok {
+			fmt.Fprintf(&bf, "%s\n", pev)
+		}
+	}
+
+	expected := "RPN channel: 2 parameter: 0/0 set value: 1536\n" +
+		"RPN channel: 2 parameter: 0/0 increment value: 1537\n"
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,150 @@
+package midi
+
+import (
+	"fmt"
+
+	"gitlab.com/gomidi/midi/v2/internal/utils"
+)
+
+// Parse parses a string, as it is returned by Message.String, back to the message, e.g.
+//
+//	msg, err := Parse("NoteOn channel: 2 key: 60 velocity: 100")
+//
+// Channel, realtime, system common and sysex messages are supported.
+// For pitchbend messages, only the relative value is parsed.
+// For meta messages, see smf.ParseMessage.
+func Parse(s string) (Message, error) {
+	name, fields, err := utils.ParseFields(s)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q: %w", s, err)
+	}
+
+	msg, err := parseMessage(name, fields)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q: %w", s, err)
+	}
+	return msg, nil
+}
+
+func parseMessage(name string, f utils.Fields) (Message, error) {
+	var typ Type
+	for t, n := range typeNames {
+		if n == name {
+			typ = t
+			break
+		}
+	}
+
+	switch typ {
+	case TickMsg:
+		return Tick(), nil
+	case TimingClockMsg:
+		return TimingClock(), nil
+	case StartMsg:
+		return Start(), nil
+	case ContinueMsg:
+		return Continue(), nil
+	case StopMsg:
+		return Stop(), nil
+	case ActiveSenseMsg:
+		return Activesense(), nil
+	case ResetMsg:
+		return Reset(), nil
+	case TuneMsg:
+		return Tune(), nil
+	case MTCMsg:
+		v, err := f.Uint("mtc", 127)
+		return MTC(uint8(v)), err
+	case SPPMsg:
+		v, err := f.Uint("spp", 0x3FFF)
+		return SPP(uint16(v)), err
+	case SongSelectMsg:
+		v, err := f.Uint("song", 127)
+		return SongSelect(uint8(v)), err
+	case SysExMsg:
+		// the string of an empty sysex message has no data field
+		if _, has := f["data"]; !has {
+			return SysEx(nil), nil
+		}
+		bt, err := f.Bytes("data")
+		return SysEx(bt), err
+	case reservedRealTimeMsg8:
+		return Message{byteUndefined4}, nil
+	case reservedSysCommonMsg5, reservedSysCommonMsg6:
+		return parseUndefinedSysCommon(typ, f)
+	case NoteOnMsg, NoteOffMsg, PolyAfterTouchMsg, AfterTouchMsg, ControlChangeMsg, ProgramChangeMsg, PitchBendMsg:
+		return parseChannelMessage(typ, f)
+	default:
+		return nil, fmt.Errorf("unsupported message type %q", name)
+	}
+}
+
+func parseChannelMessage(typ Type, f utils.Fields) (msg Message, err error) {
+	get := func(label string) uint8 {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = f.Uint(label, 127)
+		return uint8(v)
+	}
+
+	var ch uint64
+	ch, err = f.Uint("channel", 15)
+
+	switch typ {
+	case NoteOnMsg:
+		msg = NoteOn(uint8(ch), get("key"), get("velocity"))
+	case NoteOffMsg:
+		if _, has := f["velocity"]; has {
+			msg = NoteOffVelocity(uint8(ch), get("key"), get("velocity"))
+		} else {
+			msg = NoteOff(uint8(ch), get("key"))
+		}
+	case PolyAfterTouchMsg:
+		msg = PolyAfterTouch(uint8(ch), get("key"), get("pressure"))
+	case AfterTouchMsg:
+		msg = AfterTouch(uint8(ch), get("pressure"))
+	case ControlChangeMsg:
+		msg = ControlChange(uint8(ch), get("controller"), get("value"))
+	case ProgramChangeMsg:
+		msg = ProgramChange(uint8(ch), get("program"))
+	case PitchBendMsg:
+		var rel int64
+		if err == nil {
+			rel, err = f.Int("pitch", -8192, 8191)
+		}
+		msg = Pitchbend(uint8(ch), int16(rel))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// parseUndefinedSysCommon parses the undefined system common messages 0xF4 and 0xF5 with their data bytes
+func parseUndefinedSysCommon(typ Type, f utils.Fields) (Message, error) {
+	msg := Message{byteUndefinedF4}
+	if typ == reservedSysCommonMsg6 {
+		msg[0] = byteUndefinedF5
+	}
+
+	// the string of the message has no data field, if there are no data bytes
+	if _, has := f["data"]; !has {
+		return msg, nil
+	}
+
+	bt, err := f.Bytes("data")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range bt {
+		if b > 127 {
+			return nil, fmt.Errorf("invalid data byte %X", b)
+		}
+	}
+
+	return append(msg, bt...), nil
+}
@@ -0,0 +1,89 @@
+package midi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestParse(t *testing.T) {
+	tests := []midi.Message{
+		midi.NoteOn(2, 60, 100),
+		midi.NoteOn(2, 60, 0),
+		midi.NoteOff(2, 60),
+		midi.NoteOffVelocity(2, 60, 30),
+		midi.PolyAfterTouch(3, 61, 4),
+		midi.AfterTouch(3, 5),
+		midi.ControlChange(15, midi.VolumeMSB, 127),
+		midi.ProgramChange(1, 10),
+		midi.Pitchbend(1, -8192),
+		midi.Pitchbend(1, 8191),
+		midi.TimingClock(),
+		midi.Reset(),
+		midi.MTC(0x21),
+		midi.SPP(300),
+		midi.SongSelect(3),
+		midi.Tune(),
+		midi.SysEx([]byte{0x7E, 0x7F, 0x09, 0x01}),
+		midi.Message{0xFD},
+		midi.Message{0xF4},
+		midi.Message{0xF5, 0x01, 0x02},
+	}
+
+	for _, msg := range tests {
+		got, err := midi.Parse(msg.String())
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", msg.String(), err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, msg) {
+			t.Errorf("Parse(%q) = % X; want % X", msg.String(), got, msg)
+		}
+	}
+}
+
+func TestParseUndefined(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected midi.Message
+	}{
+		{"reservedRealTime8", midi.Message{0xFD}},
+		{"reservedSysCommon5", midi.Message{0xF4}},
+		{"reservedSysCommon6 data: 01 02", midi.Message{0xF5, 0x01, 0x02}},
+	}
+
+	for _, test := range tests {
+		got, err := midi.Parse(test.str)
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", test.str, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("Parse(%q) = % X; want % X", test.str, got, test.expected)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"Unknown channel: 1",
+		"NoteOn channel: 16 key: 60 velocity: 100",
+		"NoteOn channel: 1 key: 60",
+		"NoteOn channel: 1 key: 128 velocity: 100",
+		"ControlChange channel: x controller: 1 value: 1",
+		"PitchBend channel: 1 pitch: 9000 (0)",
+		"SysExType data: XY",
+		"NoteOn foo",
+		"reservedSysCommon5 data: 01 80",
+	}
+
+	for _, s := range tests {
+		if msg, err := midi.Parse(s); err == nil {
+			t.Errorf("Parse(%q) = %s; expected error", s, msg)
+		}
+	}
+}
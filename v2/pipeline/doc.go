@@ -0,0 +1,25 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package pipeline helps with building chains of message processing stages between MIDI sources and sinks.
+
+A Pipeline passes every received message through its stages (in the order they were added) and then to
+its sinks (also in the order they were added).
+
+	p := pipeline.New(
+		pipeline.OnlyChannels(0),
+		pipeline.Transpose(midi.Octave),
+		pipeline.ScaleVelocity(0.8),
+	).To(pipeline.SendTo(out))
+
+	stop, err := p.ListenTo(in)
+
+Sources are in ports (see ListenTo) and SMF files (see Play). Also the Receive method can be passed to
+midi.ListenTo directly.
+
+Errors of stages and sinks are passed to the error handler (see OnError) as *StageError or *SinkError.
+A message that causes an error in a stage is dropped, an error in a sink does not affect the other sinks.
+*/
+package pipeline
@@ -0,0 +1,178 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// Emit passes a message to the next stage of the pipeline.
+type Emit func(msg midi.Message, timestampms int32)
+
+// Stage is a processing stage of the pipeline. It receives a message with its timestamp in milliseconds and
+// passes any number of messages to emit (none, to drop the message).
+// If a stage returns an error, the error handler of the pipeline is called.
+// Stages may be called from different goroutines (e.g. the driver and the Delay stage), so they must be threadsafe.
+type Stage func(msg midi.Message, timestampms int32, emit Emit) error
+
+// Sink is the end of a pipeline, that receives the processed messages.
+// Calls to the sinks of a pipeline are serialized.
+type Sink func(msg midi.Message, timestampms int32) error
+
+// StageError is the error of a stage.
+type StageError struct {
+	// Stage is the index of the stage within the pipeline
+	Stage int
+
+	// Message is the message that caused the error
+	Message midi.Message
+
+	Err error
+}
+
+// Error returns the error message
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %v: %s: %s", e.Stage, e.Message, e.Err)
+}
+
+// Unwrap returns the error of the stage
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// SinkError is the error of a sink.
+type SinkError struct {
+	// Sink is the index of the sink within the pipeline
+	Sink int
+
+	// Message is the message that caused the error
+	Message midi.Message
+
+	Err error
+}
+
+// Error returns the error message
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %v: %s: %s", e.Sink, e.Message, e.Err)
+}
+
+// Unwrap returns the error of the sink
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline is a chain of stages that ends in sinks.
+// The stages, sinks and the error handler must be set before the first message is received.
+type Pipeline struct {
+	stages []Stage
+	sinks  []Sink
+	onErr  func(error)
+	mx     sync.Mutex
+}
+
+// New returns a new pipeline with the given stages.
+func New(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Then appends the given stages to the pipeline.
+func (p *Pipeline) Then(stages ...Stage) *Pipeline {
+	p.stages = append(p.stages, stages...)
+	return p
+}
+
+// To appends the given sinks to the pipeline.
+func (p *Pipeline) To(sinks ...Sink) *Pipeline {
+	p.sinks = append(p.sinks, sinks...)
+	return p
+}
+
+// OnError sets the error handler for errors of stages and sinks.
+// Without an error handler, errors are ignored.
+func (p *Pipeline) OnError(fn func(error)) *Pipeline {
+	p.onErr = fn
+	return p
+}
+
+func (p *Pipeline) handleErr(err error) {
+	if p.onErr != nil {
+		p.onErr(err)
+	}
+}
+
+// Receive passes the given message through the pipeline.
+// It can be used as receiver for midi.ListenTo.
+func (p *Pipeline) Receive(msg midi.Message, timestampms int32) {
+	p.run(0, msg, timestampms)
+}
+
+func (p *Pipeline) run(stage int, msg midi.Message, timestampms int32) {
+	if msg == nil {
+		return
+	}
+
+	if stage >= len(p.stages) {
+		p.sink(msg, timestampms)
+		return
+	}
+
+	err := p.stages[stage](msg, timestampms, func(m midi.Message, ts int32) {
+		p.run(stage+1, m, ts)
+	})
+
+	if err != nil {
+		p.handleErr(&StageError{Stage: stage, Message: msg, Err: err})
+	}
+}
+
+func (p *Pipeline) sink(msg midi.Message, timestampms int32) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	for i, s := range p.sinks {
+		if err := s(msg, timestampms); err != nil {
+			p.handleErr(&SinkError{Sink: i, Message: msg, Err: err})
+		}
+	}
+}
+
+// ListenTo listens on the given port and passes the received messages through the pipeline.
+// It returns a stop function that may be called to stop the listening.
+func (p *Pipeline) ListenTo(in drivers.In, opts ...midi.Option) (stop func(), err error) {
+	return midi.ListenTo(in, p.Receive, opts...)
+}
+
+// Play passes the playable messages of the given tracks through the pipeline in realtime.
+// The timestamps are the milliseconds since the start of the SMF. It blocks until all messages have been passed.
+func (p *Pipeline) Play(tr *smf.TracksReader) error {
+	var evts []smf.TrackEvent
+
+	tr.Do(func(te smf.TrackEvent) {
+		if te.Message.IsPlayable() {
+			evts = append(evts, te)
+		}
+	})
+
+	if err := tr.Error(); err != nil {
+		return err
+	}
+
+	sort.SliceStable(evts, func(a, b int) bool {
+		return evts[a].AbsMicroSeconds < evts[b].AbsMicroSeconds
+	})
+
+	start := time.Now()
+
+	for _, te := range evts {
+		at := time.Duration(te.AbsMicroSeconds) * time.Microsecond
+		time.Sleep(at - time.Since(start))
+		p.Receive(midi.Message(te.Message), int32(at.Milliseconds()))
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package pipeline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func TestStages(t *testing.T) {
+	tests := []struct {
+		descr    string
+		stages   []Stage
+		input    []midi.Message
+		expected string
+	}{
+		{
+			"only types",
+			[]Stage{OnlyTypes(midi.NoteOnMsg)},
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.ControlChange(0, 7, 100)},
+			"NoteOn channel: 0 key: 60 velocity: 100\n",
+		},
+		{
+			"skip types",
+			[]Stage{SkipTypes(midi.RealTimeMsg)},
+			[]midi.Message{midi.TimingClock(), midi.ControlChange(0, 7, 100)},
+			"ControlChange channel: 0 controller: 7 value: 100\n",
+		},
+		{
+			"only channels",
+			[]Stage{OnlyChannels(1, 2)},
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(2, 61, 100), midi.Start()},
+			"NoteOn channel: 2 key: 61 velocity: 100\nStart\n",
+		},
+		{
+			"key range",
+			[]Stage{KeyRange(60, 61)},
+			[]midi.Message{midi.NoteOn(0, 59, 100), midi.NoteOn(0, 60, 100), midi.NoteOff(0, 62), midi.ProgramChange(0, 3)},
+			"NoteOn channel: 0 key: 60 velocity: 100\nProgramChange channel: 0 program: 3\n",
+		},
+		{
+			"remap channels",
+			[]Stage{RemapChannels(map[uint8]uint8{0: 9})},
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(1, 60, 100)},
+			"NoteOn channel: 9 key: 60 velocity: 100\nNoteOn channel: 1 key: 60 velocity: 100\n",
+		},
+		{
+			"transpose",
+			[]Stage{Transpose(midi.Octave)},
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOff(0, 120), midi.PolyAfterTouch(0, 60, 3)},
+			"NoteOn channel: 0 key: 72 velocity: 100\nPolyAfterTouch channel: 0 key: 72 pressure: 3\n",
+		},
+		{
+			"scale velocity",
+			[]Stage{ScaleVelocity(0.5)},
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(0, 60, 1), midi.NoteOn(0, 60, 0)},
+			"NoteOn channel: 0 key: 60 velocity: 50\nNoteOn channel: 0 key: 60 velocity: 1\nNoteOn channel: 0 key: 60 velocity: 0\n",
+		},
+		{
+			"order",
+			[]Stage{Transpose(midi.Octave), KeyRange(60, 70)},
+			[]midi.Message{midi.NoteOn(0, 50, 100), midi.NoteOn(0, 60, 100)},
+			"NoteOn channel: 0 key: 62 velocity: 100\n",
+		},
+	}
+
+	for _, test := range tests {
+		var bf bytes.Buffer
+
+		p := New(test.stages...).To(Func(func(msg midi.Message, timestampms int32) {
+			fmt.Fprintf(&bf, "%s\n", msg)
+		}))
+
+		for _, msg := range test.input {
+			p.Receive(msg, 0)
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs []error
+	var received int
+	errFail := errors.New("fail")
+
+	p := New(Map(func(msg midi.Message) (midi.Message, error) {
+		if msg.Is(midi.ControlChangeMsg) {
+			return nil, errFail
+		}
+		return msg, nil
+	})).To(
+		func(msg midi.Message, timestampms int32) error {
+			return errFail
+		},
+		Func(func(msg midi.Message, timestampms int32) {
+			received++
+		}),
+	).OnError(func(err error) {
+		errs = append(errs, err)
+	})
+
+	p.Receive(midi.ControlChange(0, 7, 1), 0)
+	p.Receive(midi.NoteOn(0, 60, 100), 0)
+
+	if received != 1 {
+		t.Errorf("expected 1 received message, got %v", received)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", len(errs))
+	}
+
+	var serr *StageError
+	if !errors.As(errs[0], &serr) || serr.Stage != 0 || !errors.Is(errs[0], errFail) {
+		t.Errorf("expected stage error, got %v", errs[0])
+	}
+
+	var skerr *SinkError
+	if !errors.As(errs[1], &skerr) || skerr.Sink != 0 {
+		t.Errorf("expected sink error, got %v", errs[1])
+	}
+}
+
+func TestDelay(t *testing.T) {
+	done := make(chan int32)
+
+	p := New(Delay(20 * time.Millisecond)).To(Func(func(msg midi.Message, timestampms int32) {
+		done <- timestampms
+	}))
+
+	start := time.Now()
+	p.Receive(midi.NoteOn(0, 60, 100), 10)
+
+	ts := <-done
+
+	if time.Since(start) < 20*time.Millisecond {
+		t.Errorf("message was not delayed")
+	}
+
+	if ts != 30 {
+		t.Errorf("expected timestamp 30, got %v", ts)
+	}
+}
+
+func TestDelayOrder(t *testing.T) {
+	var got []string
+	done := make(chan bool)
+
+	p := New(Delay(5 * time.Millisecond)).To(Func(func(msg midi.Message, timestampms int32) {
+		got = append(got, msg.String())
+		if len(got) == 200 {
+			close(done)
+		}
+	}))
+
+	var want []string
+	for key := uint8(0); key < 100; key++ {
+		for _, msg := range []midi.Message{midi.NoteOn(0, key, 100), midi.NoteOff(0, key)} {
+			want = append(want, msg.String())
+			p.Receive(msg, 0)
+		}
+	}
+
+	<-done
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("the delayed messages are not in order")
+	}
+}
+
+func TestListenRecord(t *testing.T) {
+	drv := testdrv.New("pipeline")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	in, out := ins[0], outs[0]
+
+	var tr smf.Track
+	ticks := smf.MetricTicks(960)
+
+	p := New(Transpose(midi.Fifth)).To(Record(&tr, ticks, 120))
+
+	stop, err := p.ListenTo(in)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	out.Open()
+	out.Send(midi.NoteOn(0, 60, 100))
+	drv.Sleep(500 * time.Millisecond)
+	out.Send(midi.NoteOff(0, 60))
+	stop()
+
+	var bf bytes.Buffer
+	for _, ev := range tr {
+		fmt.Fprintf(&bf, "%v %s\n", ev.Delta, ev.Message)
+	}
+
+	expected := "0 NoteOn channel: 0 key: 67 velocity: 100\n960 NoteOff channel: 0 key: 67\n"
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// SendTo is a sink that sends the messages to the given out port.
+// The port is opened, if it is not already open.
+func SendTo(out drivers.Out) Sink {
+	return func(msg midi.Message, timestampms int32) error {
+		if !out.IsOpen() {
+			if err := out.Open(); err != nil {
+				return err
+			}
+		}
+		return out.Send(msg)
+	}
+}
+
+// Record is a sink that adds the messages to the given track. The delta ticks are calculated based on the
+// timestamps of the messages, the given resolution and the tempo in beats per minute.
+// The timestamp of the first message is the start of the track.
+func Record(tr *smf.Track, ticks smf.MetricTicks, bpm float64) Sink {
+	var started bool
+	var last int32
+
+	return func(msg midi.Message, timestampms int32) error {
+		if !started {
+			started = true
+			last = timestampms
+		}
+
+		var delta uint32
+		if timestampms > last {
+			delta = ticks.Ticks(bpm, time.Duration(timestampms-last)*time.Millisecond)
+		}
+		last = timestampms
+		tr.Add(delta, msg)
+		return nil
+	}
+}
+
+// Func is a sink that calls the given function for every message.
+func Func(fn func(msg midi.Message, timestampms int32)) Sink {
+	return func(msg midi.Message, timestampms int32) error {
+		fn(msg, timestampms)
+		return nil
+	}
+}
@@ -0,0 +1,208 @@
+package pipeline
+
+import (
+	"sync"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Filter is a stage that only passes the messages for which the given function returns true.
+func Filter(fn func(msg midi.Message) bool) Stage {
+	return func(msg midi.Message, timestampms int32, emit Emit) error {
+		if fn(msg) {
+			emit(msg, timestampms)
+		}
+		return nil
+	}
+}
+
+// OnlyTypes is a stage that only passes the messages of the given types.
+func OnlyTypes(types ...midi.Type) Stage {
+	return Filter(func(msg midi.Message) bool {
+		return msg.IsOneOf(types...)
+	})
+}
+
+// SkipTypes is a stage that drops the messages of the given types.
+func SkipTypes(types ...midi.Type) Stage {
+	return Filter(func(msg midi.Message) bool {
+		return !msg.IsOneOf(types...)
+	})
+}
+
+// OnlyChannels is a stage that only passes the channel messages of the given channels.
+// Non channel messages are passed through.
+func OnlyChannels(channels ...uint8) Stage {
+	return Filter(func(msg midi.Message) bool {
+		var ch uint8
+		if !msg.GetChannel(&ch) {
+			return true
+		}
+		for _, c := range channels {
+			if c == ch {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// getKey returns the key of note on, note off and polyphonic aftertouch messages
+func getKey(msg midi.Message) (key uint8, has bool) {
+	switch {
+	case msg.GetNoteOn(nil, &key, nil), msg.GetNoteOff(nil, &key, nil), msg.GetPolyAfterTouch(nil, &key, nil):
+		return key, true
+	default:
+		return 0, false
+	}
+}
+
+// setKey returns a copy of the given note on, note off or polyphonic aftertouch message with the given key
+func setKey(msg midi.Message, key uint8) midi.Message {
+	m := make(midi.Message, len(msg))
+	copy(m, msg)
+	m[1] = key
+	return m
+}
+
+// KeyRange is a stage that drops note on, note off and polyphonic aftertouch messages with keys outside of the
+// range from (including) lowest to (including) highest.
+// All other messages are passed through.
+func KeyRange(lowest, highest uint8) Stage {
+	return Filter(func(msg midi.Message) bool {
+		key, has := getKey(msg)
+		return !has || (key >= lowest && key <= highest)
+	})
+}
+
+// Map is a stage that replaces every message by the result of the given function.
+// If the function returns a nil message, the message is dropped.
+func Map(fn func(msg midi.Message) (midi.Message, error)) Stage {
+	return func(msg midi.Message, timestampms int32, emit Emit) error {
+		m, err := fn(msg)
+		if err != nil {
+			return err
+		}
+		emit(m, timestampms)
+		return nil
+	}
+}
+
+// RemapChannels is a stage that moves channel messages from one channel to another, based on the given map
+// (key: source channel, value: target channel). Messages of channels that are not part of the map are passed through.
+func RemapChannels(mapping map[uint8]uint8) Stage {
+	return Map(func(msg midi.Message) (midi.Message, error) {
+		var ch uint8
+		if !msg.GetChannel(&ch) {
+			return msg, nil
+		}
+
+		to, has := mapping[ch]
+		if !has || to > 15 {
+			return msg, nil
+		}
+
+		m := make(midi.Message, len(msg))
+		copy(m, msg)
+		m[0] = msg[0]&0xF0 | to
+		return m, nil
+	})
+}
+
+// Transpose is a stage that transposes note on, note off and polyphonic aftertouch messages by the given interval.
+// Messages that would be transposed outside of the MIDI key range are dropped.
+func Transpose(i midi.Interval) Stage {
+	return func(msg midi.Message, timestampms int32, emit Emit) error {
+		key, has := getKey(msg)
+		if !has {
+			emit(msg, timestampms)
+			return nil
+		}
+
+		k := int(key) + int(i)
+		if k < 0 || k > 127 {
+			return nil
+		}
+
+		emit(setKey(msg, uint8(k)), timestampms)
+		return nil
+	}
+}
+
+// ScaleVelocity is a stage that multiplies the velocity of note on messages by the given factor.
+// The resulting velocity is kept within 1 and 127, so that a note on never becomes a note off.
+func ScaleVelocity(factor float64) Stage {
+	return Map(func(msg midi.Message) (midi.Message, error) {
+		var ch, key, vel uint8
+		if !msg.GetNoteStart(&ch, &key, &vel) {
+			return msg, nil
+		}
+
+		v := int(float64(vel)*factor + 0.5)
+
+		switch {
+		case v < 1:
+			v = 1
+		case v > 127:
+			v = 127
+		}
+
+		return midi.NoteOn(ch, key, uint8(v)), nil
+	})
+}
+
+// Delay is a stage that passes every message after the given duration. The timestamp is increased accordingly.
+// The following stages and sinks are then called from a goroutine of the stage, one message after the other
+// in the order the messages were received.
+func Delay(d time.Duration) Stage {
+	q := &delayQueue{d: d}
+	return q.stage
+}
+
+// delayed is a message that waits within a delayQueue
+type delayed struct {
+	msg         midi.Message
+	timestampms int32
+	due         time.Time
+	emit        Emit
+}
+
+// delayQueue passes the delayed messages in order from a single goroutine that runs while there are messages
+type delayQueue struct {
+	d       time.Duration
+	mx      sync.Mutex
+	queue   []delayed
+	running bool
+}
+
+func (q *delayQueue) stage(msg midi.Message, timestampms int32, emit Emit) error {
+	q.mx.Lock()
+	defer q.mx.Unlock()
+
+	q.queue = append(q.queue, delayed{msg, timestampms + int32(q.d.Milliseconds()), time.Now().Add(q.d), emit})
+
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+
+	return nil
+}
+
+func (q *delayQueue) run() {
+	for {
+		q.mx.Lock()
+		if len(q.queue) == 0 {
+			q.running = false
+			q.mx.Unlock()
+			return
+		}
+		next := q.queue[0]
+		q.queue = q.queue[1:]
+		q.mx.Unlock()
+
+		time.Sleep(time.Until(next.due))
+		next.emit(next.msg, next.timestampms)
+	}
+}
@@ -0,0 +1,285 @@
+package midi
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+var (
+	// ErrUnexpectedData is the error for data bytes without a preceding status byte (and no running status)
+	ErrUnexpectedData = errors.New("data bytes without status")
+
+	// ErrUndefinedStatus is the error for the undefined status bytes 0xF4, 0xF5 and 0xFD
+	ErrUndefinedStatus = errors.New("undefined status")
+
+	// ErrIncompleteMessage is the error for a message that was interrupted by a status byte or by the end of the input
+	ErrIncompleteMessage = errors.New("incomplete message")
+
+	// ErrSysExTooLarge is the error for a sysex message that exceeds the maximum size of the Reader
+	ErrSysExTooLarge = drivers.ErrSysExTooLarge
+)
+
+// ReadError is returned by the Reader for malformed input.
+// The Reader can continue reading after a ReadError.
+type ReadError struct {
+	// Err is one of ErrUnexpectedData, ErrUndefinedStatus, ErrIncompleteMessage or ErrSysExTooLarge
+	Err error
+
+	// Data are the bytes that could not be turned into a message
+	Data []byte
+}
+
+// Error returns the error message
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("%s: % X", e.Err.Error(), e.Data)
+}
+
+// Unwrap returns the underlying error, so that errors.Is can be used.
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
+// ReaderOption is an option for the Reader
+type ReaderOption func(*Reader)
+
+// MaxSysExSize is an option to set the maximum size (in bytes, including 0xF0 and 0xF7) of sysex messages for the Reader.
+// Larger sysex messages are skipped and an ErrSysExTooLarge is returned instead.
+// When size is 0 (the default), the size is unlimited.
+func MaxSysExSize(size int) ReaderOption {
+	return func(r *Reader) {
+		r.maxSysEx = size
+	}
+}
+
+// Reader reads complete MIDI messages from a byte stream of "over the wire" MIDI data (e.g. from a serial device,
+// a pipe, a socket or a dump file).
+//
+// The Reader
+//
+//   - converts running status into complete messages
+//   - returns realtime messages immediately, also when they are interleaved within other messages or sysex
+//   - treats any non-realtime status byte within a sysex as the end of the sysex ("dropped 0xF7") and
+//     returns the sysex with an added 0xF7
+//   - ignores unpaired 0xF7 bytes, apart from cancelling running status
+//   - returns a *ReadError for malformed input
+//
+// A Reader is not threadsafe.
+type Reader struct {
+	rd       *bufio.Reader
+	maxSysEx int
+
+	status    byte
+	msg       []byte
+	sysex     []byte
+	inSysEx   bool
+	skipSysEx bool
+	undefined bool
+
+	unread    byte
+	hasUnread bool
+}
+
+// NewReader returns a Reader that reads from the given io.Reader.
+func NewReader(rd io.Reader, opts ...ReaderOption) *Reader {
+	r := &Reader{rd: bufio.NewReader(rd)}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *Reader) readByte() (byte, error) {
+	if r.hasUnread {
+		r.hasUnread = false
+		return r.unread, nil
+	}
+	return r.rd.ReadByte()
+}
+
+func (r *Reader) unreadByte(b byte) {
+	r.unread = b
+	r.hasUnread = true
+}
+
+// dataLen returns the number of data bytes for the given status byte
+func dataLen(status byte) int {
+	switch {
+	case status >= 0xC0 && status <= 0xDF:
+		return 1
+	case status < 0xF0:
+		return 2
+	case status == byteMIDITimingCodeMessage, status == byteSysSongSelect:
+		return 1
+	case status == byteSysSongPositionPointer:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// incomplete returns the error for an incomplete message or sysex and resets them
+func (r *Reader) incomplete() error {
+	var data []byte
+
+	switch {
+	case r.inSysEx:
+		data = r.sysex
+		r.sysex = nil
+		r.inSysEx = false
+	default:
+		data = r.msg
+		r.msg = nil
+	}
+
+	return &ReadError{Err: ErrIncompleteMessage, Data: data}
+}
+
+// Read reads the next complete message.
+// For malformed input a *ReadError is returned and the reading may go on.
+// Any other error is the error of the underlying io.Reader (e.g. io.EOF).
+func (r *Reader) Read() (Message, error) {
+	for {
+		b, err := r.readByte()
+
+		if err != nil {
+			if len(r.msg) > 0 || (r.inSysEx && !r.skipSysEx) {
+				return nil, r.incomplete()
+			}
+			return nil, err
+		}
+
+		switch {
+
+		// realtime messages are passed through in any case and don't affect any state
+		case b >= 0xF8:
+			if b == byteUndefined4 {
+				return nil, &ReadError{Err: ErrUndefinedStatus, Data: []byte{b}}
+			}
+			return Message{b}, nil
+
+		case r.inSysEx:
+			m, err := r.withinSysEx(b)
+			if m != nil || err != nil {
+				return m, err
+			}
+
+		case b == 0xF0:
+			if len(r.msg) > 0 {
+				r.unreadByte(b)
+				return nil, r.incomplete()
+			}
+			r.status = 0
+			r.undefined = false
+			r.inSysEx = true
+			r.skipSysEx = false
+			r.sysex = []byte{b}
+
+		// [MIDI] permits 0xF7 octets that are not part of a (0xF0, 0xF7) pair
+		// to appear on a MIDI 1.0 DIN cable.  Unpaired 0xF7 octets have no
+		// semantic meaning in MIDI apart from cancelling running status.
+		case b == 0xF7:
+			if len(r.msg) > 0 {
+				r.unreadByte(b)
+				return nil, r.incomplete()
+			}
+			r.status = 0
+			r.undefined = false
+
+		case b >= 0x80:
+			if len(r.msg) > 0 {
+				r.unreadByte(b)
+				return nil, r.incomplete()
+			}
+			r.undefined = false
+
+			if b < 0xF0 {
+				r.status = b
+				r.msg = []byte{b}
+				continue
+			}
+
+			// system common messages cancel the running status
+			r.status = 0
+
+			switch b {
+			case byteSysTuneRequest:
+				return Tune(), nil
+			case byteMIDITimingCodeMessage, byteSysSongPositionPointer, byteSysSongSelect:
+				r.msg = []byte{b}
+			default:
+				// 0xF4 and 0xF5: the following data bytes belong to the undefined message
+				r.undefined = true
+				return nil, &ReadError{Err: ErrUndefinedStatus, Data: []byte{b}}
+			}
+
+		// data byte
+		default:
+			if len(r.msg) == 0 {
+				switch {
+				case r.undefined:
+					continue
+				case r.status != 0:
+					r.msg = []byte{r.status}
+				default:
+					return nil, &ReadError{Err: ErrUnexpectedData, Data: []byte{b}}
+				}
+			}
+
+			r.msg = append(r.msg, b)
+
+			if len(r.msg) > dataLen(r.msg[0]) {
+				m := Message(r.msg)
+				r.msg = nil
+				return m, nil
+			}
+		}
+	}
+}
+
+// withinSysEx handles a non-realtime byte within a sysex
+func (r *Reader) withinSysEx(b byte) (Message, error) {
+	switch {
+	case b == 0xF7:
+		r.inSysEx = false
+		if r.skipSysEx {
+			r.sysex = nil
+			return nil, nil
+		}
+		m := Message(append(r.sysex, b))
+		r.sysex = nil
+		return m, nil
+
+	// "dropped 0xF7": the status byte ends the sysex and starts the next message
+	case b >= 0x80:
+		r.unreadByte(b)
+		r.inSysEx = false
+		if r.skipSysEx {
+			r.sysex = nil
+			return nil, nil
+		}
+		m := Message(append(r.sysex, 0xF7))
+		r.sysex = nil
+		return m, nil
+
+	case r.skipSysEx:
+		return nil, nil
+
+	default:
+		r.sysex = append(r.sysex, b)
+
+		// one byte is reserved for the 0xF7
+		if r.maxSysEx > 0 && len(r.sysex) >= r.maxSysEx {
+			data := r.sysex
+			r.sysex = nil
+			r.skipSysEx = true
+			return nil, &ReadError{Err: ErrSysExTooLarge, Data: data}
+		}
+		return nil, nil
+	}
+}
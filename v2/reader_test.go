@@ -0,0 +1,111 @@
+package midi_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		descr    string
+		input    []byte
+		expected string
+	}{
+		{
+			"running status",
+			[]byte{0x92, 0x41, 0x78, 0x37, 0x78, 0x41, 0x00, 0xC1, 0x05, 0x06},
+			"92 41 78\n92 37 78\n92 41 00\nC1 05\nC1 06\n",
+		},
+		{
+			"realtime within channel message",
+			[]byte{0x90, 0x3C, 0xF8, 0x64},
+			"F8\n90 3C 64\n",
+		},
+		{
+			"realtime within sysex",
+			[]byte{0xF0, 0x7E, 0xF8, 0x09, 0xF7},
+			"F8\nF0 7E 09 F7\n",
+		},
+		{
+			"dropped F7",
+			[]byte{0xF0, 0x7E, 0x09, 0x90, 0x3C, 0x64},
+			"F0 7E 09 F7\n90 3C 64\n",
+		},
+		{
+			"syscommon cancels running status",
+			[]byte{0x90, 0x3C, 0x64, 0xF2, 0x01, 0x02, 0x3C, 0x00},
+			"90 3C 64\nF2 01 02\nerr: data bytes without status: 3C\nerr: data bytes without status: 00\n",
+		},
+		{
+			"unpaired F7",
+			[]byte{0x90, 0x3C, 0x64, 0xF7, 0xF6},
+			"90 3C 64\nF6\n",
+		},
+		{
+			"undefined",
+			[]byte{0xF4, 0x01, 0x02, 0xFD, 0xB0, 0x07, 0x64},
+			"err: undefined status: F4\nerr: undefined status: FD\nB0 07 64\n",
+		},
+		{
+			"incomplete",
+			[]byte{0x90, 0x3C, 0xB0, 0x07, 0x64, 0x90},
+			"err: incomplete message: 90 3C\nB0 07 64\nerr: incomplete message: 90\n",
+		},
+		{
+			"incomplete sysex",
+			[]byte{0xF0, 0x7E, 0x09},
+			"err: incomplete message: F0 7E 09\n",
+		},
+	}
+
+	for _, test := range tests {
+		var bf bytes.Buffer
+		rd := midi.NewReader(bytes.NewReader(test.input))
+
+		for {
+			msg, err := rd.Read()
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				var rerr *midi.ReadError
+				if !errors.As(err, &rerr) {
+					t.Fatalf("[%s] unexpected error: %v", test.descr, err)
+				}
+				fmt.Fprintf(&bf, "err: %s\n", err)
+				continue
+			}
+
+			fmt.Fprintf(&bf, "% X\n", []byte(msg))
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+	}
+}
+
+func TestReaderMaxSysEx(t *testing.T) {
+	input := []byte{0xF0, 0x01, 0x02, 0x03, 0x04, 0xF7, 0xF0, 0x01, 0xF7}
+	rd := midi.NewReader(bytes.NewReader(input), midi.MaxSysExSize(4))
+
+	_, err := rd.Read()
+	if !errors.Is(err, midi.ErrSysExTooLarge) {
+		t.Fatalf("expected ErrSysExTooLarge, got %v", err)
+	}
+
+	msg, err := rd.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := fmt.Sprintf("% X", []byte(msg)), "F0 01 F7"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+}
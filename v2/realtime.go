@@ -17,7 +17,7 @@ var rtMessages = map[byte]Type{
 	byteStart:/* RealTimeMsg.Set(StartMsg), */ StartMsg,
 	byteContinue:/* RealTimeMsg.Set(ContinueMsg), */ ContinueMsg,
 	byteStop:/* RealTimeMsg.Set(StopMsg), */ StopMsg,
-	byteUndefined4:/* RealTimeMsg.Set(UndefinedMsg), */ UnknownMsg,
+	byteUndefined4:/* RealTimeMsg.Set(UndefinedMsg), */ reservedRealTimeMsg8,
 	byteActivesense:/* RealTimeMsg.Set(ActiveSenseMsg), */ ActiveSenseMsg,
 	byteReset:/* RealTimeMsg.Set(ResetMsg), */ ResetMsg,
 }
@@ -63,3 +63,9 @@ func Activesense() Message {
 func Reset() Message {
 	return []byte{byteReset}
 }
+
+// isReset returns true, if the message is a reset message. In contrast to Is(ResetMsg), it is false for
+// the SMF meta messages that also start with 0xFF.
+func isReset(msg Message) bool {
+	return len(msg) == 1 && msg[0] == byteReset
+}
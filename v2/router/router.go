@@ -0,0 +1,406 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package router connects multiple MIDI in ports to multiple MIDI out ports.
+
+Every Route connects one in port with one out port and may filter by channel and message type,
+remap channels and have further pipeline stages. Several routes may start at the same in port (splitting) and end at
+the same out port (merging).
+
+Routes can be added and removed while messages are flowing. When a route is removed, note off messages are sent for
+the notes that are still sounding on its out port.
+
+Since the router can't know about connections outside of the program (e.g. MIDI thru cables, loopback or virtual ports),
+those can be declared via Loopback, so that routes that would create a feedback loop are refused.
+*/
+package router
+
+import (
+	"errors"
+	"sync"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/pipeline"
+)
+
+var (
+	// ErrFeedbackLoop is returned, if a route or loopback would create a feedback loop
+	ErrFeedbackLoop = errors.New("routing feedback loop")
+
+	// ErrUnknownRoute is returned, if a route is not part of the router
+	ErrUnknownRoute = errors.New("unknown route")
+)
+
+// Route is a connection from an in port to an out port
+type Route struct {
+	In  drivers.In
+	Out drivers.Out
+
+	// Channels are the channels that are passed (nil: every channel). Non channel messages are not affected.
+	Channels []uint8
+
+	// Types are the message types that are passed (nil: every type).
+	Types []midi.Type
+
+	// ChannelMap moves channel messages from one channel (key) to another (value).
+	ChannelMap map[uint8]uint8
+
+	// Stages are further pipeline stages that are run after the filtering and remapping.
+	Stages []pipeline.Stage
+}
+
+// RouteID identifies a route within a router
+type RouteID int
+
+type route struct {
+	Route
+	id      RouteID
+	pl      *pipeline.Pipeline
+	inp     *input
+	out     *output
+	notes   *midi.NoteTracker
+	removed bool
+}
+
+// output serializes the sending to an out port, since several routes may send to it.
+// It knows the routes to the out port, so that notes that are sounding via other routes are not silenced.
+type output struct {
+	mx     sync.Mutex
+	out    drivers.Out
+	routes []*route
+}
+
+type input struct {
+	stop   func()
+	routes []*route
+
+	// ready is closed, when the listening has been started (or failed with err)
+	ready chan struct{}
+	err   error
+}
+
+// Router connects in ports to out ports via routes.
+type Router struct {
+	mx        sync.RWMutex
+	nextID    RouteID
+	routes    map[RouteID]*route
+	inputs    map[drivers.In]*input
+	outputs   map[drivers.Out]*output
+	loopbacks map[drivers.Out][]drivers.In
+	opts      []midi.Option
+	onErr     func(error)
+}
+
+// New returns a new router. The given options are used for listening on the in ports.
+func New(opts ...midi.Option) *Router {
+	return &Router{
+		routes:    map[RouteID]*route{},
+		inputs:    map[drivers.In]*input{},
+		outputs:   map[drivers.Out]*output{},
+		loopbacks: map[drivers.Out][]drivers.In{},
+		opts:      opts,
+	}
+}
+
+// OnError sets the handler for errors that happen while routing.
+// It must be set before the first route is added.
+func (r *Router) OnError(fn func(error)) *Router {
+	r.onErr = fn
+	return r
+}
+
+func (r *Router) handleErr(err error) {
+	if r.onErr != nil {
+		r.onErr(err)
+	}
+}
+
+// Loopback declares that messages sent to the given out port arrive at the given in port
+// (e.g. because of a MIDI thru cable or a virtual port).
+// It returns ErrFeedbackLoop, if that would create a feedback loop with the existing routes.
+func (r *Router) Loopback(out drivers.Out, in drivers.In) error {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+
+	r.loopbacks[out] = append(r.loopbacks[out], in)
+
+	for _, rt := range r.routes {
+		if r.reaches(rt.Out, rt.In, map[drivers.In]bool{}) {
+			r.loopbacks[out] = r.loopbacks[out][:len(r.loopbacks[out])-1]
+			return ErrFeedbackLoop
+		}
+	}
+
+	return nil
+}
+
+// reaches returns true, if messages sent to the given out port may arrive at the given in port
+func (r *Router) reaches(out drivers.Out, target drivers.In, visited map[drivers.In]bool) bool {
+	for _, in := range r.loopbacks[out] {
+		if in == target {
+			return true
+		}
+
+		if visited[in] {
+			continue
+		}
+		visited[in] = true
+
+		if inp, has := r.inputs[in]; has {
+			for _, rt := range inp.routes {
+				if r.reaches(rt.Out, target, visited) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Add adds the given route and starts listening on its in port, if needed.
+// It returns ErrFeedbackLoop, if the route would create a feedback loop.
+func (r *Router) Add(rt Route) (id RouteID, err error) {
+	r.mx.Lock()
+
+	if r.reaches(rt.Out, rt.In, map[drivers.In]bool{}) {
+		r.mx.Unlock()
+		return 0, ErrFeedbackLoop
+	}
+
+	if !rt.Out.IsOpen() {
+		if err = rt.Out.Open(); err != nil {
+			r.mx.Unlock()
+			return 0, err
+		}
+	}
+
+	r.nextID++
+	_rt := &route{Route: rt, id: r.nextID, notes: midi.NewNoteTracker()}
+
+	out, has := r.outputs[rt.Out]
+	if !has {
+		out = &output{out: rt.Out}
+		r.outputs[rt.Out] = out
+	}
+	out.mx.Lock()
+	out.routes = append(out.routes, _rt)
+	out.mx.Unlock()
+	_rt.out = out
+
+	var stages []pipeline.Stage
+
+	if rt.Channels != nil {
+		stages = append(stages, pipeline.OnlyChannels(rt.Channels...))
+	}
+
+	if rt.Types != nil {
+		stages = append(stages, pipeline.OnlyTypes(rt.Types...))
+	}
+
+	if rt.ChannelMap != nil {
+		stages = append(stages, pipeline.RemapChannels(rt.ChannelMap))
+	}
+
+	_rt.pl = pipeline.New(append(stages, rt.Stages...)...).To(_rt.send).OnError(r.handleErr)
+
+	inp, listening := r.inputs[rt.In]
+	if !listening {
+		inp = &input{ready: make(chan struct{})}
+		r.inputs[rt.In] = inp
+	}
+	_rt.inp = inp
+
+	// copy on write, since the slice is read while receiving
+	inp.routes = append(append([]*route{}, inp.routes...), _rt)
+	r.routes[_rt.id] = _rt
+	r.mx.Unlock()
+
+	if listening {
+		// the listening might still be started by another route
+		<-inp.ready
+
+		if inp.err != nil {
+			r.Remove(_rt.id)
+			return 0, inp.err
+		}
+
+		return _rt.id, nil
+	}
+
+	stop, err := midi.ListenTo(rt.In, func(msg midi.Message, timestampms int32) {
+		r.receive(inp, msg, timestampms)
+	}, r.opts...)
+
+	r.mx.Lock()
+	inp.stop = stop
+	inp.err = err
+
+	if err != nil && r.inputs[rt.In] == inp {
+		// the next route for the in port starts a new listening
+		delete(r.inputs, rt.In)
+	}
+
+	// all routes of the in port have been removed in the meantime
+	unused := err == nil && len(inp.routes) == 0
+	r.mx.Unlock()
+
+	close(inp.ready)
+
+	// must be called without lock, since the driver may wait for a running receive
+	if unused {
+		stop()
+	}
+
+	if err != nil {
+		r.Remove(_rt.id)
+		return 0, err
+	}
+
+	return _rt.id, nil
+}
+
+func (r *Router) receive(inp *input, msg midi.Message, timestampms int32) {
+	r.mx.RLock()
+	routes := inp.routes
+	r.mx.RUnlock()
+
+	for _, rt := range routes {
+		rt.pl.Receive(msg, timestampms)
+	}
+}
+
+// send is the sink of the route pipeline
+func (rt *route) send(msg midi.Message, timestampms int32) error {
+	rt.out.mx.Lock()
+	defer rt.out.mx.Unlock()
+
+	if rt.removed {
+		return nil
+	}
+
+	err := rt.out.out.Send(msg)
+	if err == nil {
+		rt.notes.Track(msg)
+	}
+	return err
+}
+
+// silence sends note off messages for the sounding notes of the route and prevents further sending.
+// Notes and hold pedals that are held via other routes to the same out port are not affected.
+func (rt *route) silence() (err error) {
+	rt.out.mx.Lock()
+	defer rt.out.mx.Unlock()
+
+	rt.removed = true
+
+	for _, msg := range rt.notes.SilenceChannel(-1) {
+		if rt.out.holds(msg) {
+			continue
+		}
+
+		if e := rt.out.out.Send(msg); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+// holds returns true, if the note or hold pedal that the given message releases is held via a route to the out port.
+// It must be called with the lock of the output.
+func (o *output) holds(msg midi.Message) bool {
+	var ch, key, ctl, val uint8
+
+	for _, rt := range o.routes {
+		switch {
+		case msg.GetNoteEnd(&ch, &key):
+			if rt.notes.IsSounding(ch, key) {
+				return true
+			}
+		case msg.GetControlChange(&ch, &ctl, &val):
+			if ctl == midi.HoldPedalSwitch && rt.notes.IsPedalPressed(ch) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Remove removes the route with the given id and sends note off messages for the notes that are still sounding.
+// If it was the last route of its in port, the listening on the in port is stopped.
+func (r *Router) Remove(id RouteID) error {
+	r.mx.Lock()
+
+	rt, has := r.routes[id]
+	if !has {
+		r.mx.Unlock()
+		return ErrUnknownRoute
+	}
+
+	delete(r.routes, id)
+
+	var stop func()
+	inp := rt.inp
+
+	var routes []*route
+	for _, _rt := range inp.routes {
+		if _rt != rt {
+			routes = append(routes, _rt)
+		}
+	}
+	inp.routes = routes
+
+	if len(routes) == 0 {
+		stop = inp.stop
+		if r.inputs[rt.In] == inp {
+			delete(r.inputs, rt.In)
+		}
+	}
+
+	rt.out.mx.Lock()
+	var others []*route
+	for _, _rt := range rt.out.routes {
+		if _rt != rt {
+			others = append(others, _rt)
+		}
+	}
+	rt.out.routes = others
+	rt.out.mx.Unlock()
+
+	if len(others) == 0 {
+		delete(r.outputs, rt.Out)
+	}
+
+	r.mx.Unlock()
+
+	// must be called without lock, since the driver may wait for a running receive
+	if stop != nil {
+		stop()
+	}
+
+	return rt.silence()
+}
+
+// Routes returns the ids of the current routes and the routes.
+func (r *Router) Routes() map[RouteID]Route {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	res := make(map[RouteID]Route, len(r.routes))
+	for id, rt := range r.routes {
+		res[id] = rt.Route
+	}
+	return res
+}
+
+// Close removes all routes. The ports are not closed.
+func (r *Router) Close() (err error) {
+	for id := range r.Routes() {
+		if e := r.Remove(id); e != nil {
+			err = e
+		}
+	}
+	return
+}
@@ -0,0 +1,223 @@
+package router
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+func ports(name string) (drivers.In, drivers.Out) {
+	drv := testdrv.New(name)
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	return ins[0], outs[0]
+}
+
+func TestRouting(t *testing.T) {
+	inA, outA := ports("A")
+	inB, outB := ports("B")
+
+	var bf bytes.Buffer
+	stop, _ := midi.ListenTo(inB, func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	})
+	defer stop()
+
+	r := New()
+
+	id, err := r.Add(Route{
+		In:         inA,
+		Out:        outB,
+		Channels:   []uint8{0, 1},
+		Types:      []midi.Type{midi.NoteOnMsg, midi.NoteOffMsg},
+		ChannelMap: map[uint8]uint8{0: 5},
+	})
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	outA.Open()
+	outA.Send(midi.NoteOn(0, 60, 100))
+	outA.Send(midi.NoteOn(1, 61, 100))
+	outA.Send(midi.NoteOn(2, 62, 100))
+	outA.Send(midi.ControlChange(0, 7, 100))
+	outA.Send(midi.NoteOff(1, 61))
+
+	if err := r.Remove(id); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if len(r.Routes()) != 0 {
+		t.Errorf("expected no routes")
+	}
+
+	expected := `NoteOn channel: 5 key: 60 velocity: 100
+NoteOn channel: 1 key: 61 velocity: 100
+NoteOff channel: 1 key: 61
+NoteOff channel: 5 key: 60
+`
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+
+	if err := r.Remove(id); !errors.Is(err, ErrUnknownRoute) {
+		t.Errorf("expected ErrUnknownRoute, got %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	inA, outA := ports("A")
+	inB, outB := ports("B")
+	inC, outC := ports("C")
+
+	var bf bytes.Buffer
+	stop, _ := midi.ListenTo(inC, func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	})
+	defer stop()
+
+	r := New()
+	defer r.Close()
+
+	r.Add(Route{In: inA, Out: outC})
+	r.Add(Route{In: inB, Out: outC, ChannelMap: map[uint8]uint8{0: 1}})
+
+	outA.Open()
+	outB.Open()
+	outA.Send(midi.ProgramChange(0, 3))
+	outB.Send(midi.ProgramChange(0, 4))
+
+	expected := "ProgramChange channel: 0 program: 3\nProgramChange channel: 1 program: 4\n"
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestFeedbackLoop(t *testing.T) {
+	inA, outA := ports("A")
+	inB, outB := ports("B")
+	inC, outC := ports("C")
+
+	r := New()
+	defer r.Close()
+
+	// the testdrv out ports are connected to their in ports
+	r.Loopback(outA, inA)
+	r.Loopback(outB, inB)
+	r.Loopback(outC, inC)
+
+	if _, err := r.Add(Route{In: inA, Out: outA}); !errors.Is(err, ErrFeedbackLoop) {
+		t.Errorf("expected ErrFeedbackLoop, got %v", err)
+	}
+
+	if _, err := r.Add(Route{In: inA, Out: outB}); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if _, err := r.Add(Route{In: inB, Out: outC}); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if _, err := r.Add(Route{In: inC, Out: outA}); !errors.Is(err, ErrFeedbackLoop) {
+		t.Errorf("expected ErrFeedbackLoop, got %v", err)
+	}
+
+	if len(r.Routes()) != 2 {
+		t.Errorf("expected 2 routes, got %v", len(r.Routes()))
+	}
+}
+
+// failingIn is an in port that fails to listen, when it is released
+type failingIn struct {
+	drivers.In
+	release chan struct{}
+}
+
+func (f *failingIn) Listen(onMsg func(msg []byte, milliseconds int32), config drivers.ListenConfig) (func(), error) {
+	<-f.release
+	return nil, errors.New("listening failed")
+}
+
+func TestAddFailingListening(t *testing.T) {
+	inA, _ := ports("A")
+	_, outB := ports("B")
+	_, outC := ports("C")
+
+	in := &failingIn{In: inA, release: make(chan struct{})}
+	r := New()
+
+	errs := make(chan error, 2)
+
+	add := func(out drivers.Out, routes int) {
+		go func() {
+			_, err := r.Add(Route{In: in, Out: out})
+			errs <- err
+		}()
+
+		// wait until the route is registered
+		for len(r.Routes()) < routes {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	add(outB, 1)
+	add(outC, 2)
+	close(in.release)
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err == nil {
+			t.Errorf("expected an error")
+		}
+	}
+
+	if len(r.Routes()) != 0 {
+		t.Errorf("expected no routes, got %v", len(r.Routes()))
+	}
+}
+
+func TestRemoveSharedNotes(t *testing.T) {
+	inA, outA := ports("A")
+	inB, outB := ports("B")
+	inC, outC := ports("C")
+
+	var bf bytes.Buffer
+	stop, _ := midi.ListenTo(inC, func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	})
+	defer stop()
+
+	r := New()
+	defer r.Close()
+
+	idA, _ := r.Add(Route{In: inA, Out: outC})
+	r.Add(Route{In: inB, Out: outC})
+
+	outA.Open()
+	outB.Open()
+	outA.Send(midi.ControlChange(0, midi.HoldPedalSwitch, 127))
+	outB.Send(midi.ControlChange(0, midi.HoldPedalSwitch, 127))
+	outA.Send(midi.NoteOn(0, 60, 100))
+	outB.Send(midi.NoteOn(0, 60, 100))
+	outA.Send(midi.NoteOn(0, 62, 100))
+	bf.Reset()
+
+	if err := r.Remove(idA); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	// the note 60 and the hold pedal are still held via the route from B
+	expected := "NoteOff channel: 0 key: 62\n"
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
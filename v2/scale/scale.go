@@ -0,0 +1,217 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package scale provides musical scales and modes based on midi.Note and midi.Interval.
+
+A Scale is a root note and a Mode, i.e. the intervals of the scale degrees from the root, e.g.
+
+	s := scale.New(midi.Note(midi.D(5)), scale.Dorian)
+	s.Contains(midi.Note(midi.F(5)))  // true
+	s.Snap(midi.Note(midi.Gb(5)))     // F5
+	s.Step(midi.Note(midi.D(5)), 2)   // F5
+
+Only the pitch class of the root is relevant, so scales are the same in every octave.
+*/
+package scale
+
+import (
+	"errors"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+// Mode is a list of ascending intervals from the root note of a scale within an octave, starting with midi.Unison.
+type Mode []midi.Interval
+
+var (
+	Major      = Mode{0, 2, 4, 5, 7, 9, 11}
+	Ionian     = Major
+	Dorian     = Mode{0, 2, 3, 5, 7, 9, 10}
+	Phrygian   = Mode{0, 1, 3, 5, 7, 8, 10}
+	Lydian     = Mode{0, 2, 4, 6, 7, 9, 11}
+	Mixolydian = Mode{0, 2, 4, 5, 7, 9, 10}
+	Aeolian    = Mode{0, 2, 3, 5, 7, 8, 10}
+	Locrian    = Mode{0, 1, 3, 5, 6, 8, 10}
+
+	NaturalMinor  = Aeolian
+	HarmonicMinor = Mode{0, 2, 3, 5, 7, 8, 11}
+
+	// MelodicMinor is the ascending melodic minor scale
+	MelodicMinor = Mode{0, 2, 3, 5, 7, 9, 11}
+
+	MajorPentatonic = Mode{0, 2, 4, 7, 9}
+	MinorPentatonic = Mode{0, 3, 5, 7, 10}
+	Blues           = Mode{0, 3, 5, 6, 7, 10}
+	WholeTone       = Mode{0, 2, 4, 6, 8, 10}
+	Chromatic       = Mode{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+)
+
+// ErrEmptyMode is returned, if a custom mode has no intervals
+var ErrEmptyMode = errors.New("empty mode")
+
+// Custom returns a user defined mode of the given intervals. The intervals are reduced to an octave, sorted and
+// duplicates are removed. The root (midi.Unison) is always part of the mode.
+func Custom(intervals ...midi.Interval) (Mode, error) {
+	if len(intervals) == 0 {
+		return nil, ErrEmptyMode
+	}
+
+	var has [12]bool
+	has[0] = true
+
+	for _, iv := range intervals {
+		has[(int(iv)%12+12)%12] = true
+	}
+
+	var m Mode
+	for i, h := range has {
+		if h {
+			m = append(m, midi.Interval(i))
+		}
+	}
+	return m, nil
+}
+
+// Rotate returns the mode that starts at the given degree (1-based) of m, e.g. Major.Rotate(2) is Dorian.
+func (m Mode) Rotate(degree int) Mode {
+	if len(m) == 0 {
+		return nil
+	}
+
+	idx := ((degree-1)%len(m) + len(m)) % len(m)
+	start := m[idx]
+
+	res := make(Mode, len(m))
+	for i := range m {
+		res[i] = (m[(idx+i)%len(m)] - start + 12) % 12
+	}
+	return res
+}
+
+// Scale is a mode that starts at a root note.
+type Scale struct {
+	// Root is the root note. Only its pitch class is relevant.
+	Root midi.Note
+
+	// Mode are the intervals of the scale degrees.
+	Mode Mode
+}
+
+// New returns the scale of the given mode starting at the given root.
+func New(root midi.Note, mode Mode) Scale {
+	return Scale{Root: root, Mode: mode}
+}
+
+// PitchClasses returns the pitch classes (0 = C - 11 = B) of the scale degrees.
+func (s Scale) PitchClasses() []uint8 {
+	res := make([]uint8, len(s.Mode))
+	for i, iv := range s.Mode {
+		res[i] = uint8((int(s.Root.Base()) + int(iv)) % 12)
+	}
+	return res
+}
+
+// Degree returns the degree (1-based) of the given note within the scale and true, if the note is part of the scale.
+// Otherwise false is returned.
+func (s Scale) Degree(n midi.Note) (degree int, ok bool) {
+	for i, pc := range s.PitchClasses() {
+		if pc == n.Base() {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Contains returns true, if the given note is part of the scale.
+func (s Scale) Contains(n midi.Note) bool {
+	_, ok := s.Degree(n)
+	return ok
+}
+
+// Notes returns the notes of the scale from the note from to the note to (both inclusive).
+func (s Scale) Notes(from, to midi.Note) (notes []midi.Note) {
+	if to > 127 {
+		to = 127
+	}
+
+	for n := int(from); n <= int(to); n++ {
+		if s.Contains(midi.Note(n)) {
+			notes = append(notes, midi.Note(n))
+		}
+	}
+	return
+}
+
+// Snap returns the note of the scale that is nearest to the given note.
+// If there are two nearest notes, the lower one is returned.
+func (s Scale) Snap(n midi.Note) midi.Note {
+	if len(s.Mode) == 0 {
+		return n
+	}
+
+	for dist := 0; dist < 12; dist++ {
+		if down := int(n) - dist; down >= 0 && s.Contains(midi.Note(down)) {
+			return midi.Note(down)
+		}
+
+		if up := int(n) + dist; up <= 127 && s.Contains(midi.Note(up)) {
+			return midi.Note(up)
+		}
+	}
+
+	return n
+}
+
+// Note returns the note of the given degree (1-based) in the octave of the root (as given by Root).
+// Degrees above the number of scale degrees and below 1 continue in the next and previous octaves,
+// e.g. the degree 8 of a major scale is the root one octave higher.
+// The note is clipped to the range 0-127.
+func (s Scale) Note(degree int) midi.Note {
+	if len(s.Mode) == 0 {
+		return s.Root
+	}
+	return clip(s.note(int(s.Root), degree))
+}
+
+func (s Scale) note(root int, degree int) int {
+	idx := degree - 1
+	oct := idx / len(s.Mode)
+	idx %= len(s.Mode)
+
+	if idx < 0 {
+		idx += len(s.Mode)
+		oct--
+	}
+
+	return root + oct*12 + int(s.Mode[idx])
+}
+
+// Step moves the given note by the given number of scale degrees (negative steps move downwards).
+// A note that is not part of the scale is snapped first.
+// The note is clipped to the range 0-127.
+func (s Scale) Step(n midi.Note, steps int) midi.Note {
+	if len(s.Mode) == 0 {
+		return n
+	}
+
+	n = s.Snap(n)
+	degree, _ := s.Degree(n)
+
+	// the root in the octave below or at n
+	root := int(n) - int(s.Mode[degree-1])
+
+	return clip(s.note(root, degree+steps))
+}
+
+func clip(n int) midi.Note {
+	switch {
+	case n < 0:
+		return 0
+	case n > 127:
+		return 127
+	default:
+		return midi.Note(n)
+	}
+}
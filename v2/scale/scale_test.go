@@ -0,0 +1,90 @@
+package scale
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestModes(t *testing.T) {
+	tests := []struct {
+		mode     Mode
+		degree   int
+		expected Mode
+	}{
+		{Major, 2, Dorian},
+		{Major, 3, Phrygian},
+		{Major, 4, Lydian},
+		{Major, 5, Mixolydian},
+		{Major, 6, Aeolian},
+		{Major, 7, Locrian},
+		{Major, 8, Ionian},
+		{Major, 0, Locrian},
+		{MajorPentatonic, 5, MinorPentatonic},
+	}
+
+	for _, test := range tests {
+		if got := test.mode.Rotate(test.degree); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%v.Rotate(%v) = %v; want %v", test.mode, test.degree, got, test.expected)
+		}
+	}
+
+	m, err := Custom(7, 3, 15, -2, 12)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if got, want := m, (Mode{0, 3, 7, 10}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Custom() = %v; want %v", got, want)
+	}
+
+	if _, err := Custom(); err != ErrEmptyMode {
+		t.Errorf("expected ErrEmptyMode, got %v", err)
+	}
+}
+
+func TestScale(t *testing.T) {
+	d := New(midi.Note(midi.D(5)), Dorian)
+
+	if got, want := d.PitchClasses(), []uint8{2, 4, 5, 7, 9, 11, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PitchClasses() = %v; want %v", got, want)
+	}
+
+	if got, want := fmt.Sprint(d.Notes(midi.Note(midi.C(5)), midi.Note(midi.G(5)))), "[C5 D5 E5 F5 G5]"; got != want {
+		t.Errorf("Notes() = %s; want %s", got, want)
+	}
+
+	if !d.Contains(midi.Note(midi.F(2))) || d.Contains(midi.Note(midi.Gb(5))) {
+		t.Errorf("Contains() is wrong")
+	}
+
+	if deg, ok := d.Degree(midi.Note(midi.C(1))); !ok || deg != 7 {
+		t.Errorf("Degree(C1) = %v, %v; want 7, true", deg, ok)
+	}
+
+	tests := []struct {
+		fn       func() midi.Note
+		descr    string
+		expected string
+	}{
+		{func() midi.Note { return d.Snap(midi.Note(midi.Gb(5))) }, "Snap(Gb5)", "F5"},
+		{func() midi.Note { return d.Snap(midi.Note(midi.G(5))) }, "Snap(G5)", "G5"},
+		{func() midi.Note { return New(0, MajorPentatonic).Snap(midi.Note(midi.B(5))) }, "Snap(B5)", "C6"},
+		{func() midi.Note { return d.Note(1) }, "Note(1)", "D5"},
+		{func() midi.Note { return d.Note(8) }, "Note(8)", "D6"},
+		{func() midi.Note { return d.Note(0) }, "Note(0)", "C5"},
+		{func() midi.Note { return d.Note(-6) }, "Note(-6)", "D4"},
+		{func() midi.Note { return d.Step(midi.Note(midi.D(5)), 2) }, "Step(D5, 2)", "F5"},
+		{func() midi.Note { return d.Step(midi.Note(midi.C(6)), 1) }, "Step(C6, 1)", "D6"},
+		{func() midi.Note { return d.Step(midi.Note(midi.E(5)), -3) }, "Step(E5, -3)", "B4"},
+		{func() midi.Note { return d.Step(midi.Note(midi.G(10)), 7) }, "Step(G10, 7)", "G10"},
+	}
+
+	for _, test := range tests {
+		if got := test.fn().String(); got != test.expected {
+			t.Errorf("%s = %s; want %s", test.descr, got, test.expected)
+		}
+	}
+}
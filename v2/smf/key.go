@@ -1,5 +1,9 @@
 package smf
 
+import (
+	"gitlab.com/gomidi/midi/v2"
+)
+
 type Key struct {
 	Key     uint8
 	Num     uint8
@@ -11,6 +15,20 @@ func (k Key) String() string {
 	return keyStrings[k]
 }
 
+// SharpsOrFlats returns the number of sharps (> 0) or flats (< 0) of the key signature.
+func (k Key) SharpsOrFlats() int8 {
+	if k.IsFlat {
+		return -int8(k.Num)
+	}
+	return int8(k.Num)
+}
+
+// Spell returns the name of the given note, spelled according to the key (e.g. F# in D major and Gb in Db major).
+// The octave convention can be set via the midi.MiddleC option.
+func (k Key) Spell(n midi.Note, opts ...midi.NoteOption) string {
+	return n.Spell(append(opts, midi.KeySignature(k.SharpsOrFlats()))...)
+}
+
 var keyStrings = map[Key]string{}
 
 func key(key, num uint8, isMajor, isFlat bool) Message {
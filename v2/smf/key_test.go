@@ -70,3 +70,28 @@ func TestKeys(t *testing.T) {
 	}
 
 }
+
+func TestKeySpell(t *testing.T) {
+	tests := []struct {
+		key      Message
+		note     midi.Note
+		expected string
+	}{
+		{DMaj(), 66, "F#5"},
+		{DbMaj(), 66, "Gb5"},
+		{FsharpMaj(), 65, "E#5"},
+		{AMin(), 68, "Ab5"},
+		{EMin(), 63, "D#5"},
+	}
+
+	for _, test := range tests {
+		var k Key
+		if !test.key.GetMetaKey(&k) {
+			t.Fatalf("%s is no key", test.key)
+		}
+
+		if got := k.Spell(test.note); got != test.expected {
+			t.Errorf("%s.Spell(%v) = %q; want %q", k, uint8(test.note), got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package smf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/internal/utils"
+)
+
+// ParseMessage parses a string, as it is returned by Message.String, back to the message.
+// In addition to the messages supported by midi.Parse, meta messages are supported (except MetaUndefined).
+// For MetaTimeSig messages, the fields clocksperclick and demisemiquaverperquarter may follow the meter
+// (they are not part of the string). Otherwise the defaults of MetaMeter are used.
+func ParseMessage(s string) (Message, error) {
+	name, fields, err := utils.ParseFields(s)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q: %w", s, err)
+	}
+
+	if !strings.HasPrefix(name, "Meta") {
+		msg, err := midi.Parse(s)
+		return Message(msg), err
+	}
+
+	msg, err := parseMeta(name, fields)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse %q: %w", s, err)
+	}
+	return msg, nil
+}
+
+func parseMeta(name string, f utils.Fields) (Message, error) {
+	var typ midi.Type
+	for t, n := range msgTypeString {
+		if n == name {
+			typ = t
+			break
+		}
+	}
+
+	switch typ {
+	case MetaEndOfTrackMsg:
+		return EOT, nil
+	case MetaTempoMsg:
+		bpm, err := strconv.ParseFloat(f["bpm"], 64)
+		if err != nil || bpm <= 0 {
+			return nil, fmt.Errorf("invalid \"bpm\": %q", f["bpm"])
+		}
+		return MetaTempo(bpm), nil
+	case MetaTimeSigMsg:
+		var num, denom uint8
+		if _, err := fmt.Sscanf(f["meter"], "%d/%d", &num, &denom); err != nil {
+			return nil, fmt.Errorf("invalid \"meter\": %q", f["meter"])
+		}
+		if _, has := f["clocksperclick"]; !has {
+			return MetaMeter(num, denom), nil
+		}
+		cpc, err := f.Uint("clocksperclick", 255)
+		if err != nil {
+			return nil, err
+		}
+		dsqpq, err := f.Uint("demisemiquaverperquarter", 255)
+		if err != nil {
+			return nil, err
+		}
+		return MetaTimeSig(num, denom, uint8(cpc), uint8(dsqpq)), nil
+	case MetaKeySigMsg:
+		for k, str := range keyStrings {
+			if str == f["key"] {
+				return MetaKey(k.Key, k.IsMajor, k.Num, k.IsFlat), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid \"key\": %q", f["key"])
+	case MetaChannelMsg:
+		v, err := f.Uint("channel", 15)
+		return MetaChannel(uint8(v)), err
+	case MetaPortMsg:
+		v, err := f.Uint("port", 255)
+		return MetaPort(uint8(v)), err
+	case MetaSeqNumberMsg:
+		v, err := f.Uint("number", 0xFFFF)
+		return MetaSequenceNo(uint16(v)), err
+	case MetaSeqDataMsg:
+		bt, err := f.Bytes("bytes")
+		return MetaSequencerData(bt), err
+	case MetaSMPTEOffsetMsg:
+		var vals [5]uint8
+		for i, label := range []string{"hour", "minute", "second", "frame", "fractframe"} {
+			v, err := f.Uint(label, 255)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = uint8(v)
+		}
+		return MetaSMPTE(vals[0], vals[1], vals[2], vals[3], vals[4]), nil
+	}
+
+	text, has := f["text"]
+	if !has {
+		return nil, fmt.Errorf("unsupported message type %q", name)
+	}
+
+	switch typ {
+	case MetaLyricMsg:
+		return MetaLyric(text), nil
+	case MetaMarkerMsg:
+		return MetaMarker(text), nil
+	case MetaCopyrightMsg:
+		return MetaCopyright(text), nil
+	case MetaTextMsg:
+		return MetaText(text), nil
+	case MetaCuepointMsg:
+		return MetaCuepoint(text), nil
+	case MetaDeviceMsg:
+		return MetaDevice(text), nil
+	case MetaInstrumentMsg:
+		return MetaInstrument(text), nil
+	case MetaProgramNameMsg:
+		return MetaProgram(text), nil
+	case MetaTrackNameMsg:
+		return MetaTrackSequenceName(text), nil
+	default:
+		return nil, fmt.Errorf("unsupported message type %q", name)
+	}
+}
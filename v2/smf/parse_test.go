@@ -0,0 +1,40 @@
+package smf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []Message{
+		MetaTempo(120),
+		MetaMeter(3, 4),
+		CMaj(),
+		AMin(),
+		MetaChannel(3),
+		MetaPort(2),
+		MetaSequenceNo(300),
+		MetaSequencerData([]byte{1, 2}),
+		MetaSMPTE(1, 2, 3, 4, 5),
+		MetaLyric(`a "quoted" lyric: la`),
+		MetaTrackSequenceName("piano"),
+		EOT,
+		Message([]byte{0x90, 60, 100}),
+	}
+
+	for _, msg := range tests {
+		got, err := ParseMessage(msg.String())
+		if err != nil {
+			t.Errorf("ParseMessage(%q) returned error: %s", msg.String(), err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, msg) {
+			t.Errorf("ParseMessage(%q) = % X; want % X", msg.String(), got, msg)
+		}
+	}
+
+	if _, err := ParseMessage("MetaKeySig key: HMaj"); err == nil {
+		t.Errorf("expected error for invalid key")
+	}
+}
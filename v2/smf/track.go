@@ -53,6 +53,9 @@ func (t *Track) Add(deltaticks uint32, msgs ...[]byte) {
 	}
 }
 
+// RecordFrom records the messages that are received on the given port to the track, until the returned stop function is called.
+// The delta ticks are calculated from the high resolution timestamps of the messages (see midi.ListenTimestamp),
+// based on the given resolution and tempo.
 func (t *Track) RecordFrom(inPort drivers.In, ticks MetricTicks, bpm float64) (stop func(), err error) {
 	if !inPort.IsOpen() {
 		err := inPort.Open()
@@ -61,11 +64,12 @@ func (t *Track) RecordFrom(inPort drivers.In, ticks MetricTicks, bpm float64) (s
 		}
 	}
 	t.Add(0, MetaTempo(bpm))
-	var absmillisec int32
-	return midi.ListenTo(inPort, func(msg midi.Message, absms int32) {
-		deltams := absms - absmillisec
-		absmillisec = absms
-		delta := ticks.Ticks(bpm, time.Duration(deltams)*time.Millisecond)
+	var absticks uint32
+	return midi.ListenTimestamp(inPort, func(msg midi.Message, ts midi.Timestamp) {
+		// the delta is calculated from the absolute position, so that the rounding errors don't add up
+		abs := ticks.Ticks(bpm, ts.Since)
+		delta := abs - absticks
+		absticks = abs
 		t.Add(delta, msg)
 	})
 }
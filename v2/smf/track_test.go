@@ -0,0 +1,50 @@
+package smf_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func TestRecordFrom(t *testing.T) {
+	drv := testdrv.New("record-from")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+	outs[0].Open()
+
+	var tr smf.Track
+
+	// with 960 ticks per quarter note at 120 BPM a tick is 520.8333µs
+	stop, err := tr.RecordFrom(ins[0], smf.MetricTicks(960), 120)
+
+	if err != nil {
+		t.Fatalf("ERROR: %s", err.Error())
+	}
+
+	for i := 0; i < 3; i++ {
+		drv.Sleep(1300 * time.Microsecond)
+		outs[0].Send(midi.NoteOn(1, 60, 100))
+	}
+	stop()
+
+	var bf strings.Builder
+	for _, ev := range tr {
+		fmt.Fprintf(&bf, "[%v] %s\n", ev.Delta, ev.Message)
+	}
+
+	// 1.3ms = 2.496 ticks, 2.6ms = 4.992 ticks, 3.9ms = 7.488 ticks
+	want := `[0] MetaTempo bpm: 120.00
+[2] NoteOn channel: 1 key: 60 velocity: 100
+[3] NoteOn channel: 1 key: 60 velocity: 100
+[2] NoteOn channel: 1 key: 60 velocity: 100
+`
+
+	if got := bf.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
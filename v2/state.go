@@ -0,0 +1,386 @@
+package midi
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// ChannelState is the state of a channel, as far as it can be derived from the messages that were sent to it:
+// the program, the controller values, the pitchbend, the channel pressure and the values of the RPNs and NRPNs.
+// The bank is tracked as the controllers BankSelectMSB and BankSelectLSB.
+// A ChannelState can be serialized via encoding/json.
+// A ChannelState is not threadsafe.
+type ChannelState struct {
+	Channel uint8 `json:"channel"`
+
+	// Program is the current program, or -1 if it is unknown.
+	Program int8 `json:"program"`
+
+	// Controllers are the values of the controllers that have been set (the data entry and parameter number
+	// controllers and the channel mode messages are not part of it).
+	Controllers map[uint8]uint8 `json:"controllers,omitempty"`
+
+	// Pitchbend is the relative pitchbend value.
+	Pitchbend int16 `json:"pitchbend"`
+
+	// Pressure is the channel pressure (aftertouch).
+	Pressure uint8 `json:"pressure"`
+
+	// RPN are the 14-bit values of the registered parameters that have been set (key: MSB<<7 | LSB of the parameter).
+	RPN map[uint16]uint16 `json:"rpn,omitempty"`
+
+	// NRPN are the 14-bit values of the non registered parameters that have been set (key: MSB<<7 | LSB of the parameter).
+	NRPN map[uint16]uint16 `json:"nrpn,omitempty"`
+
+	// the currently selected parameter for data entry
+	params parameterSelection
+}
+
+// NewChannelState returns the state of the given channel with an unknown program and no controllers set.
+func NewChannelState(channel uint8) *ChannelState {
+	return &ChannelState{Channel: channel & 0x0F, Program: -1}
+}
+
+// Clone returns a copy of the state that does not share its maps.
+func (s *ChannelState) Clone() *ChannelState {
+	c := *s
+	c.Controllers = copyMap8(s.Controllers)
+	c.RPN = copyMap16(s.RPN)
+	c.NRPN = copyMap16(s.NRPN)
+	return &c
+}
+
+func copyMap8(m map[uint8]uint8) map[uint8]uint8 {
+	if m == nil {
+		return nil
+	}
+	res := make(map[uint8]uint8, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+func copyMap16(m map[uint16]uint16) map[uint16]uint16 {
+	if m == nil {
+		return nil
+	}
+	res := make(map[uint16]uint16, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// Track updates the state with the given message. Messages for other channels are ignored.
+func (s *ChannelState) Track(msg Message) {
+	var ch, val, ctl uint8
+	var rel int16
+
+	if !msg.GetChannel(&ch) || ch != s.Channel {
+		return
+	}
+
+	switch {
+	case msg.GetProgramChange(nil, &val):
+		s.Program = int8(val)
+	case msg.GetAfterTouch(nil, &val):
+		s.Pressure = val
+	case msg.GetPitchBend(nil, &rel, nil):
+		s.Pitchbend = rel
+	case msg.GetControlChange(nil, &ctl, &val):
+		s.trackCC(ctl, val)
+	}
+}
+
+func (s *ChannelState) trackCC(ctl, val uint8) {
+	switch ctl {
+	case RegisteredParameterMSB, RegisteredParameterLSB, NonRegisteredParameterMSB, NonRegisteredParameterLSB,
+		DataEntryMSB, DataEntryLSB, DataButtonIncrement, DataButtonDecrement:
+		ev, ok := s.params.control(s.Channel, ctl, val, func(nrpn bool, param uint16) uint16 {
+			if nrpn {
+				return s.NRPN[param]
+			}
+			return s.RPN[param]
+		})
+
+		if ok && ev.Change != ParameterReset {
+			s.setParam(ev)
+		}
+	case AllControllersOff:
+		s.resetControllers()
+	default:
+		// channel mode messages
+		if ctl >= AllSoundOff {
+			return
+		}
+		if s.Controllers == nil {
+			s.Controllers = map[uint8]uint8{}
+		}
+		s.Controllers[ctl] = val
+	}
+}
+
+func (s *ChannelState) setParam(ev ParameterEvent) {
+	if ev.NRPN {
+		if s.NRPN == nil {
+			s.NRPN = map[uint16]uint16{}
+		}
+		s.NRPN[ev.Parameter] = ev.Value
+		return
+	}
+
+	if s.RPN == nil {
+		s.RPN = map[uint16]uint16{}
+	}
+	s.RPN[ev.Parameter] = ev.Value
+}
+
+// resetControllers follows the recommended practice RP-015 for AllControllersOff (121):
+// bank, volume, pan, the sound controllers (70-79) and the effects levels (91-95) are kept,
+// the other controllers, the pitchbend, the pressure and the parameter selection are reset.
+// The reset controllers are removed from the state, since their values are the defaults after AllControllersOff
+// (the expression is 127, the others are 0). The values of the RPNs and NRPNs are kept.
+func (s *ChannelState) resetControllers() {
+	for ctl := range s.Controllers {
+		switch {
+		case ctl == BankSelectMSB, ctl == BankSelectLSB, ctl == VolumeMSB, ctl == VolumeLSB,
+			ctl == PanPositionMSB, ctl == PanPositionLSB:
+		case ctl >= SoundVariation && ctl <= SoundControl10:
+		case ctl >= EffectsLevel && ctl <= PhaserLevel:
+		default:
+			delete(s.Controllers, ctl)
+		}
+	}
+
+	s.Pitchbend = 0
+	s.Pressure = 0
+	s.params.reset()
+}
+
+// Messages returns the messages that recreate the state: the messages of ResetChannel (without bank select and
+// program change, if the program is unknown), followed by the controllers in ascending order, the RPNs, the NRPNs,
+// the pitchbend and the channel pressure, as far as they differ from the state after ResetChannel.
+// Each RPN and NRPN is followed by a null parameter, so that the parameter selection is not part of the state.
+func (s *ChannelState) Messages() []Message {
+	msgs := s.resetMessages()
+
+	reset := NewChannelState(s.Channel)
+	for _, msg := range msgs {
+		reset.Track(msg)
+	}
+
+	return append(msgs, reset.changes(s)...)
+}
+
+// resetMessages returns the messages of ResetChannel for the bank and program of the state.
+// The bank select LSB is added, if it is set.
+func (s *ChannelState) resetMessages() (msgs []Message) {
+	for _, msg := range ResetChannel(s.Channel, s.Controllers[BankSelectMSB], uint8(s.Program)) {
+		var ctl uint8
+		isBank := msg.GetControlChange(nil, &ctl, nil) && ctl == BankSelectMSB
+
+		if s.Program < 0 && (isBank || msg.Is(ProgramChangeMsg)) {
+			continue
+		}
+
+		if val, has := s.Controllers[BankSelectLSB]; has && s.Program >= 0 && msg.Is(ProgramChangeMsg) {
+			msgs = append(msgs, ControlChange(s.Channel, BankSelectLSB, val))
+		}
+
+		msgs = append(msgs, msg)
+	}
+	return
+}
+
+func (s *ChannelState) programMessages() (msgs []Message) {
+	if s.Program < 0 {
+		return nil
+	}
+
+	if val, has := s.Controllers[BankSelectMSB]; has {
+		msgs = append(msgs, ControlChange(s.Channel, BankSelectMSB, val))
+	}
+
+	if val, has := s.Controllers[BankSelectLSB]; has {
+		msgs = append(msgs, ControlChange(s.Channel, BankSelectLSB, val))
+	}
+
+	return append(msgs, ProgramChange(s.Channel, uint8(s.Program)))
+}
+
+func (s *ChannelState) paramMessages(nrpn bool, param, val uint16) []Message {
+	msb, lsb := RegisteredParameterMSB, RegisteredParameterLSB
+	if nrpn {
+		msb, lsb = NonRegisteredParameterMSB, NonRegisteredParameterLSB
+	}
+
+	return []Message{
+		ControlChange(s.Channel, msb, uint8(param>>7)),
+		ControlChange(s.Channel, lsb, uint8(param&0x7F)),
+		ControlChange(s.Channel, DataEntryMSB, uint8(val>>7)),
+		ControlChange(s.Channel, DataEntryLSB, uint8(val&0x7F)),
+		ControlChange(s.Channel, msb, 127),
+		ControlChange(s.Channel, lsb, 127),
+	}
+}
+
+// Diff returns the messages that change the state s into the given target state (of the same channel).
+// If s has controllers or parameters that are not set in the target, they can't be unset,
+// so the result is the complete target.Messages() in this case.
+func (s *ChannelState) Diff(target *ChannelState) (msgs []Message) {
+	for ctl := range s.Controllers {
+		if _, has := target.Controllers[ctl]; !has {
+			return target.Messages()
+		}
+	}
+
+	for param := range s.RPN {
+		if _, has := target.RPN[param]; !has {
+			return target.Messages()
+		}
+	}
+
+	for param := range s.NRPN {
+		if _, has := target.NRPN[param]; !has {
+			return target.Messages()
+		}
+	}
+
+	if s.Program != target.Program ||
+		s.Controllers[BankSelectMSB] != target.Controllers[BankSelectMSB] ||
+		s.Controllers[BankSelectLSB] != target.Controllers[BankSelectLSB] {
+		msgs = append(msgs, target.programMessages()...)
+	}
+
+	return append(msgs, s.changes(target)...)
+}
+
+// changes returns the messages for the controllers (except the bank), parameters, pitchbend and pressure
+// of the target that differ from s
+func (s *ChannelState) changes(target *ChannelState) (msgs []Message) {
+	for _, ctl := range sortedKeys8(target.Controllers) {
+		if ctl == BankSelectMSB || ctl == BankSelectLSB {
+			continue
+		}
+
+		if val, has := s.Controllers[ctl]; !has || val != target.Controllers[ctl] {
+			msgs = append(msgs, ControlChange(target.Channel, ctl, target.Controllers[ctl]))
+		}
+	}
+
+	for _, param := range sortedKeys16(target.RPN) {
+		if val, has := s.RPN[param]; !has || val != target.RPN[param] {
+			msgs = append(msgs, target.paramMessages(false, param, target.RPN[param])...)
+		}
+	}
+
+	for _, param := range sortedKeys16(target.NRPN) {
+		if val, has := s.NRPN[param]; !has || val != target.NRPN[param] {
+			msgs = append(msgs, target.paramMessages(true, param, target.NRPN[param])...)
+		}
+	}
+
+	if s.Pitchbend != target.Pitchbend {
+		msgs = append(msgs, Pitchbend(target.Channel, target.Pitchbend))
+	}
+
+	if s.Pressure != target.Pressure {
+		msgs = append(msgs, AfterTouch(target.Channel, target.Pressure))
+	}
+
+	return
+}
+
+func sortedKeys8(m map[uint8]uint8) []uint8 {
+	keys := make([]uint8, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a] < keys[b] })
+	return keys
+}
+
+func sortedKeys16(m map[uint16]uint16) []uint16 {
+	keys := make([]uint16, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool { return keys[a] < keys[b] })
+	return keys
+}
+
+// DeviceState is the state of all 16 channels of a device.
+// A DeviceState can be serialized via encoding/json.
+// A DeviceState is not threadsafe.
+type DeviceState struct {
+	Channels [16]*ChannelState `json:"channels"`
+}
+
+// NewDeviceState returns a new DeviceState with the initial states of all channels.
+func NewDeviceState() *DeviceState {
+	var d DeviceState
+	for ch := uint8(0); ch < 16; ch++ {
+		d.Channels[ch] = NewChannelState(ch)
+	}
+	return &d
+}
+
+// Track updates the state with the given message. A Reset message resets the state of every channel.
+// SMF meta messages are ignored.
+func (d *DeviceState) Track(msg Message) {
+	var ch uint8
+
+	switch {
+	case msg.GetChannel(&ch):
+		d.Channels[ch].Track(msg)
+	case isReset(msg):
+		*d = *NewDeviceState()
+	}
+}
+
+// Clone returns a copy of the state (a snapshot).
+func (d *DeviceState) Clone() *DeviceState {
+	var c DeviceState
+	for ch, s := range d.Channels {
+		c.Channels[ch] = s.Clone()
+	}
+	return &c
+}
+
+// Messages returns the messages that recreate the state of every channel (see ChannelState.Messages).
+func (d *DeviceState) Messages() (msgs []Message) {
+	for _, s := range d.Channels {
+		msgs = append(msgs, s.Messages()...)
+	}
+	return
+}
+
+// Diff returns the messages that change the state d into the given target state (see ChannelState.Diff).
+func (d *DeviceState) Diff(target *DeviceState) (msgs []Message) {
+	for ch, s := range d.Channels {
+		msgs = append(msgs, s.Diff(target.Channels[ch])...)
+	}
+	return
+}
+
+// UnmarshalJSON restores the state from the JSON encoding.
+func (d *DeviceState) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Channels []*ChannelState `json:"channels"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*d = *NewDeviceState()
+
+	for _, s := range v.Channels {
+		if s != nil {
+			d.Channels[s.Channel&0x0F] = s
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package midi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func msgsString(msgs []midi.Message) string {
+	var bf bytes.Buffer
+	for _, msg := range msgs {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	}
+	return bf.String()
+}
+
+// resetString is the string of midi.ResetChannel for channel 1 without bank select and program change
+const resetString = "ControlChange channel: 1 controller: 121 value: 0\n" +
+	"ControlChange channel: 1 controller: 7 value: 100\n" +
+	"ControlChange channel: 1 controller: 11 value: 127\n" +
+	"ControlChange channel: 1 controller: 64 value: 0\n" +
+	"ControlChange channel: 1 controller: 10 value: 64\n"
+
+func TestChannelState(t *testing.T) {
+	tests := []struct {
+		descr    string
+		msgs     []midi.Message
+		expected string
+	}{
+		{
+			"empty",
+			nil,
+			resetString,
+		},
+		{
+			"program and bank",
+			[]midi.Message{
+				midi.ControlChange(1, midi.BankSelectMSB, 2),
+				midi.ProgramChange(1, 5),
+				midi.ProgramChange(0, 7),
+			},
+			"ControlChange channel: 1 controller: 0 value: 2\n" +
+				"ProgramChange channel: 1 program: 5\n" +
+				resetString,
+		},
+		{
+			"controllers",
+			[]midi.Message{
+				midi.ControlChange(1, midi.VolumeMSB, 100),
+				midi.ControlChange(1, midi.ModulationWheelMSB, 20),
+				midi.ControlChange(1, midi.AllNotesOff, 0),
+				midi.ControlChange(1, midi.VolumeMSB, 90),
+			},
+			resetString +
+				"ControlChange channel: 1 controller: 1 value: 20\n" +
+				"ControlChange channel: 1 controller: 7 value: 90\n",
+		},
+		{
+			"all controllers off",
+			[]midi.Message{
+				midi.ControlChange(1, midi.VolumeMSB, 100),
+				midi.ControlChange(1, midi.ModulationWheelMSB, 20),
+				midi.ControlChange(1, midi.ExpressionMSB, 80),
+				midi.ControlChange(1, midi.HoldPedalSwitch, 127),
+				midi.ControlChange(1, midi.SoundBrightness, 30),
+				midi.ControlChange(1, midi.ChorusLevel, 40),
+				midi.Pitchbend(1, 300),
+				midi.ControlChange(1, midi.AllControllersOff, 0),
+			},
+			resetString +
+				"ControlChange channel: 1 controller: 74 value: 30\n" +
+				"ControlChange channel: 1 controller: 93 value: 40\n",
+		},
+		{
+			"pitchbend and pressure",
+			[]midi.Message{midi.Pitchbend(1, -200), midi.AfterTouch(1, 30)},
+			resetString +
+				"PitchBend channel: 1 pitch: -200 (7992)\n" +
+				"AfterTouch channel: 1 pressure: 30\n",
+		},
+		{
+			"rpn",
+			[]midi.Message{
+				midi.ControlChange(1, midi.RegisteredParameterMSB, 0),
+				midi.ControlChange(1, midi.RegisteredParameterLSB, 0),
+				midi.ControlChange(1, midi.DataEntryMSB, 12),
+				midi.ControlChange(1, midi.DataButtonIncrement, 0),
+				midi.ControlChange(1, midi.RegisteredParameterMSB, 127),
+				midi.ControlChange(1, midi.RegisteredParameterLSB, 127),
+				midi.ControlChange(1, midi.DataEntryMSB, 3),
+			},
+			resetString +
+				"ControlChange channel: 1 controller: 101 value: 0\n" +
+				"ControlChange channel: 1 controller: 100 value: 0\n" +
+				"ControlChange channel: 1 controller: 6 value: 12\n" +
+				"ControlChange channel: 1 controller: 38 value: 1\n" +
+				"ControlChange channel: 1 controller: 101 value: 127\n" +
+				"ControlChange channel: 1 controller: 100 value: 127\n",
+		},
+		{
+			"nrpn",
+			[]midi.Message{
+				midi.ControlChange(1, midi.NonRegisteredParameterMSB, 1),
+				midi.ControlChange(1, midi.NonRegisteredParameterLSB, 2),
+				midi.ControlChange(1, midi.DataEntryMSB, 3),
+				midi.ControlChange(1, midi.DataEntryLSB, 4),
+			},
+			resetString +
+				"ControlChange channel: 1 controller: 99 value: 1\n" +
+				"ControlChange channel: 1 controller: 98 value: 2\n" +
+				"ControlChange channel: 1 controller: 6 value: 3\n" +
+				"ControlChange channel: 1 controller: 38 value: 4\n" +
+				"ControlChange channel: 1 controller: 99 value: 127\n" +
+				"ControlChange channel: 1 controller: 98 value: 127\n",
+		},
+	}
+
+	for _, test := range tests {
+		s := midi.NewChannelState(1)
+
+		for _, msg := range test.msgs {
+			s.Track(msg)
+		}
+
+		msgs := s.Messages()
+
+		if got, want := msgsString(msgs), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+
+		// recreation
+		r := midi.NewChannelState(1)
+		for _, msg := range msgs {
+			r.Track(msg)
+		}
+
+		if got, want := msgsString(r.Messages()), test.expected; got != want {
+			t.Errorf("[%s] recreated\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+	}
+}
+
+func TestChannelStateDiff(t *testing.T) {
+	a := midi.NewChannelState(0)
+	a.Track(midi.ProgramChange(0, 3))
+	a.Track(midi.ControlChange(0, midi.VolumeMSB, 100))
+	a.Track(midi.ControlChange(0, midi.PanPositionMSB, 64))
+
+	b := a.Clone()
+	b.Track(midi.ControlChange(0, midi.PanPositionMSB, 20))
+	b.Track(midi.ControlChange(0, midi.ExpressionMSB, 127))
+
+	expected := "ControlChange channel: 0 controller: 10 value: 20\n" +
+		"ControlChange channel: 0 controller: 11 value: 127\n"
+
+	if got := msgsString(a.Diff(b)); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+
+	if got, want := msgsString(b.Diff(a)), msgsString(a.Messages()); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := a.Diff(a.Clone()); len(got) != 0 {
+		t.Errorf("expected no difference, got %v", got)
+	}
+}
+
+func TestDeviceStateJSON(t *testing.T) {
+	d := midi.NewDeviceState()
+	d.Track(midi.ProgramChange(3, 10))
+	d.Track(midi.ControlChange(3, midi.VolumeMSB, 80))
+	d.Track(midi.ControlChange(9, midi.RegisteredParameterMSB, 0))
+	d.Track(midi.ControlChange(9, midi.RegisteredParameterLSB, 0))
+	d.Track(midi.ControlChange(9, midi.DataEntryMSB, 2))
+
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	var r midi.DeviceState
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if got, want := r.Messages(), d.Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", msgsString(got), msgsString(want))
+	}
+
+	// meta messages of SMF tracks are not reset messages
+	before := d.Messages()
+	d.Track(midi.Message(smf.MetaTempo(120)))
+
+	if got := d.Messages(); !reflect.DeepEqual(got, before) {
+		t.Errorf("state changed by meta message:\n%s", msgsString(got))
+	}
+
+	d.Track(midi.Reset())
+
+	if got, want := d.Messages(), midi.NewDeviceState().Messages(); !reflect.DeepEqual(got, want) {
+		t.Errorf("state not reset:\n%s", msgsString(got))
+	}
+}
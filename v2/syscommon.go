@@ -8,6 +8,8 @@ MIDI Timing Code            F1                   1
 Song Position Pointer       F2                   2
 Song Select                 F3                   1
 Tune Request                F6                  None
+(undefined)                 F4                 (undefined)
+(undefined)                 F5                 (undefined)
 
 */
 
@@ -16,6 +18,8 @@ const (
 	byteSysSongPositionPointer = byte(0xF2)
 	byteSysSongSelect          = byte(0xF3)
 	byteSysTuneRequest         = byte(0xF6)
+	byteUndefinedF4            = byte(0xF4)
+	byteUndefinedF5            = byte(0xF5)
 )
 
 var syscommMessages = map[byte]Type{
@@ -23,6 +27,8 @@ var syscommMessages = map[byte]Type{
 	byteSysSongPositionPointer:/* SysCommonMsg.Set(SPPMsg), */ SPPMsg,
 	byteSysSongSelect:/* SysCommonMsg.Set(SongSelectMsg), */ SongSelectMsg,
 	byteSysTuneRequest:/* SysCommonMsg.Set(TuneMsg), */ TuneMsg,
+	byteUndefinedF4: reservedSysCommonMsg5,
+	byteUndefinedF5: reservedSysCommonMsg6,
 }
 
 // Tune returns a tune message
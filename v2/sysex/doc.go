@@ -2,5 +2,16 @@
 // Use of this source code is governed by a MIT
 // license that can be found in the LICENSE file.
 
-// Package sysex provides helpers when dealing with system exclusiv messages
+/*
+Package sysex provides helpers when dealing with system exclusiv messages.
+
+The messages of the MIDI Tuning Standard (MTS) can be created and parsed (see ParseTuning) and the tunings
+can be imported from Scala .scl and .kbm files (see ReadScale and ReadKeyboardMapping).
+
+Large dumps can be sent to slow devices with a Sender that splits the messages into chunks (if the out port implements
+drivers.RawOut, otherwise the complete messages are paced), pauses between them and waits for the handshake replies of the device:
+
+	s := sysex.NewSender(out, sysex.ChunkSize(128), sysex.ChunkDelay(20*time.Millisecond))
+	err := s.Send(dump...)
+*/
 package sysex
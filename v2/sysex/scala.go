@@ -0,0 +1,276 @@
+package sysex
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// see https://www.huygens-fokker.org/scala/scl_format.html
+// and https://www.huygens-fokker.org/scala/help.htm#mappings
+
+// Scale is a tuning scale of a Scala .scl file.
+type Scale struct {
+	Description string
+
+	// Degrees are the pitches of the scale degrees above the root (that is not included) in cents.
+	// The last degree is the period of the scale, usually the octave (1200 cents).
+	Degrees []float64
+}
+
+// ReadScale reads a Scala .scl file.
+func ReadScale(r io.Reader) (*Scale, error) {
+	lines, err := scalaLines(r, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("invalid scl file: missing description or number of notes")
+	}
+
+	var s Scale
+	s.Description = strings.TrimSpace(lines[0])
+
+	num, err := strconv.Atoi(firstField(lines[1]))
+	if err != nil || num < 1 {
+		return nil, fmt.Errorf("invalid scl file: invalid number of notes %q", lines[1])
+	}
+
+	if len(lines)-2 < num {
+		return nil, fmt.Errorf("invalid scl file: %v notes expected, got %v", num, len(lines)-2)
+	}
+
+	for _, l := range lines[2 : 2+num] {
+		c, err := parsePitch(firstField(l))
+		if err != nil {
+			return nil, fmt.Errorf("invalid scl file: %w", err)
+		}
+		s.Degrees = append(s.Degrees, c)
+	}
+
+	return &s, nil
+}
+
+// parsePitch parses a pitch of a scl file: cents if it contains a period, a ratio otherwise
+func parsePitch(s string) (float64, error) {
+	if strings.Contains(s, ".") {
+		c, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid cents %q", s)
+		}
+		return c, nil
+	}
+
+	num, denom := s, "1"
+	if i := strings.Index(s, "/"); i >= 0 {
+		num, denom = s[:i], s[i+1:]
+	}
+
+	n, err1 := strconv.ParseUint(num, 10, 64)
+	d, err2 := strconv.ParseUint(denom, 10, 64)
+	if err1 != nil || err2 != nil || n == 0 || d == 0 {
+		return 0, fmt.Errorf("invalid ratio %q", s)
+	}
+
+	return 1200 * math.Log2(float64(n)/float64(d)), nil
+}
+
+// scalaLines returns the lines that are no comments. If keepFirst is set, the first line is returned even if it is empty
+func scalaLines(r io.Reader, keepFirst bool) (lines []string, err error) {
+	sc := bufio.NewScanner(r)
+
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if strings.HasPrefix(l, "!") {
+			continue
+		}
+		if strings.TrimSpace(l) == "" && !(keepFirst && len(lines) == 0) {
+			continue
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, sc.Err()
+}
+
+func firstField(s string) string {
+	f := strings.Fields(s)
+	if len(f) == 0 {
+		return ""
+	}
+	return f[0]
+}
+
+// pitch returns the cents of the given scale degree above the root (degree 0)
+func (s *Scale) pitch(degree int) float64 {
+	num := len(s.Degrees)
+	period := s.Degrees[num-1]
+
+	oct := degree / num
+	deg := degree % num
+	if deg < 0 {
+		deg += num
+		oct--
+	}
+
+	if deg == 0 {
+		return float64(oct) * period
+	}
+	return float64(oct)*period + s.Degrees[deg-1]
+}
+
+// KeyboardMapping is the mapping of the keys to the scale degrees of a Scala .kbm file.
+type KeyboardMapping struct {
+	// Size is the number of keys of the repeating pattern; 0 maps the keys linearly to the scale degrees.
+	Size int
+
+	// First and Last are the range of the keys that are retuned.
+	First, Last uint8
+
+	// Middle is the key that is mapped to the root of the scale.
+	Middle uint8
+
+	// Reference is the key that has the Frequency.
+	Reference uint8
+	Frequency float64
+
+	// OctaveDegree is the scale degree of the formal octave, i.e. the degree that is reached after Size keys.
+	OctaveDegree int
+
+	// Mapping are the scale degrees of the keys of the pattern, starting at Middle. -1 means that the key is not mapped.
+	Mapping []int
+}
+
+// DefaultKeyboardMapping maps all keys linearly to the scale degrees, with the root at 60 and 69 = 440 Hz.
+var DefaultKeyboardMapping = KeyboardMapping{First: 0, Last: 127, Middle: 60, Reference: 69, Frequency: 440}
+
+// ReadKeyboardMapping reads a Scala .kbm file.
+func ReadKeyboardMapping(r io.Reader) (*KeyboardMapping, error) {
+	lines, err := scalaLines(r, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) < 7 {
+		return nil, fmt.Errorf("invalid kbm file: %v lines expected, got %v", 7, len(lines))
+	}
+
+	var ints [7]int
+	for i, l := range lines[:7] {
+		if i == 5 {
+			continue
+		}
+		ints[i], err = strconv.Atoi(firstField(l))
+		if err != nil || ints[i] < 0 || (i >= 1 && i <= 4 && ints[i] > 127) {
+			return nil, fmt.Errorf("invalid kbm file: invalid value %q in line %v", l, i+1)
+		}
+	}
+
+	var m KeyboardMapping
+	m.Size = ints[0]
+	m.First, m.Last, m.Middle, m.Reference = uint8(ints[1]), uint8(ints[2]), uint8(ints[3]), uint8(ints[4])
+	m.OctaveDegree = ints[6]
+
+	m.Frequency, err = strconv.ParseFloat(firstField(lines[5]), 64)
+	if err != nil || m.Frequency <= 0 {
+		return nil, fmt.Errorf("invalid kbm file: invalid frequency %q", lines[5])
+	}
+
+	// missing entries at the end are unmapped
+	for i := 0; i < m.Size; i++ {
+		deg := -1
+		if 7+i < len(lines) {
+			if f := firstField(lines[7+i]); f != "x" {
+				deg, err = strconv.Atoi(f)
+				if err != nil || deg < 0 {
+					return nil, fmt.Errorf("invalid kbm file: invalid mapping %q", lines[7+i])
+				}
+			}
+		}
+		m.Mapping = append(m.Mapping, deg)
+	}
+
+	return &m, nil
+}
+
+// degree returns the scale degree of the key and false, if the key is not mapped
+func (m *KeyboardMapping) degree(key int, scaleSize int) (int, bool) {
+	i := key - int(m.Middle)
+
+	if m.Size == 0 {
+		return i, true
+	}
+
+	oct := i / m.Size
+	idx := i % m.Size
+	if idx < 0 {
+		idx += m.Size
+		oct--
+	}
+
+	deg := m.Mapping[idx]
+	if deg < 0 {
+		return 0, false
+	}
+
+	octDeg := m.OctaveDegree
+	if octDeg == 0 {
+		octDeg = scaleSize
+	}
+
+	return oct*octDeg + deg, true
+}
+
+// Tunings returns the tunings of the keys for the given keyboard mapping (nil for the DefaultKeyboardMapping),
+// e.g. for the BulkTuningDump. Keys that are not mapped or outside the range of the mapping get NoTuningChange.
+func (s *Scale) Tunings(m *KeyboardMapping) (t [128]NoteTuning, err error) {
+	if m == nil {
+		m = &DefaultKeyboardMapping
+	}
+
+	if len(s.Degrees) == 0 {
+		return t, fmt.Errorf("empty scale")
+	}
+
+	ref, ok := m.degree(int(m.Reference), len(s.Degrees))
+	if !ok {
+		return t, fmt.Errorf("reference key %v is not mapped", m.Reference)
+	}
+
+	refCents := 6900 + 1200*math.Log2(m.Frequency/440)
+
+	for key := range t {
+		t[key] = NoTuningChange
+
+		if key < int(m.First) || key > int(m.Last) {
+			continue
+		}
+
+		deg, ok := m.degree(key, len(s.Degrees))
+		if !ok {
+			continue
+		}
+
+		t[key] = NoteTuningFromCents(refCents + s.pitch(deg) - s.pitch(ref))
+	}
+
+	return t, nil
+}
+
+// OctaveCents returns the offsets of the pitch classes C, Db, D, ..., B from equal temperament for the ScaleOctaveTuning,
+// if the root of the scale is the given pitch class (0 = C). The scale must have 12 degrees and a period of 1200 cents.
+func (s *Scale) OctaveCents(root uint8) (c [12]float64, err error) {
+	if len(s.Degrees) != 12 || math.Abs(s.Degrees[11]-1200) > 0.001 {
+		return c, fmt.Errorf("scale is not a 12 degree scale with an octave period")
+	}
+
+	for i := 1; i < 12; i++ {
+		c[(int(root)+i)%12] = s.Degrees[i-1] - float64(i)*100
+	}
+
+	return c, nil
+}
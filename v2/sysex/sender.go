@@ -0,0 +1,336 @@
+package sysex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+var (
+	// ErrNoReply is the error when the device does not reply to a message in time (see Handshake).
+	ErrNoReply = errors.New("no handshake reply")
+
+	// ErrNAK is the error when the device does not acknowledge a message, even after the retries (see Retries).
+	ErrNAK = errors.New("message not acknowledged")
+
+	// ErrCanceled is the error when the device cancels the transmission.
+	ErrCanceled = errors.New("transmission canceled by the device")
+)
+
+// Reply is the meaning of a message that is received from the device during a handshake (see Handshake).
+type Reply int
+
+const (
+	// NoReply is a message that is not a reply to the sent message. It is ignored.
+	NoReply Reply = iota
+
+	// ACK acknowledges the sent message, so that the next message is sent.
+	ACK
+
+	// NAK rejects the sent message, so that it is sent again (see Retries).
+	NAK
+
+	// Wait lets the sender wait for the next reply, restarting the timeout.
+	Wait
+
+	// Cancel cancels the transmission.
+	Cancel
+)
+
+// SenderOption is an option for a Sender.
+type SenderOption func(*Sender)
+
+// ChunkSize sets the size of the chunks (in bytes) that a message is split into. The default is 0: messages are not split.
+// The chunks are sent via drivers.RawOut, since only the first one starts with 0xF0. If the out port does not implement it
+// (like the ports of most drivers), the messages are sent as a whole, followed by the pauses of their chunks
+// (see ChunkDelay), so that the device gets the same time to process the data.
+func ChunkSize(size int) SenderOption {
+	return func(s *Sender) {
+		s.chunkSize = size
+	}
+}
+
+// ChunkDelay sets the pause between the chunks of a message.
+func ChunkDelay(d time.Duration) SenderOption {
+	return func(s *Sender) {
+		s.chunkDelay = d
+	}
+}
+
+// MessageDelay sets the pause between the messages (after the handshake, if there is one).
+func MessageDelay(d time.Duration) SenderOption {
+	return func(s *Sender) {
+		s.messageDelay = d
+	}
+}
+
+// Handshake lets the Sender wait after each message for a reply of the device on the given in port.
+// The received messages are passed to the reply function that returns their meaning. If there is no reply
+// within the timeout, ErrNoReply is returned. With a timeout of 0, the Sender waits until the context is done.
+func Handshake(in drivers.In, timeout time.Duration, reply func(msg []byte) Reply) SenderOption {
+	return func(s *Sender) {
+		s.in = in
+		s.timeout = timeout
+		s.reply = reply
+	}
+}
+
+// Retries sets how often a message is sent again after a NAK. The default is 3.
+func Retries(n int) SenderOption {
+	return func(s *Sender) {
+		s.retries = n
+	}
+}
+
+// Progress sets a callback that is called after each sent chunk with the number of sent bytes and the total number of bytes.
+func Progress(cb func(sent, total int)) SenderOption {
+	return func(s *Sender) {
+		s.progress = cb
+	}
+}
+
+// Sender sends sysex messages to devices with small input buffers: the messages can be split into chunks that are
+// sent with pauses inbetween (see ChunkSize and ChunkDelay) and the Sender can wait for a handshake of the
+// device after each message (see Handshake).
+// A Sender is not threadsafe.
+type Sender struct {
+	out          drivers.Out
+	chunkSize    int
+	chunkDelay   time.Duration
+	messageDelay time.Duration
+	in           drivers.In
+	timeout      time.Duration
+	reply        func([]byte) Reply
+	retries      int
+	progress     func(sent, total int)
+}
+
+// NewSender returns a Sender for the given out port.
+func NewSender(out drivers.Out, opts ...SenderOption) *Sender {
+	s := &Sender{out: out, retries: 3}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Send sends the given sysex messages (including 0xF0 and 0xF7), e.g. the messages of a dump.
+func (s *Sender) Send(msgs ...[]byte) error {
+	return s.SendContext(context.Background(), msgs...)
+}
+
+// SendContext is like Send, but stops the sending, when the context is done.
+func (s *Sender) SendContext(ctx context.Context, msgs ...[]byte) error {
+	send, chunked := s.out.Send, false
+
+	if raw, ok := s.out.(drivers.RawOut); ok && s.chunkSize > 0 {
+		send, chunked = raw.SendRaw, true
+	}
+
+	if !s.out.IsOpen() {
+		err := s.out.Open()
+		if err != nil {
+			return err
+		}
+	}
+
+	var replies chan []byte
+
+	if s.in != nil {
+		if !s.in.IsOpen() {
+			err := s.in.Open()
+			if err != nil {
+				return err
+			}
+		}
+
+		replies = make(chan []byte, 16)
+
+		stop, err := s.in.Listen(func(msg []byte, milliseconds int32) {
+			select {
+			case replies <- append([]byte(nil), msg...):
+			default:
+			}
+		}, drivers.ListenConfig{SysEx: true})
+
+		if err != nil {
+			return err
+		}
+
+		defer stop()
+	}
+
+	var total, sent int
+	for _, msg := range msgs {
+		total += len(msg)
+	}
+
+	for i, msg := range msgs {
+		if i > 0 {
+			err := sleep(ctx, s.messageDelay)
+			if err != nil {
+				return err
+			}
+		}
+
+		for attempt := 0; ; attempt++ {
+			if replies != nil {
+				drain(replies)
+			}
+
+			var err error
+			if chunked {
+				err = s.sendChunks(ctx, send, msg, sent, total)
+			} else {
+				err = s.sendPaced(ctx, send, msg, sent, total)
+			}
+			if err != nil {
+				return err
+			}
+
+			if replies == nil {
+				break
+			}
+
+			r, err := s.awaitReply(ctx, replies)
+			if err != nil {
+				return fmt.Errorf("message %v: %w", i, err)
+			}
+
+			if r == ACK {
+				break
+			}
+
+			if r == Cancel {
+				return fmt.Errorf("message %v: %w", i, ErrCanceled)
+			}
+
+			// NAK
+			if attempt >= s.retries {
+				return fmt.Errorf("message %v: %w", i, ErrNAK)
+			}
+		}
+
+		sent += len(msg)
+	}
+
+	return nil
+}
+
+// sendChunks sends the message in chunks with the given send function
+func (s *Sender) sendChunks(ctx context.Context, send func([]byte) error, msg []byte, sent, total int) error {
+	size := s.chunkSize
+	if size <= 0 {
+		size = len(msg)
+	}
+
+	for from := 0; from < len(msg); from += size {
+		if from > 0 {
+			err := sleep(ctx, s.chunkDelay)
+			if err != nil {
+				return err
+			}
+		}
+
+		to := from + size
+		if to > len(msg) {
+			to = len(msg)
+		}
+
+		err := send(msg[from:to])
+		if err != nil {
+			return err
+		}
+
+		if s.progress != nil {
+			s.progress(sent+to, total)
+		}
+	}
+
+	return nil
+}
+
+// sendPaced sends the message as a whole, followed by the pauses between its chunks (if there is a chunk size)
+func (s *Sender) sendPaced(ctx context.Context, send func([]byte) error, msg []byte, sent, total int) error {
+	err := send(msg)
+	if err != nil {
+		return err
+	}
+
+	if s.progress != nil {
+		s.progress(sent+len(msg), total)
+	}
+
+	if s.chunkSize <= 0 {
+		return nil
+	}
+
+	chunks := (len(msg) + s.chunkSize - 1) / s.chunkSize
+	return sleep(ctx, time.Duration(chunks-1)*s.chunkDelay)
+}
+
+// awaitReply waits for an ACK, a NAK or a Cancel
+func (s *Sender) awaitReply(ctx context.Context, replies chan []byte) (Reply, error) {
+	var timer *time.Timer
+	var timeout <-chan time.Time
+
+	if s.timeout > 0 {
+		timer = time.NewTimer(s.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return NoReply, ctx.Err()
+		case <-timeout:
+			return NoReply, ErrNoReply
+		case msg := <-replies:
+			switch r := s.reply(msg); r {
+			case NoReply:
+			case Wait:
+				if timer != nil {
+					if !timer.Stop() {
+						<-timer.C
+					}
+					timer.Reset(s.timeout)
+				}
+			default:
+				return r, nil
+			}
+		}
+	}
+}
+
+func drain(c chan []byte) {
+	for {
+		select {
+		case <-c:
+		default:
+			return
+		}
+	}
+}
+
+// sleep pauses for the given duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
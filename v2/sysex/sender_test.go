@@ -0,0 +1,261 @@
+package sysex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+)
+
+// device records the sent data and replies to complete messages via the out port of the test driver
+// (which needs a listener)
+type device struct {
+	drivers.Out
+	log      *strings.Builder
+	replies  [][]byte
+	listened bool
+}
+
+func (d *device) Send(bt []byte) error {
+	return d.SendRaw(bt)
+}
+
+func (d *device) SendRaw(bt []byte) error {
+	fmt.Fprintf(d.log, "% X\n", bt)
+
+	if !d.listened {
+		return nil
+	}
+
+	err := d.Out.(drivers.RawOut).SendRaw(bt)
+	if err != nil {
+		return err
+	}
+
+	if bt[len(bt)-1] == 0xF7 && len(d.replies) > 0 {
+		reply := d.replies[0]
+		d.replies = d.replies[1:]
+		return d.Out.Send(reply)
+	}
+
+	return nil
+}
+
+var (
+	testACK    = []byte{0xF0, 0x7E, 0x00, 0x7F, 0x00, 0xF7}
+	testNAK    = []byte{0xF0, 0x7E, 0x00, 0x7E, 0x00, 0xF7}
+	testWait   = []byte{0xF0, 0x7E, 0x00, 0x7C, 0x00, 0xF7}
+	testCancel = []byte{0xF0, 0x7E, 0x00, 0x7D, 0x00, 0xF7}
+)
+
+func join(msgs ...[]byte) []byte {
+	return bytes.Join(msgs, nil)
+}
+
+func testReply(msg []byte) Reply {
+	switch {
+	case bytes.Equal(msg, testACK):
+		return ACK
+	case bytes.Equal(msg, testNAK):
+		return NAK
+	case bytes.Equal(msg, testWait):
+		return Wait
+	case bytes.Equal(msg, testCancel):
+		return Cancel
+	default:
+		return NoReply
+	}
+}
+
+func TestSender(t *testing.T) {
+	msgs := [][]byte{
+		{0xF0, 0x41, 0x01, 0x02, 0x03, 0x04, 0xF7},
+		{0xF0, 0x41, 0x05, 0xF7},
+	}
+
+	tests := []struct {
+		descr     string
+		handshake bool
+		replies   [][]byte
+		err       error
+		want      string
+	}{
+		{
+			"chunks without handshake",
+			false,
+			nil,
+			nil,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 05
+progress: 10/11
+F7
+progress: 11/11
+`,
+		},
+		{
+			"ACK",
+			true,
+			[][]byte{testACK, join(testWait, testACK)},
+			nil,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 05
+progress: 10/11
+F7
+progress: 11/11
+`,
+		},
+		{
+			"NAK and resend",
+			true,
+			[][]byte{testNAK, testACK, testACK},
+			nil,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 05
+progress: 10/11
+F7
+progress: 11/11
+`,
+		},
+		{
+			"too many NAKs",
+			true,
+			[][]byte{testNAK, testNAK, testNAK},
+			ErrNAK,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+`,
+		},
+		{
+			"cancel",
+			true,
+			[][]byte{testACK, testCancel},
+			ErrCanceled,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+F0 41 05
+progress: 10/11
+F7
+progress: 11/11
+`,
+		},
+		{
+			"no reply",
+			true,
+			nil,
+			ErrNoReply,
+			`F0 41 01
+progress: 3/11
+02 03 04
+progress: 6/11
+F7
+progress: 7/11
+`,
+		},
+	}
+
+	for _, test := range tests {
+		drv := testdrv.New("sysex-sender")
+		ins, _ := drv.Ins()
+		outs, _ := drv.Outs()
+
+		var log strings.Builder
+		dev := &device{Out: outs[0], log: &log, replies: test.replies, listened: test.handshake}
+
+		opts := []SenderOption{
+			ChunkSize(3),
+			ChunkDelay(time.Millisecond),
+			MessageDelay(time.Millisecond),
+			Retries(1),
+			Progress(func(sent, total int) {
+				fmt.Fprintf(&log, "progress: %v/%v\n", sent, total)
+			}),
+		}
+
+		if test.handshake {
+			opts = append(opts, Handshake(ins[0], 20*time.Millisecond, testReply))
+		}
+
+		err := NewSender(dev, opts...).Send(msgs...)
+
+		if !errors.Is(err, test.err) {
+			t.Errorf("[%s] error: %v; want %v", test.descr, err, test.err)
+		}
+
+		if got := log.String(); got != test.want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, test.want)
+		}
+	}
+}
+
+func TestSenderWithoutRawOut(t *testing.T) {
+	drv := testdrv.New("sysex-sender-raw")
+	outs, _ := drv.Outs()
+
+	var log strings.Builder
+
+	// the embedded port hides the SendRaw method of the test driver
+	out := struct{ drivers.Out }{&device{Out: outs[0], log: &log}}
+	msg := []byte{0xF0, 0x41, 0x01, 0x02, 0x03, 0x04, 0xF7}
+
+	// the message is sent as a whole, followed by the pauses of its three chunks
+	start := time.Now()
+	err := NewSender(out, ChunkSize(3), ChunkDelay(10*time.Millisecond)).Send(msg)
+
+	if err != nil {
+		t.Errorf("error: %v; want nil", err)
+	}
+
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("sending took %v; want at least 20ms", d)
+	}
+
+	err = NewSender(out).Send(msg)
+
+	if err != nil {
+		t.Errorf("error: %v; want nil", err)
+	}
+
+	if got, want := log.String(), "F0 41 01 02 03 04 F7\nF0 41 01 02 03 04 F7\n"; got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,362 @@
+package sysex
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+)
+
+/*
+MIDI Tuning Standard (MTS)
+
+All numbers are in hexadecimal notation.
+
+F0 7E dd 08 00 tt F7                        Bulk Tuning Dump Request
+F0 7E dd 08 01 tt name[16] [xx yy zz]*128 cs F7    Bulk Tuning Dump
+F0 7F dd 08 02 tt ll [kk xx yy zz]*ll F7     Single Note Tuning Change (realtime)
+F0 7E dd 08 03 bb tt F7                     Bulk Tuning Dump Request (bank)
+F0 7E dd 08 04 bb tt name[16] [xx yy zz]*128 cs F7 Key-Based Tuning Dump (bank)
+F0 7E/7F dd 08 07 bb tt ll [kk xx yy zz]*ll F7   Single Note Tuning Change (bank)
+F0 7E/7F dd 08 08 ff gg hh [ss]*12 F7        Scale/Octave Tuning, 1-byte form
+F0 7E/7F dd 08 09 ff gg hh [ss tt]*12 F7     Scale/Octave Tuning, 2-byte form
+
+dd = device ID, 7F = all devices
+tt = tuning program, bb = tuning bank
+xx yy zz = frequency: semitone, MSB and LSB of the fraction of a semitone in 1/16384 (7F 7F 7F = no change)
+cs = checksum: XOR of the bytes from 7E to the last data byte
+ff gg hh = channel bitmask: ff = channels 15-14, gg = channels 13-7, hh = channels 6-0
+ss = 1-byte form: cents offset of the pitch class from equal temperament; 00 = -64, 40 = 0, 7F = +63
+ss tt = 2-byte form: MSB and LSB of the offset in 100/8192 cents; 00 00 = -100, 40 00 = 0, 7F 7F = +100
+*/
+
+const tuningSubID = 0x08
+
+var (
+	// ErrNoTuning is returned, if a sysex message is not a supported MIDI Tuning Standard message
+	ErrNoTuning = errors.New("no MIDI tuning message")
+
+	// ErrChecksum is returned, if the checksum of a sysex message is wrong
+	ErrChecksum = errors.New("invalid checksum")
+)
+
+// NoteTuning is the frequency of a key in the MIDI Tuning Standard: the equal tempered semitone (the key with
+// 69 = 440 Hz) and the fraction above it in 1/16384 semitone (0.0061 cents).
+type NoteTuning struct {
+	Semitone uint8
+	Fraction uint16
+}
+
+// NoTuningChange is the NoteTuning that leaves the tuning of a key unchanged.
+var NoTuningChange = NoteTuning{Semitone: 0x7F, Fraction: 0x3FFF}
+
+// NoteTuningFromCents returns the NoteTuning for the given cents above the key 0 (8.18 Hz).
+// The result is clipped to the range of the keys 0 to 127.
+func NoteTuningFromCents(cents float64) NoteTuning {
+	units := math.Round(cents / 100 * 16384)
+
+	if units <= 0 {
+		return NoteTuning{}
+	}
+
+	if units >= 128*16384-2 {
+		// the maximum that is not NoTuningChange
+		return NoteTuning{Semitone: 127, Fraction: 0x3FFE}
+	}
+
+	u := int(units)
+	return NoteTuning{Semitone: uint8(u / 16384), Fraction: uint16(u % 16384)}
+}
+
+// NoteTuningFromFrequency returns the NoteTuning for the given frequency in Hz.
+func NoteTuningFromFrequency(hz float64) NoteTuning {
+	if hz <= 0 {
+		return NoteTuning{}
+	}
+	return NoteTuningFromCents(6900 + 1200*math.Log2(hz/440))
+}
+
+// Cents returns the cents above the key 0.
+func (n NoteTuning) Cents() float64 {
+	return float64(n.Semitone)*100 + float64(n.Fraction)*100/16384
+}
+
+// Frequency returns the frequency in Hz.
+func (n NoteTuning) Frequency() float64 {
+	return 440 * math.Pow(2, (n.Cents()-6900)/1200)
+}
+
+func (n NoteTuning) bytes() []byte {
+	return []byte{n.Semitone & 0x7F, byte(n.Fraction>>7) & 0x7F, byte(n.Fraction) & 0x7F}
+}
+
+func parseNoteTuning(bt []byte) NoteTuning {
+	return NoteTuning{Semitone: bt[0], Fraction: uint16(bt[1])<<7 | uint16(bt[2])}
+}
+
+// Tuning is a MIDI Tuning Standard message.
+type Tuning interface {
+	SysEx() []byte
+}
+
+// BulkTuningDumpRequest requests the bulk tuning dump of a tuning program.
+type BulkTuningDumpRequest struct {
+	DeviceID byte
+	HasBank  bool
+	Bank     byte
+	Program  byte
+}
+
+func (r BulkTuningDumpRequest) SysEx() []byte {
+	if r.HasBank {
+		return []byte{0xF0, 0x7E, r.DeviceID, tuningSubID, 0x03, r.Bank, r.Program, 0xF7}
+	}
+	return []byte{0xF0, 0x7E, r.DeviceID, tuningSubID, 0x00, r.Program, 0xF7}
+}
+
+// BulkTuningDump is the tuning of all keys of a tuning program.
+// If HasBank is true, it is a key-based tuning dump of a tuning program within a bank.
+type BulkTuningDump struct {
+	DeviceID byte
+	HasBank  bool
+	Bank     byte
+	Program  byte
+	Name     string // max. 16 ASCII characters
+	Tunings  [128]NoteTuning
+}
+
+func (d BulkTuningDump) SysEx() []byte {
+	var bf bytes.Buffer
+
+	bf.Write([]byte{0xF0, 0x7E, d.DeviceID, tuningSubID})
+	if d.HasBank {
+		bf.Write([]byte{0x04, d.Bank})
+	} else {
+		bf.WriteByte(0x01)
+	}
+	bf.WriteByte(d.Program)
+
+	var name [16]byte
+	for i := range name {
+		name[i] = ' '
+		if i < len(d.Name) {
+			name[i] = d.Name[i] & 0x7F
+		}
+	}
+	bf.Write(name[:])
+
+	for _, t := range d.Tunings {
+		bf.Write(t.bytes())
+	}
+
+	bf.WriteByte(xorChecksum(bf.Bytes()[1:]))
+	bf.WriteByte(0xF7)
+	return bf.Bytes()
+}
+
+// SingleNoteTuningChange changes the tuning of single keys of a tuning program.
+// Without bank, the message is always realtime (and Realtime is ignored).
+type SingleNoteTuningChange struct {
+	DeviceID byte
+	Realtime bool
+	HasBank  bool
+	Bank     byte
+	Program  byte
+	Changes  []KeyTuning // max. 127
+}
+
+// KeyTuning is the tuning of a key.
+type KeyTuning struct {
+	Key uint8
+	NoteTuning
+}
+
+func (s SingleNoteTuningChange) SysEx() []byte {
+	var bf bytes.Buffer
+
+	bf.WriteByte(0xF0)
+	switch {
+	case s.HasBank && !s.Realtime:
+		bf.WriteByte(0x7E)
+	default:
+		bf.WriteByte(0x7F)
+	}
+	bf.Write([]byte{s.DeviceID, tuningSubID})
+
+	if s.HasBank {
+		bf.Write([]byte{0x07, s.Bank})
+	} else {
+		bf.WriteByte(0x02)
+	}
+	bf.WriteByte(s.Program)
+
+	changes := s.Changes
+	if len(changes) > 127 {
+		changes = changes[:127]
+	}
+
+	bf.WriteByte(byte(len(changes)))
+	for _, c := range changes {
+		bf.WriteByte(c.Key & 0x7F)
+		bf.Write(c.bytes())
+	}
+
+	bf.WriteByte(0xF7)
+	return bf.Bytes()
+}
+
+// ScaleOctaveTuning sets the offsets of the 12 pitch classes from equal temperament for the given channels.
+// The 1-byte form has a range of -64 to +63 cents in steps of 1 cent, the 2-byte form a range of
+// -100 to +100 cents in steps of 0.012 cents.
+type ScaleOctaveTuning struct {
+	DeviceID byte
+	Realtime bool
+	TwoByte  bool
+	Channels uint16      // bitmask: bit 0 is channel 0 and bit 15 is channel 15
+	Cents    [12]float64 // the offsets of C, Db, D, ..., B in cents
+}
+
+func (s ScaleOctaveTuning) SysEx() []byte {
+	var bf bytes.Buffer
+
+	bf.WriteByte(0xF0)
+	if s.Realtime {
+		bf.WriteByte(0x7F)
+	} else {
+		bf.WriteByte(0x7E)
+	}
+	bf.Write([]byte{s.DeviceID, tuningSubID})
+
+	if s.TwoByte {
+		bf.WriteByte(0x09)
+	} else {
+		bf.WriteByte(0x08)
+	}
+
+	bf.Write([]byte{byte(s.Channels>>14) & 0x03, byte(s.Channels>>7) & 0x7F, byte(s.Channels) & 0x7F})
+
+	for _, c := range s.Cents {
+		if s.TwoByte {
+			v := clip(math.Round(c/100*8192)+8192, 0, 0x3FFF)
+			bf.Write([]byte{byte(v >> 7), byte(v) & 0x7F})
+		} else {
+			bf.WriteByte(byte(clip(math.Round(c)+64, 0, 127)))
+		}
+	}
+
+	bf.WriteByte(0xF7)
+	return bf.Bytes()
+}
+
+func clip(v float64, min, max int) int {
+	switch {
+	case v < float64(min):
+		return min
+	case v > float64(max):
+		return max
+	default:
+		return int(v)
+	}
+}
+
+func xorChecksum(bt []byte) (sum byte) {
+	for _, b := range bt {
+		sum ^= b
+	}
+	return sum & 0x7F
+}
+
+// ParseTuning parses a MIDI Tuning Standard message. The result is one of *BulkTuningDumpRequest, *BulkTuningDump,
+// *SingleNoteTuningChange or *ScaleOctaveTuning. ErrNoTuning is returned for other messages.
+func ParseTuning(bt []byte) (Tuning, error) {
+	if len(bt) < 6 || bt[0] != 0xF0 || (bt[1] != 0x7E && bt[1] != 0x7F) || bt[3] != tuningSubID {
+		return nil, ErrNoTuning
+	}
+
+	if bt[len(bt)-1] != 0xF7 {
+		return nil, fmt.Errorf("missing end byte 0xF7")
+	}
+
+	realtime := bt[1] == 0x7F
+	dev := bt[2]
+	data := bt[5 : len(bt)-1]
+
+	tooShort := fmt.Errorf("tuning message too short: % X", bt)
+
+	switch sub := bt[4]; {
+	case (sub == 0x00 || sub == 0x03) && !realtime:
+		r := &BulkTuningDumpRequest{DeviceID: dev, HasBank: sub == 0x03}
+		if r.HasBank {
+			if len(data) != 2 {
+				return nil, tooShort
+			}
+			r.Bank, data = data[0], data[1:]
+		}
+		if len(data) != 1 {
+			return nil, tooShort
+		}
+		r.Program = data[0]
+		return r, nil
+
+	case (sub == 0x01 || sub == 0x04) && !realtime:
+		d := &BulkTuningDump{DeviceID: dev, HasBank: sub == 0x04}
+		if d.HasBank {
+			if len(data) == 0 {
+				return nil, tooShort
+			}
+			d.Bank, data = data[0], data[1:]
+		}
+		if len(data) != 1+16+128*3+1 {
+			return nil, tooShort
+		}
+		if xorChecksum(bt[1:len(bt)-2]) != bt[len(bt)-2] {
+			return nil, ErrChecksum
+		}
+		d.Program = data[0]
+		d.Name = string(bytes.TrimRight(data[1:17], " \x00"))
+		for i := range d.Tunings {
+			d.Tunings[i] = parseNoteTuning(data[17+i*3:])
+		}
+		return d, nil
+
+	case (sub == 0x02 && realtime) || sub == 0x07:
+		s := &SingleNoteTuningChange{DeviceID: dev, Realtime: realtime, HasBank: sub == 0x07}
+		if s.HasBank {
+			if len(data) == 0 {
+				return nil, tooShort
+			}
+			s.Bank, data = data[0], data[1:]
+		}
+		if len(data) < 2 || len(data[2:]) < int(data[1])*4 {
+			return nil, tooShort
+		}
+		s.Program = data[0]
+		for i := 0; i < int(data[1]); i++ {
+			kt := data[2+i*4:]
+			s.Changes = append(s.Changes, KeyTuning{Key: kt[0], NoteTuning: parseNoteTuning(kt[1:])})
+		}
+		return s, nil
+
+	case sub == 0x08 || sub == 0x09:
+		s := &ScaleOctaveTuning{DeviceID: dev, Realtime: realtime, TwoByte: sub == 0x09}
+		size := 12
+		if s.TwoByte {
+			size = 24
+		}
+		if len(data) != 3+size {
+			return nil, tooShort
+		}
+		s.Channels = uint16(data[0]&0x03)<<14 | uint16(data[1])<<7 | uint16(data[2])
+		for i := range s.Cents {
+			if s.TwoByte {
+				s.Cents[i] = float64(int(data[3+i*2])<<7|int(data[4+i*2])-8192) * 100 / 8192
+			} else {
+				s.Cents[i] = float64(int(data[3+i]) - 64)
+			}
+		}
+		return s, nil
+
+	default:
+		return nil, ErrNoTuning
+	}
+}
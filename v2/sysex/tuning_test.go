@@ -0,0 +1,236 @@
+package sysex
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNoteTuning(t *testing.T) {
+	tests := []struct {
+		hz   float64
+		want NoteTuning
+	}{
+		{440, NoteTuning{69, 0}},
+		{8.175798915643707, NoteTuning{0, 0}},
+		{261.6255653005986, NoteTuning{60, 0}},
+		{440 * math.Pow(2, 0.5/12), NoteTuning{69, 8192}},
+		{1, NoteTuning{0, 0}},
+		{100000, NoteTuning{127, 0x3FFE}},
+	}
+
+	for _, test := range tests {
+		got := NoteTuningFromFrequency(test.hz)
+		if got != test.want {
+			t.Errorf("NoteTuningFromFrequency(%v) = %v; want %v", test.hz, got, test.want)
+		}
+	}
+
+	if got := (NoteTuning{69, 8192}).Cents(); got != 6950 {
+		t.Errorf("Cents() = %v; want 6950", got)
+	}
+
+	if got := (NoteTuning{69, 0}).Frequency(); got != 440 {
+		t.Errorf("Frequency() = %v; want 440", got)
+	}
+}
+
+func TestTuningMessages(t *testing.T) {
+	var dump BulkTuningDump
+	dump.DeviceID = 0x7F
+	dump.Program = 3
+	dump.Name = "just"
+	for i := range dump.Tunings {
+		dump.Tunings[i] = NoteTuning{uint8(i), 100}
+	}
+
+	bankDump := dump
+	bankDump.HasBank = true
+	bankDump.Bank = 2
+
+	tests := []struct {
+		msg   Tuning
+		bytes string
+	}{
+		{
+			BulkTuningDumpRequest{DeviceID: 0x10, Program: 5},
+			"F0 7E 10 08 00 05 F7",
+		},
+		{
+			BulkTuningDumpRequest{DeviceID: 0x10, HasBank: true, Bank: 1, Program: 5},
+			"F0 7E 10 08 03 01 05 F7",
+		},
+		{
+			SingleNoteTuningChange{DeviceID: 0x7F, Realtime: true, Program: 0, Changes: []KeyTuning{{69, NoteTuning{69, 8192}}, {70, NoTuningChange}}},
+			"F0 7F 7F 08 02 00 02 45 45 40 00 46 7F 7F 7F F7",
+		},
+		{
+			SingleNoteTuningChange{DeviceID: 0, HasBank: true, Bank: 1, Program: 2, Changes: []KeyTuning{{60, NoteTuning{60, 1}}}},
+			"F0 7E 00 08 07 01 02 01 3C 3C 00 01 F7",
+		},
+		{
+			SingleNoteTuningChange{DeviceID: 0, Realtime: true, HasBank: true, Bank: 1, Program: 2, Changes: []KeyTuning{{60, NoteTuning{60, 1}}}},
+			"F0 7F 00 08 07 01 02 01 3C 3C 00 01 F7",
+		},
+		{
+			ScaleOctaveTuning{DeviceID: 0x7F, Channels: 0xFFFF, Cents: [12]float64{0, -10, 0, 0, -14, 0, 0, 0, 0, 0, 0, 63}},
+			"F0 7E 7F 08 08 03 7F 7F 40 36 40 40 32 40 40 40 40 40 40 7F F7",
+		},
+		{
+			ScaleOctaveTuning{DeviceID: 0x7F, Realtime: true, TwoByte: true, Channels: 0x0081, Cents: [12]float64{0, -100, 50}},
+			"F0 7F 7F 08 09 00 01 01 40 00 00 00 60 00 40 00 40 00 40 00 40 00 40 00 40 00 40 00 40 00 40 00 F7",
+		},
+	}
+
+	for _, test := range tests {
+		bt := test.msg.SysEx()
+
+		if got, want := fmt.Sprintf("% X", bt), test.bytes; got != want {
+			t.Errorf("%#v.SysEx()\ngot:\n%s\nwant:\n%s", test.msg, got, want)
+		}
+
+		parsed, err := ParseTuning(bt)
+		if err != nil {
+			t.Errorf("ParseTuning(% X) returned error: %s", bt, err)
+			continue
+		}
+
+		if got := reflect.ValueOf(parsed).Elem().Interface(); !reflect.DeepEqual(got, test.msg) {
+			t.Errorf("ParseTuning(% X) = %#v; want %#v", bt, got, test.msg)
+		}
+	}
+
+	for _, d := range []BulkTuningDump{dump, bankDump} {
+		bt := d.SysEx()
+
+		parsed, err := ParseTuning(bt)
+		if err != nil {
+			t.Fatalf("ParseTuning(BulkTuningDump) returned error: %s", err)
+		}
+
+		if got := *parsed.(*BulkTuningDump); !reflect.DeepEqual(got, d) {
+			t.Errorf("ParseTuning(BulkTuningDump) = %#v; want %#v", got, d)
+		}
+
+		bt[10] ^= 1
+		if _, err := ParseTuning(bt); err != ErrChecksum {
+			t.Errorf("expected ErrChecksum, got %v", err)
+		}
+	}
+
+	if got := len(dump.SysEx()); got != 408 {
+		t.Errorf("len(BulkTuningDump.SysEx()) = %v; want 408", got)
+	}
+
+	if _, err := ParseTuning(GMSystem(0x7F, true)); err != ErrNoTuning {
+		t.Errorf("expected ErrNoTuning, got %v", err)
+	}
+}
+
+var justScl = `! just.scl
+!
+Just intonation
+ 12
+!
+ 16/15
+ 9/8
+ 6/5
+ 5/4
+ 4/3
+ 45/32
+ 3/2
+ 8/5
+ 5/3
+ 9/5
+ 15/8
+ 2/1
+`
+
+func TestScala(t *testing.T) {
+	s, err := ReadScale(strings.NewReader(justScl))
+	if err != nil {
+		t.Fatalf("ReadScale returned error: %s", err)
+	}
+
+	if s.Description != "Just intonation" || len(s.Degrees) != 12 || s.Degrees[11] != 1200 {
+		t.Fatalf("ReadScale = %#v", s)
+	}
+
+	// A = 440 Hz and C = 264 Hz (5/3 below)
+	tunings, err := s.Tunings(nil)
+	if err != nil {
+		t.Fatalf("Tunings returned error: %s", err)
+	}
+
+	if got := tunings[69].Frequency(); math.Abs(got-440) > 0.01 {
+		t.Errorf("frequency of 69 = %v; want 440", got)
+	}
+
+	if got := tunings[60].Frequency(); math.Abs(got-264) > 0.01 {
+		t.Errorf("frequency of 60 = %v; want 264", got)
+	}
+
+	if got := tunings[72].Frequency(); math.Abs(got-528) > 0.01 {
+		t.Errorf("frequency of 72 = %v; want 528", got)
+	}
+
+	cents, err := s.OctaveCents(0)
+	if err != nil {
+		t.Fatalf("OctaveCents returned error: %s", err)
+	}
+
+	if got := math.Round(cents[4]); got != -14 {
+		t.Errorf("offset of E = %v; want -14", got)
+	}
+
+	kbm := `! white keys only
+7
+48
+72
+60
+65
+440.0
+12
+! mapping
+0
+2
+4
+5
+7
+9
+11
+`
+	m, err := ReadKeyboardMapping(strings.NewReader(kbm))
+	if err != nil {
+		t.Fatalf("ReadKeyboardMapping returned error: %s", err)
+	}
+
+	want := KeyboardMapping{Size: 7, First: 48, Last: 72, Middle: 60, Reference: 65, Frequency: 440, OctaveDegree: 12, Mapping: []int{0, 2, 4, 5, 7, 9, 11}}
+	if !reflect.DeepEqual(*m, want) {
+		t.Fatalf("ReadKeyboardMapping = %#v; want %#v", *m, want)
+	}
+
+	tunings, err = s.Tunings(m)
+	if err != nil {
+		t.Fatalf("Tunings returned error: %s", err)
+	}
+
+	// 65 is the A, 67 the C above
+	if got := tunings[65].Frequency(); math.Abs(got-440) > 0.01 {
+		t.Errorf("frequency of 65 = %v; want 440", got)
+	}
+
+	if got := tunings[67].Frequency(); math.Abs(got-528) > 0.01 {
+		t.Errorf("frequency of 67 = %v; want 528", got)
+	}
+
+	if tunings[47] != NoTuningChange || tunings[73] != NoTuningChange {
+		t.Errorf("keys outside of the range must not be changed")
+	}
+
+	if _, err := ReadScale(strings.NewReader("desc\n3\n100.0\n")); err == nil {
+		t.Errorf("expected error for missing notes")
+	}
+}
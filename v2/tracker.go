@@ -0,0 +1,193 @@
+package midi
+
+import (
+	"sync"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// NoteTracker tracks the sounding notes of a message stream (sent or received) per channel, so that exactly
+// these notes can be silenced.
+// A note is sounding, while its key is held or while it is held by the hold pedal (HoldPedalSwitch).
+// AllNotesOff, AllSoundOff and Reset messages clear the affected notes. SMF meta messages are ignored.
+// A NoteTracker is threadsafe.
+type NoteTracker struct {
+	mx        sync.Mutex
+	held      [16][128]bool
+	sustained [16][128]bool
+	pedal     [16]bool
+}
+
+// NewNoteTracker returns a new NoteTracker.
+func NewNoteTracker() *NoteTracker {
+	return &NoteTracker{}
+}
+
+// Track updates the state of the tracker with the given message.
+func (t *NoteTracker) Track(msg Message) {
+	var ch, key, vel, ctl, val uint8
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	switch {
+	case msg.GetNoteStart(&ch, &key, &vel):
+		t.held[ch][key&0x7F] = true
+		t.sustained[ch][key&0x7F] = false
+	case msg.GetNoteEnd(&ch, &key):
+		key &= 0x7F
+		if t.held[ch][key] && t.pedal[ch] {
+			t.sustained[ch][key] = true
+		}
+		t.held[ch][key] = false
+	case msg.GetControlChange(&ch, &ctl, &val):
+		switch ctl {
+		case HoldPedalSwitch:
+			t.pedal[ch] = val >= 64
+			if !t.pedal[ch] {
+				t.sustained[ch] = [128]bool{}
+			}
+		case AllNotesOff, AllSoundOff:
+			t.clear(ch)
+		}
+	case isReset(msg):
+		for c := uint8(0); c < 16; c++ {
+			t.clear(c)
+		}
+	}
+}
+
+func (t *NoteTracker) clear(ch uint8) {
+	t.held[ch] = [128]bool{}
+	t.sustained[ch] = [128]bool{}
+	t.pedal[ch] = false
+}
+
+// IsHeld returns true, if the key is held on the given channel.
+func (t *NoteTracker) IsHeld(channel, key uint8) bool {
+	if channel > 15 || key > 127 {
+		return false
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return t.held[channel][key]
+}
+
+// IsSounding returns true, if the key is held or held by the hold pedal on the given channel.
+func (t *NoteTracker) IsSounding(channel, key uint8) bool {
+	if channel > 15 || key > 127 {
+		return false
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return t.held[channel][key] || t.sustained[channel][key]
+}
+
+// IsPedalPressed returns true, if the hold pedal is pressed on the given channel.
+func (t *NoteTracker) IsPedalPressed(channel uint8) bool {
+	if channel > 15 {
+		return false
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	return t.pedal[channel]
+}
+
+// Sounding returns the sounding keys of the given channel.
+func (t *NoteTracker) Sounding(channel uint8) (keys []uint8) {
+	if channel > 15 {
+		return nil
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	for key := 0; key < 128; key++ {
+		if t.held[channel][key] || t.sustained[channel][key] {
+			keys = append(keys, uint8(key))
+		}
+	}
+	return
+}
+
+// SilenceChannel returns the messages that silence exactly the sounding notes on the given channel.
+// If the channel is -1, every channel is affected. If the hold pedal is pressed, it is released first.
+// The state of the tracker is cleared for the affected channels.
+// If channel is > 15, the method panics.
+func (t *NoteTracker) SilenceChannel(ch int8) (out []Message) {
+	if ch > 15 {
+		panic("invalid channel number")
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	// single channel
+	if ch >= 0 {
+		return t.silence(uint8(ch))
+	}
+
+	// all channels
+	for c := uint8(0); c < 16; c++ {
+		out = append(out, t.silence(c)...)
+	}
+	return
+}
+
+func (t *NoteTracker) silence(ch uint8) (out []Message) {
+	if t.pedal[ch] {
+		out = append(out, ControlChange(ch, HoldPedalSwitch, Off))
+	}
+
+	for key := uint8(0); key < 128; key++ {
+		if t.held[ch][key] || t.sustained[ch][key] {
+			out = append(out, NoteOff(ch, key))
+		}
+	}
+
+	t.clear(ch)
+	return
+}
+
+// TrackingOut is an out port that tracks the sounding notes of the sent messages, so that they can be silenced.
+type TrackingOut struct {
+	drivers.Out
+	*NoteTracker
+}
+
+// NewTrackingOut wraps the given out port, so that the sent messages are tracked.
+func NewTrackingOut(out drivers.Out) *TrackingOut {
+	return &TrackingOut{Out: out, NoteTracker: NewNoteTracker()}
+}
+
+// Send sends the given message and tracks it. The message must be complete (no running status).
+func (o *TrackingOut) Send(data []byte) error {
+	err := o.Out.Send(data)
+	if err == nil {
+		o.Track(data)
+	}
+	return err
+}
+
+// Silence sends note off messages for the sounding notes on the given channel (-1 for every channel).
+// It does not return on the first error, but tries everything instead to make it silent
+// and returns the last error.
+func (o *TrackingOut) Silence(ch int8) (err error) {
+	for _, msg := range o.SilenceChannel(ch) {
+		if e := o.Out.Send(msg); e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+// Close silences every channel and closes the port.
+func (o *TrackingOut) Close() error {
+	if o.Out.IsOpen() {
+		o.Silence(-1)
+	}
+	return o.Out.Close()
+}
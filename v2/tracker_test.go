@@ -0,0 +1,139 @@
+package midi_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers/testdrv"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+func TestNoteTracker(t *testing.T) {
+	tests := []struct {
+		descr    string
+		msgs     []midi.Message
+		ch       int8
+		expected string
+	}{
+		{
+			"held notes",
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(0, 62, 100), midi.NoteOn(1, 64, 100), midi.NoteOff(0, 60)},
+			-1,
+			"NoteOff channel: 0 key: 62\nNoteOff channel: 1 key: 64\n",
+		},
+		{
+			"single channel",
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(1, 64, 100)},
+			1,
+			"NoteOff channel: 1 key: 64\n",
+		},
+		{
+			"note on with velocity 0",
+			[]midi.Message{midi.NoteOn(0, 60, 100), midi.NoteOn(0, 60, 0)},
+			-1,
+			"",
+		},
+		{
+			"sustained notes",
+			[]midi.Message{midi.ControlChange(2, midi.HoldPedalSwitch, midi.On), midi.NoteOn(2, 60, 100), midi.NoteOff(2, 60)},
+			-1,
+			"ControlChange channel: 2 controller: 64 value: 0\nNoteOff channel: 2 key: 60\n",
+		},
+		{
+			"pedal released",
+			[]midi.Message{midi.ControlChange(2, midi.HoldPedalSwitch, midi.On), midi.NoteOn(2, 60, 100), midi.NoteOff(2, 60), midi.ControlChange(2, midi.HoldPedalSwitch, midi.Off)},
+			-1,
+			"",
+		},
+		{
+			"all notes off",
+			[]midi.Message{midi.NoteOn(3, 60, 100), midi.NoteOn(4, 60, 100), midi.ControlChange(3, midi.AllNotesOff, 0)},
+			-1,
+			"NoteOff channel: 4 key: 60\n",
+		},
+		{
+			"reset",
+			[]midi.Message{midi.NoteOn(3, 60, 100), midi.NoteOn(4, 60, 100), midi.Reset()},
+			-1,
+			"",
+		},
+		{
+			"meta messages",
+			[]midi.Message{midi.NoteOn(3, 60, 100), midi.Message(smf.MetaLyric("la")), midi.Message(smf.MetaTempo(100))},
+			-1,
+			"NoteOff channel: 3 key: 60\n",
+		},
+	}
+
+	for _, test := range tests {
+		tr := midi.NewNoteTracker()
+
+		for _, msg := range test.msgs {
+			tr.Track(msg)
+		}
+
+		var bf bytes.Buffer
+		for _, msg := range tr.SilenceChannel(test.ch) {
+			fmt.Fprintf(&bf, "%s\n", msg)
+		}
+
+		if got, want := bf.String(), test.expected; got != want {
+			t.Errorf("[%s]\ngot:\n%s\nwant:\n%s", test.descr, got, want)
+		}
+
+		if got := tr.SilenceChannel(test.ch); len(got) != 0 {
+			t.Errorf("[%s] state not cleared: %v", test.descr, got)
+		}
+	}
+}
+
+func TestNoteTrackerSounding(t *testing.T) {
+	tr := midi.NewNoteTracker()
+	tr.Track(midi.ControlChange(0, midi.HoldPedalSwitch, 127))
+	tr.Track(midi.NoteOn(0, 60, 100))
+	tr.Track(midi.NoteOn(0, 64, 100))
+	tr.Track(midi.NoteOff(0, 60))
+
+	if tr.IsHeld(0, 60) {
+		t.Errorf("key 60 should not be held")
+	}
+
+	if !tr.IsSounding(0, 60) {
+		t.Errorf("key 60 should be sounding")
+	}
+
+	if got, want := fmt.Sprint(tr.Sounding(0)), "[60 64]"; got != want {
+		t.Errorf("Sounding(0) = %s; want %s", got, want)
+	}
+}
+
+func TestTrackingOut(t *testing.T) {
+	drv := testdrv.New("tracker")
+	ins, _ := drv.Ins()
+	outs, _ := drv.Outs()
+
+	var bf bytes.Buffer
+	stop, _ := midi.ListenTo(ins[0], func(msg midi.Message, timestampms int32) {
+		fmt.Fprintf(&bf, "%s\n", msg)
+	})
+	defer stop()
+
+	out := midi.NewTrackingOut(outs[0])
+	out.Open()
+	out.Send(midi.NoteOn(0, 60, 100))
+	out.Send(midi.NoteOn(0, 62, 100))
+	out.Send(midi.NoteOff(0, 60))
+	out.Close()
+
+	expected := `NoteOn channel: 0 key: 60 velocity: 100
+NoteOn channel: 0 key: 62 velocity: 100
+NoteOff channel: 0 key: 60
+NoteOff channel: 0 key: 62
+`
+
+	if got := bf.String(); got != expected {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, expected)
+	}
+}
@@ -0,0 +1,171 @@
+package typed
+
+import (
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// NoteOn is a note on message.
+type NoteOn struct {
+	Channel  uint8 `json:"channel"`
+	Key      uint8 `json:"key"`
+	Velocity uint8 `json:"velocity"`
+}
+
+// Type returns the type of the message.
+func (v NoteOn) Type() midi.Type {
+	return midi.NoteOnMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v NoteOn) Bytes() []byte {
+	return midi.NoteOn(v.Channel, v.Key, v.Velocity)
+}
+
+func (v *NoteOn) decode(m smf.Message) error {
+	if !m.GetNoteOn(&v.Channel, &v.Key, &v.Velocity) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// NoteOff is a note off message.
+type NoteOff struct {
+	Channel  uint8 `json:"channel"`
+	Key      uint8 `json:"key"`
+	Velocity uint8 `json:"velocity"`
+}
+
+// Type returns the type of the message.
+func (v NoteOff) Type() midi.Type {
+	return midi.NoteOffMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v NoteOff) Bytes() []byte {
+	return midi.NoteOffVelocity(v.Channel, v.Key, v.Velocity)
+}
+
+func (v *NoteOff) decode(m smf.Message) error {
+	if !m.GetNoteOff(&v.Channel, &v.Key, &v.Velocity) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// PolyAfterTouch is a polyphonic aftertouch message.
+type PolyAfterTouch struct {
+	Channel  uint8 `json:"channel"`
+	Key      uint8 `json:"key"`
+	Pressure uint8 `json:"pressure"`
+}
+
+// Type returns the type of the message.
+func (v PolyAfterTouch) Type() midi.Type {
+	return midi.PolyAfterTouchMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v PolyAfterTouch) Bytes() []byte {
+	return midi.PolyAfterTouch(v.Channel, v.Key, v.Pressure)
+}
+
+func (v *PolyAfterTouch) decode(m smf.Message) error {
+	if !m.GetPolyAfterTouch(&v.Channel, &v.Key, &v.Pressure) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// AfterTouch is a channel aftertouch message.
+type AfterTouch struct {
+	Channel  uint8 `json:"channel"`
+	Pressure uint8 `json:"pressure"`
+}
+
+// Type returns the type of the message.
+func (v AfterTouch) Type() midi.Type {
+	return midi.AfterTouchMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v AfterTouch) Bytes() []byte {
+	return midi.AfterTouch(v.Channel, v.Pressure)
+}
+
+func (v *AfterTouch) decode(m smf.Message) error {
+	if !m.GetAfterTouch(&v.Channel, &v.Pressure) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// ControlChange is a control change message.
+type ControlChange struct {
+	Channel    uint8 `json:"channel"`
+	Controller uint8 `json:"controller"`
+	Value      uint8 `json:"value"`
+}
+
+// Type returns the type of the message.
+func (v ControlChange) Type() midi.Type {
+	return midi.ControlChangeMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v ControlChange) Bytes() []byte {
+	return midi.ControlChange(v.Channel, v.Controller, v.Value)
+}
+
+func (v *ControlChange) decode(m smf.Message) error {
+	if !m.GetControlChange(&v.Channel, &v.Controller, &v.Value) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// ProgramChange is a program change message.
+type ProgramChange struct {
+	Channel uint8 `json:"channel"`
+	Program uint8 `json:"program"`
+}
+
+// Type returns the type of the message.
+func (v ProgramChange) Type() midi.Type {
+	return midi.ProgramChangeMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v ProgramChange) Bytes() []byte {
+	return midi.ProgramChange(v.Channel, v.Program)
+}
+
+func (v *ProgramChange) decode(m smf.Message) error {
+	if !m.GetProgramChange(&v.Channel, &v.Program) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// PitchBend is a pitchbend message with the relative value (-8192 - 8191).
+type PitchBend struct {
+	Channel uint8 `json:"channel"`
+	Value   int16 `json:"value"`
+}
+
+// Type returns the type of the message.
+func (v PitchBend) Type() midi.Type {
+	return midi.PitchBendMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v PitchBend) Bytes() []byte {
+	return midi.Pitchbend(v.Channel, v.Value)
+}
+
+func (v *PitchBend) decode(m smf.Message) error {
+	if !m.GetPitchBend(&v.Channel, &v.Value, nil) {
+		return invalid(m)
+	}
+	return nil
+}
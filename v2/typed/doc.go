@@ -0,0 +1,26 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package typed provides typed values for MIDI messages and SMF meta messages, e.g.
+
+	NoteOn{Channel: 2, Key: 60, Velocity: 100}
+
+that can be converted from and to the bytes of midi.Message and smf.Message (via Decode and Bytes).
+
+Every typed value implements encoding.TextMarshaler and encoding.TextUnmarshaler (using the format of the String method
+of the messages) and json.Marshaler. Both encodings keep every field of the value: the text of MetaTimeSig has the
+additional fields clocksperclick and demisemiquaverperquarter and the text of MetaTempo has the full precision of the tempo.
+Note that the bytes of a MetaTempo only have a precision of a microsecond per quarter note.
+The JSON encoding is an object with the fields of the value and the name of the type:
+
+	{"type":"NoteOn","channel":2,"key":60,"velocity":100}
+
+Since the type of a message is not known in advance, use Parse and UnmarshalJSON to get the typed value of a string or
+a JSON object and Messages to unmarshal a JSON array of messages.
+
+The methods that are the same for every typed value are generated (see gen.go). A typed value is defined by a struct
+with the methods Type, Bytes and decode. After adding one, run "go generate".
+*/
+package typed
@@ -0,0 +1,139 @@
+//go:build ignore
+// +build ignore
+
+// gen generates the methods that are the same for every typed value (methods_gen.go).
+// A typed value is a struct type with a decode method. Its Type, Bytes and decode methods are written by hand.
+// Every generated method can be replaced by writing it by hand.
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const output = "methods_gen.go"
+
+var tmpl = template.Must(template.New("methods").Parse(`// Code generated by gen.go; DO NOT EDIT.
+
+package typed
+
+import (
+	"encoding/json"
+)
+
+func init() {
+{{- range .}}
+	register({{.Name}}{}.Type().String(), func() Message { return &{{.Name}}{} })
+{{- end}}
+}
+{{range .}}
+{{- if not .Has.String}}
+// String represents the message as a string.
+func (v {{.Name}}) String() string {
+	return str(v)
+}
+{{end}}
+{{- if not .Has.MarshalText}}
+// MarshalText returns the string of the message.
+func (v {{.Name}}) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+{{end}}
+{{- if not .Has.UnmarshalText}}
+// UnmarshalText parses the string of the message.
+func (v *{{.Name}}) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+{{end}}
+{{- if not .Has.MarshalJSON}}
+// MarshalJSON returns the JSON object of the message.
+func (v {{.Name}}) MarshalJSON() ([]byte, error) {
+	type fields {{.Name}}
+	return marshalJSON(v, fields(v))
+}
+{{end}}
+{{- if not .Has.UnmarshalJSON}}
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *{{.Name}}) UnmarshalJSON(data []byte) error {
+	type fields {{.Name}}
+	return json.Unmarshal(data, (*fields)(v))
+}
+{{end}}
+func (v *{{.Name}}) value() Message {
+	return *v
+}
+{{end}}`))
+
+type typedValue struct {
+	Name string
+	Has  map[string]bool
+}
+
+func main() {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, ".", func(fi os.FileInfo) bool {
+		name := fi.Name()
+		return !strings.HasSuffix(name, "_test.go") && name != output && name != "gen.go"
+	}, 0)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	methods := map[string]map[string]bool{}
+
+	for _, file := range pkgs["typed"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+
+			recv := fn.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+
+			name := recv.(*ast.Ident).Name
+			if methods[name] == nil {
+				methods[name] = map[string]bool{}
+			}
+			methods[name][fn.Name.Name] = true
+		}
+	}
+
+	var values []typedValue
+
+	for name, has := range methods {
+		if has["decode"] {
+			values = append(values, typedValue{Name: name, Has: has})
+		}
+	}
+
+	sort.Slice(values, func(a, b int) bool {
+		return values[a].Name < values[b].Name
+	})
+
+	var bf bytes.Buffer
+	if err := tmpl.Execute(&bf, values); err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := format.Source(bf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,444 @@
+package typed
+
+import (
+	"fmt"
+	"strconv"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/internal/utils"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// MetaTempo is a tempo meta message.
+type MetaTempo struct {
+	BPM float64 `json:"bpm"`
+}
+
+// Type returns the type of the message.
+func (v MetaTempo) Type() midi.Type {
+	return smf.MetaTempoMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaTempo) Bytes() []byte {
+	return smf.MetaTempo(v.BPM)
+}
+
+func (v *MetaTempo) decode(m smf.Message) error {
+	if !m.GetMetaTempo(&v.BPM) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MarshalText returns the string of the message with the full precision of the tempo.
+func (v MetaTempo) MarshalText() ([]byte, error) {
+	return []byte(v.Type().String() + " bpm: " + strconv.FormatFloat(v.BPM, 'f', -1, 64)), nil
+}
+
+// UnmarshalText parses the string of the message with the full precision of the tempo.
+func (v *MetaTempo) UnmarshalText(text []byte) error {
+	name, fields, err := utils.ParseFields(string(text))
+	if err != nil {
+		return err
+	}
+
+	if name != v.Type().String() {
+		return fmt.Errorf("can't unmarshal %s into %s", name, v.Type())
+	}
+
+	bpm, err := strconv.ParseFloat(fields["bpm"], 64)
+	if err != nil || bpm <= 0 {
+		return fmt.Errorf("invalid \"bpm\": %q", fields["bpm"])
+	}
+
+	v.BPM = bpm
+	return nil
+}
+
+// MetaTimeSig is a time signature meta message.
+type MetaTimeSig struct {
+	Numerator                uint8 `json:"numerator"`
+	Denominator              uint8 `json:"denominator"`
+	ClocksPerClick           uint8 `json:"clocksperclick"`
+	DemiSemiQuaverPerQuarter uint8 `json:"demisemiquaverperquarter"`
+}
+
+// Type returns the type of the message.
+func (v MetaTimeSig) Type() midi.Type {
+	return smf.MetaTimeSigMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaTimeSig) Bytes() []byte {
+	return smf.MetaTimeSig(v.Numerator, v.Denominator, v.ClocksPerClick, v.DemiSemiQuaverPerQuarter)
+}
+
+func (v *MetaTimeSig) decode(m smf.Message) error {
+	if !m.GetMetaTimeSig(&v.Numerator, &v.Denominator, &v.ClocksPerClick, &v.DemiSemiQuaverPerQuarter) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MarshalText returns the string of the message with the additional fields clocksperclick and demisemiquaverperquarter,
+// since the string only contains the meter (see smf.ParseMessage).
+func (v MetaTimeSig) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s clocksperclick: %v demisemiquaverperquarter: %v", v, v.ClocksPerClick, v.DemiSemiQuaverPerQuarter)), nil
+}
+
+// MetaKeySig is a key signature meta message (see smf.Key).
+type MetaKeySig struct {
+	Key     uint8 `json:"key"`
+	Num     uint8 `json:"num"`
+	IsMajor bool  `json:"ismajor"`
+	IsFlat  bool  `json:"isflat"`
+}
+
+// Type returns the type of the message.
+func (v MetaKeySig) Type() midi.Type {
+	return smf.MetaKeySigMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaKeySig) Bytes() []byte {
+	return smf.MetaKey(v.Key, v.IsMajor, v.Num, v.IsFlat)
+}
+
+func (v *MetaKeySig) decode(m smf.Message) error {
+	if !m.GetMetaKeySig(&v.Key, &v.Num, &v.IsMajor, &v.IsFlat) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaChannel is a channel meta message.
+type MetaChannel struct {
+	Channel uint8 `json:"channel"`
+}
+
+// Type returns the type of the message.
+func (v MetaChannel) Type() midi.Type {
+	return smf.MetaChannelMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaChannel) Bytes() []byte {
+	return smf.MetaChannel(v.Channel)
+}
+
+func (v *MetaChannel) decode(m smf.Message) error {
+	if !m.GetMetaChannel(&v.Channel) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaPort is a port meta message.
+type MetaPort struct {
+	Port uint8 `json:"port"`
+}
+
+// Type returns the type of the message.
+func (v MetaPort) Type() midi.Type {
+	return smf.MetaPortMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaPort) Bytes() []byte {
+	return smf.MetaPort(v.Port)
+}
+
+func (v *MetaPort) decode(m smf.Message) error {
+	if !m.GetMetaPort(&v.Port) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaSeqNumber is a sequence number meta message.
+type MetaSeqNumber struct {
+	Number uint16 `json:"number"`
+}
+
+// Type returns the type of the message.
+func (v MetaSeqNumber) Type() midi.Type {
+	return smf.MetaSeqNumberMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaSeqNumber) Bytes() []byte {
+	return smf.MetaSequenceNo(v.Number)
+}
+
+func (v *MetaSeqNumber) decode(m smf.Message) error {
+	if !m.GetMetaSeqNumber(&v.Number) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaSeqData is a sequencer specific meta message.
+type MetaSeqData struct {
+	Data []byte `json:"data"`
+}
+
+// Type returns the type of the message.
+func (v MetaSeqData) Type() midi.Type {
+	return smf.MetaSeqDataMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaSeqData) Bytes() []byte {
+	return smf.MetaSequencerData(v.Data)
+}
+
+func (v *MetaSeqData) decode(m smf.Message) error {
+	var data []byte
+	if !m.GetMetaSeqData(&data) {
+		return invalid(m)
+	}
+	v.Data = append([]byte{}, data...)
+	return nil
+}
+
+// MetaSMPTEOffset is a SMPTE offset meta message.
+type MetaSMPTEOffset struct {
+	Hour       uint8 `json:"hour"`
+	Minute     uint8 `json:"minute"`
+	Second     uint8 `json:"second"`
+	Frame      uint8 `json:"frame"`
+	FractFrame uint8 `json:"fractframe"`
+}
+
+// Type returns the type of the message.
+func (v MetaSMPTEOffset) Type() midi.Type {
+	return smf.MetaSMPTEOffsetMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaSMPTEOffset) Bytes() []byte {
+	return smf.MetaSMPTE(v.Hour, v.Minute, v.Second, v.Frame, v.FractFrame)
+}
+
+func (v *MetaSMPTEOffset) decode(m smf.Message) error {
+	if !m.GetMetaSMPTEOffsetMsg(&v.Hour, &v.Minute, &v.Second, &v.Frame, &v.FractFrame) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaEndOfTrack is an end of track meta message.
+type MetaEndOfTrack struct{}
+
+// Type returns the type of the message.
+func (v MetaEndOfTrack) Type() midi.Type {
+	return smf.MetaEndOfTrackMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaEndOfTrack) Bytes() []byte {
+	return smf.EOT
+}
+
+func (v *MetaEndOfTrack) decode(m smf.Message) error {
+	return nil
+}
+
+// MetaText is a text meta message.
+type MetaText struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaText) Type() midi.Type {
+	return smf.MetaTextMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaText) Bytes() []byte {
+	return smf.MetaText(v.Text)
+}
+
+func (v *MetaText) decode(m smf.Message) error {
+	if !m.GetMetaText(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaLyric is a lyric meta message.
+type MetaLyric struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaLyric) Type() midi.Type {
+	return smf.MetaLyricMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaLyric) Bytes() []byte {
+	return smf.MetaLyric(v.Text)
+}
+
+func (v *MetaLyric) decode(m smf.Message) error {
+	if !m.GetMetaLyric(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaMarker is a marker meta message.
+type MetaMarker struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaMarker) Type() midi.Type {
+	return smf.MetaMarkerMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaMarker) Bytes() []byte {
+	return smf.MetaMarker(v.Text)
+}
+
+func (v *MetaMarker) decode(m smf.Message) error {
+	if !m.GetMetaMarker(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaCopyright is a copyright meta message.
+type MetaCopyright struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaCopyright) Type() midi.Type {
+	return smf.MetaCopyrightMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaCopyright) Bytes() []byte {
+	return smf.MetaCopyright(v.Text)
+}
+
+func (v *MetaCopyright) decode(m smf.Message) error {
+	if !m.GetMetaCopyright(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaCuepoint is a cuepoint meta message.
+type MetaCuepoint struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaCuepoint) Type() midi.Type {
+	return smf.MetaCuepointMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaCuepoint) Bytes() []byte {
+	return smf.MetaCuepoint(v.Text)
+}
+
+func (v *MetaCuepoint) decode(m smf.Message) error {
+	if !m.GetMetaCuepoint(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaDevice is a device meta message.
+type MetaDevice struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaDevice) Type() midi.Type {
+	return smf.MetaDeviceMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaDevice) Bytes() []byte {
+	return smf.MetaDevice(v.Text)
+}
+
+func (v *MetaDevice) decode(m smf.Message) error {
+	if !m.GetMetaDevice(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaInstrument is a instrument meta message.
+type MetaInstrument struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaInstrument) Type() midi.Type {
+	return smf.MetaInstrumentMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaInstrument) Bytes() []byte {
+	return smf.MetaInstrument(v.Text)
+}
+
+func (v *MetaInstrument) decode(m smf.Message) error {
+	if !m.GetMetaInstrument(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaProgramName is a program name meta message.
+type MetaProgramName struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaProgramName) Type() midi.Type {
+	return smf.MetaProgramNameMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaProgramName) Bytes() []byte {
+	return smf.MetaProgram(v.Text)
+}
+
+func (v *MetaProgramName) decode(m smf.Message) error {
+	if !m.GetMetaProgramName(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// MetaTrackName is a track name meta message.
+type MetaTrackName struct {
+	Text string `json:"text"`
+}
+
+// Type returns the type of the message.
+func (v MetaTrackName) Type() midi.Type {
+	return smf.MetaTrackNameMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MetaTrackName) Bytes() []byte {
+	return smf.MetaTrackSequenceName(v.Text)
+}
+
+func (v *MetaTrackName) decode(m smf.Message) error {
+	if !m.GetMetaTrackName(&v.Text) {
+		return invalid(m)
+	}
+	return nil
+}
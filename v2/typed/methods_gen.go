@@ -0,0 +1,1179 @@
+// Code generated by gen.go; DO NOT EDIT.
+
+package typed
+
+import (
+	"encoding/json"
+)
+
+func init() {
+	register(ActiveSense{}.Type().String(), func() Message { return &ActiveSense{} })
+	register(AfterTouch{}.Type().String(), func() Message { return &AfterTouch{} })
+	register(Continue{}.Type().String(), func() Message { return &Continue{} })
+	register(ControlChange{}.Type().String(), func() Message { return &ControlChange{} })
+	register(MTC{}.Type().String(), func() Message { return &MTC{} })
+	register(MetaChannel{}.Type().String(), func() Message { return &MetaChannel{} })
+	register(MetaCopyright{}.Type().String(), func() Message { return &MetaCopyright{} })
+	register(MetaCuepoint{}.Type().String(), func() Message { return &MetaCuepoint{} })
+	register(MetaDevice{}.Type().String(), func() Message { return &MetaDevice{} })
+	register(MetaEndOfTrack{}.Type().String(), func() Message { return &MetaEndOfTrack{} })
+	register(MetaInstrument{}.Type().String(), func() Message { return &MetaInstrument{} })
+	register(MetaKeySig{}.Type().String(), func() Message { return &MetaKeySig{} })
+	register(MetaLyric{}.Type().String(), func() Message { return &MetaLyric{} })
+	register(MetaMarker{}.Type().String(), func() Message { return &MetaMarker{} })
+	register(MetaPort{}.Type().String(), func() Message { return &MetaPort{} })
+	register(MetaProgramName{}.Type().String(), func() Message { return &MetaProgramName{} })
+	register(MetaSMPTEOffset{}.Type().String(), func() Message { return &MetaSMPTEOffset{} })
+	register(MetaSeqData{}.Type().String(), func() Message { return &MetaSeqData{} })
+	register(MetaSeqNumber{}.Type().String(), func() Message { return &MetaSeqNumber{} })
+	register(MetaTempo{}.Type().String(), func() Message { return &MetaTempo{} })
+	register(MetaText{}.Type().String(), func() Message { return &MetaText{} })
+	register(MetaTimeSig{}.Type().String(), func() Message { return &MetaTimeSig{} })
+	register(MetaTrackName{}.Type().String(), func() Message { return &MetaTrackName{} })
+	register(NoteOff{}.Type().String(), func() Message { return &NoteOff{} })
+	register(NoteOn{}.Type().String(), func() Message { return &NoteOn{} })
+	register(PitchBend{}.Type().String(), func() Message { return &PitchBend{} })
+	register(PolyAfterTouch{}.Type().String(), func() Message { return &PolyAfterTouch{} })
+	register(ProgramChange{}.Type().String(), func() Message { return &ProgramChange{} })
+	register(Reset{}.Type().String(), func() Message { return &Reset{} })
+	register(SPP{}.Type().String(), func() Message { return &SPP{} })
+	register(SongSelect{}.Type().String(), func() Message { return &SongSelect{} })
+	register(Start{}.Type().String(), func() Message { return &Start{} })
+	register(Stop{}.Type().String(), func() Message { return &Stop{} })
+	register(SysEx{}.Type().String(), func() Message { return &SysEx{} })
+	register(Tick{}.Type().String(), func() Message { return &Tick{} })
+	register(TimingClock{}.Type().String(), func() Message { return &TimingClock{} })
+	register(Tune{}.Type().String(), func() Message { return &Tune{} })
+}
+
+// String represents the message as a string.
+func (v ActiveSense) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v ActiveSense) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *ActiveSense) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v ActiveSense) MarshalJSON() ([]byte, error) {
+	type fields ActiveSense
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *ActiveSense) UnmarshalJSON(data []byte) error {
+	type fields ActiveSense
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *ActiveSense) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v AfterTouch) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v AfterTouch) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *AfterTouch) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v AfterTouch) MarshalJSON() ([]byte, error) {
+	type fields AfterTouch
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *AfterTouch) UnmarshalJSON(data []byte) error {
+	type fields AfterTouch
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *AfterTouch) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Continue) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Continue) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Continue) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Continue) MarshalJSON() ([]byte, error) {
+	type fields Continue
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Continue) UnmarshalJSON(data []byte) error {
+	type fields Continue
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Continue) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v ControlChange) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v ControlChange) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *ControlChange) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v ControlChange) MarshalJSON() ([]byte, error) {
+	type fields ControlChange
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *ControlChange) UnmarshalJSON(data []byte) error {
+	type fields ControlChange
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *ControlChange) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MTC) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MTC) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MTC) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MTC) MarshalJSON() ([]byte, error) {
+	type fields MTC
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MTC) UnmarshalJSON(data []byte) error {
+	type fields MTC
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MTC) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaChannel) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaChannel) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaChannel) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaChannel) MarshalJSON() ([]byte, error) {
+	type fields MetaChannel
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaChannel) UnmarshalJSON(data []byte) error {
+	type fields MetaChannel
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaChannel) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaCopyright) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaCopyright) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaCopyright) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaCopyright) MarshalJSON() ([]byte, error) {
+	type fields MetaCopyright
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaCopyright) UnmarshalJSON(data []byte) error {
+	type fields MetaCopyright
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaCopyright) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaCuepoint) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaCuepoint) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaCuepoint) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaCuepoint) MarshalJSON() ([]byte, error) {
+	type fields MetaCuepoint
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaCuepoint) UnmarshalJSON(data []byte) error {
+	type fields MetaCuepoint
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaCuepoint) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaDevice) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaDevice) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaDevice) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaDevice) MarshalJSON() ([]byte, error) {
+	type fields MetaDevice
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaDevice) UnmarshalJSON(data []byte) error {
+	type fields MetaDevice
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaDevice) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaEndOfTrack) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaEndOfTrack) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaEndOfTrack) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaEndOfTrack) MarshalJSON() ([]byte, error) {
+	type fields MetaEndOfTrack
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaEndOfTrack) UnmarshalJSON(data []byte) error {
+	type fields MetaEndOfTrack
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaEndOfTrack) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaInstrument) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaInstrument) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaInstrument) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaInstrument) MarshalJSON() ([]byte, error) {
+	type fields MetaInstrument
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaInstrument) UnmarshalJSON(data []byte) error {
+	type fields MetaInstrument
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaInstrument) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaKeySig) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaKeySig) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaKeySig) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaKeySig) MarshalJSON() ([]byte, error) {
+	type fields MetaKeySig
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaKeySig) UnmarshalJSON(data []byte) error {
+	type fields MetaKeySig
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaKeySig) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaLyric) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaLyric) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaLyric) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaLyric) MarshalJSON() ([]byte, error) {
+	type fields MetaLyric
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaLyric) UnmarshalJSON(data []byte) error {
+	type fields MetaLyric
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaLyric) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaMarker) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaMarker) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaMarker) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaMarker) MarshalJSON() ([]byte, error) {
+	type fields MetaMarker
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaMarker) UnmarshalJSON(data []byte) error {
+	type fields MetaMarker
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaMarker) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaPort) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaPort) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaPort) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaPort) MarshalJSON() ([]byte, error) {
+	type fields MetaPort
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaPort) UnmarshalJSON(data []byte) error {
+	type fields MetaPort
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaPort) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaProgramName) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaProgramName) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaProgramName) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaProgramName) MarshalJSON() ([]byte, error) {
+	type fields MetaProgramName
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaProgramName) UnmarshalJSON(data []byte) error {
+	type fields MetaProgramName
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaProgramName) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaSMPTEOffset) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaSMPTEOffset) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaSMPTEOffset) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaSMPTEOffset) MarshalJSON() ([]byte, error) {
+	type fields MetaSMPTEOffset
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaSMPTEOffset) UnmarshalJSON(data []byte) error {
+	type fields MetaSMPTEOffset
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaSMPTEOffset) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaSeqData) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaSeqData) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaSeqData) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaSeqData) MarshalJSON() ([]byte, error) {
+	type fields MetaSeqData
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaSeqData) UnmarshalJSON(data []byte) error {
+	type fields MetaSeqData
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaSeqData) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaSeqNumber) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaSeqNumber) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaSeqNumber) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaSeqNumber) MarshalJSON() ([]byte, error) {
+	type fields MetaSeqNumber
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaSeqNumber) UnmarshalJSON(data []byte) error {
+	type fields MetaSeqNumber
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaSeqNumber) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaTempo) String() string {
+	return str(v)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaTempo) MarshalJSON() ([]byte, error) {
+	type fields MetaTempo
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaTempo) UnmarshalJSON(data []byte) error {
+	type fields MetaTempo
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaTempo) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaText) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaText) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaText) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaText) MarshalJSON() ([]byte, error) {
+	type fields MetaText
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaText) UnmarshalJSON(data []byte) error {
+	type fields MetaText
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaText) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaTimeSig) String() string {
+	return str(v)
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaTimeSig) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaTimeSig) MarshalJSON() ([]byte, error) {
+	type fields MetaTimeSig
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaTimeSig) UnmarshalJSON(data []byte) error {
+	type fields MetaTimeSig
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaTimeSig) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v MetaTrackName) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v MetaTrackName) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *MetaTrackName) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v MetaTrackName) MarshalJSON() ([]byte, error) {
+	type fields MetaTrackName
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *MetaTrackName) UnmarshalJSON(data []byte) error {
+	type fields MetaTrackName
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *MetaTrackName) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v NoteOff) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v NoteOff) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *NoteOff) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v NoteOff) MarshalJSON() ([]byte, error) {
+	type fields NoteOff
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *NoteOff) UnmarshalJSON(data []byte) error {
+	type fields NoteOff
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *NoteOff) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v NoteOn) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v NoteOn) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *NoteOn) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v NoteOn) MarshalJSON() ([]byte, error) {
+	type fields NoteOn
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *NoteOn) UnmarshalJSON(data []byte) error {
+	type fields NoteOn
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *NoteOn) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v PitchBend) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v PitchBend) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *PitchBend) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v PitchBend) MarshalJSON() ([]byte, error) {
+	type fields PitchBend
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *PitchBend) UnmarshalJSON(data []byte) error {
+	type fields PitchBend
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *PitchBend) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v PolyAfterTouch) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v PolyAfterTouch) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *PolyAfterTouch) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v PolyAfterTouch) MarshalJSON() ([]byte, error) {
+	type fields PolyAfterTouch
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *PolyAfterTouch) UnmarshalJSON(data []byte) error {
+	type fields PolyAfterTouch
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *PolyAfterTouch) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v ProgramChange) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v ProgramChange) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *ProgramChange) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v ProgramChange) MarshalJSON() ([]byte, error) {
+	type fields ProgramChange
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *ProgramChange) UnmarshalJSON(data []byte) error {
+	type fields ProgramChange
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *ProgramChange) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Reset) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Reset) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Reset) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Reset) MarshalJSON() ([]byte, error) {
+	type fields Reset
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Reset) UnmarshalJSON(data []byte) error {
+	type fields Reset
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Reset) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v SPP) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v SPP) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *SPP) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v SPP) MarshalJSON() ([]byte, error) {
+	type fields SPP
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *SPP) UnmarshalJSON(data []byte) error {
+	type fields SPP
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *SPP) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v SongSelect) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v SongSelect) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *SongSelect) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v SongSelect) MarshalJSON() ([]byte, error) {
+	type fields SongSelect
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *SongSelect) UnmarshalJSON(data []byte) error {
+	type fields SongSelect
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *SongSelect) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Start) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Start) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Start) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Start) MarshalJSON() ([]byte, error) {
+	type fields Start
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Start) UnmarshalJSON(data []byte) error {
+	type fields Start
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Start) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Stop) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Stop) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Stop) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Stop) MarshalJSON() ([]byte, error) {
+	type fields Stop
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Stop) UnmarshalJSON(data []byte) error {
+	type fields Stop
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Stop) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v SysEx) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v SysEx) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *SysEx) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v SysEx) MarshalJSON() ([]byte, error) {
+	type fields SysEx
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *SysEx) UnmarshalJSON(data []byte) error {
+	type fields SysEx
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *SysEx) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Tick) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Tick) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Tick) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Tick) MarshalJSON() ([]byte, error) {
+	type fields Tick
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Tick) UnmarshalJSON(data []byte) error {
+	type fields Tick
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Tick) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v TimingClock) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v TimingClock) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *TimingClock) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v TimingClock) MarshalJSON() ([]byte, error) {
+	type fields TimingClock
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *TimingClock) UnmarshalJSON(data []byte) error {
+	type fields TimingClock
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *TimingClock) value() Message {
+	return *v
+}
+
+// String represents the message as a string.
+func (v Tune) String() string {
+	return str(v)
+}
+
+// MarshalText returns the string of the message.
+func (v Tune) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText parses the string of the message.
+func (v *Tune) UnmarshalText(text []byte) error {
+	return unmarshalText(v, text)
+}
+
+// MarshalJSON returns the JSON object of the message.
+func (v Tune) MarshalJSON() ([]byte, error) {
+	type fields Tune
+	return marshalJSON(v, fields(v))
+}
+
+// UnmarshalJSON unmarshals the JSON object of the message.
+func (v *Tune) UnmarshalJSON(data []byte) error {
+	type fields Tune
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+func (v *Tune) value() Message {
+	return *v
+}
@@ -0,0 +1,239 @@
+package typed
+
+import (
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+// Tick is a tick realtime message.
+type Tick struct{}
+
+// Type returns the type of the message.
+func (v Tick) Type() midi.Type {
+	return midi.TickMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Tick) Bytes() []byte {
+	return midi.Tick()
+}
+
+func (v *Tick) decode(m smf.Message) error {
+	return nil
+}
+
+// TimingClock is a timing clock realtime message.
+type TimingClock struct{}
+
+// Type returns the type of the message.
+func (v TimingClock) Type() midi.Type {
+	return midi.TimingClockMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v TimingClock) Bytes() []byte {
+	return midi.TimingClock()
+}
+
+func (v *TimingClock) decode(m smf.Message) error {
+	return nil
+}
+
+// Start is a start realtime message.
+type Start struct{}
+
+// Type returns the type of the message.
+func (v Start) Type() midi.Type {
+	return midi.StartMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Start) Bytes() []byte {
+	return midi.Start()
+}
+
+func (v *Start) decode(m smf.Message) error {
+	return nil
+}
+
+// Continue is a continue realtime message.
+type Continue struct{}
+
+// Type returns the type of the message.
+func (v Continue) Type() midi.Type {
+	return midi.ContinueMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Continue) Bytes() []byte {
+	return midi.Continue()
+}
+
+func (v *Continue) decode(m smf.Message) error {
+	return nil
+}
+
+// Stop is a stop realtime message.
+type Stop struct{}
+
+// Type returns the type of the message.
+func (v Stop) Type() midi.Type {
+	return midi.StopMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Stop) Bytes() []byte {
+	return midi.Stop()
+}
+
+func (v *Stop) decode(m smf.Message) error {
+	return nil
+}
+
+// ActiveSense is a active sense realtime message.
+type ActiveSense struct{}
+
+// Type returns the type of the message.
+func (v ActiveSense) Type() midi.Type {
+	return midi.ActiveSenseMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v ActiveSense) Bytes() []byte {
+	return midi.Activesense()
+}
+
+func (v *ActiveSense) decode(m smf.Message) error {
+	return nil
+}
+
+// Reset is a reset realtime message.
+type Reset struct{}
+
+// Type returns the type of the message.
+func (v Reset) Type() midi.Type {
+	return midi.ResetMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Reset) Bytes() []byte {
+	return midi.Reset()
+}
+
+func (v *Reset) decode(m smf.Message) error {
+	return nil
+}
+
+// MTC is a MIDI time code quarter frame message.
+type MTC struct {
+	QuarterFrame uint8 `json:"quarterframe"`
+}
+
+// Type returns the type of the message.
+func (v MTC) Type() midi.Type {
+	return midi.MTCMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v MTC) Bytes() []byte {
+	return midi.MTC(v.QuarterFrame)
+}
+
+func (v *MTC) decode(m smf.Message) error {
+	if !midi.Message(m).GetMTC(&v.QuarterFrame) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// SPP is a song position pointer message.
+type SPP struct {
+	Pointer uint16 `json:"pointer"`
+}
+
+// Type returns the type of the message.
+func (v SPP) Type() midi.Type {
+	return midi.SPPMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v SPP) Bytes() []byte {
+	return midi.SPP(v.Pointer)
+}
+
+func (v *SPP) decode(m smf.Message) error {
+	if !midi.Message(m).GetSPP(&v.Pointer) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// SongSelect is a song select message.
+type SongSelect struct {
+	Song uint8 `json:"song"`
+}
+
+// Type returns the type of the message.
+func (v SongSelect) Type() midi.Type {
+	return midi.SongSelectMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v SongSelect) Bytes() []byte {
+	return midi.SongSelect(v.Song)
+}
+
+func (v *SongSelect) decode(m smf.Message) error {
+	if !midi.Message(m).GetSongSelect(&v.Song) {
+		return invalid(m)
+	}
+	return nil
+}
+
+// Tune is a tune request message.
+type Tune struct{}
+
+// Type returns the type of the message.
+func (v Tune) Type() midi.Type {
+	return midi.TuneMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v Tune) Bytes() []byte {
+	return midi.Tune()
+}
+
+func (v *Tune) decode(m smf.Message) error {
+	return nil
+}
+
+// SysEx is a system exclusive message with the inner bytes (without F0 and F7).
+type SysEx struct {
+	Data []byte `json:"data"`
+}
+
+// Type returns the type of the message.
+func (v SysEx) Type() midi.Type {
+	return midi.SysExMsg
+}
+
+// Bytes returns the bytes of the message.
+func (v SysEx) Bytes() []byte {
+	return midi.SysEx(v.Data)
+}
+
+func (v *SysEx) decode(m smf.Message) error {
+	var data []byte
+
+	// GetSysEx does not accept the empty sysex message
+	if len(m) == 2 && m[0] == 0xF0 && m[1] == 0xF7 {
+		v.Data = []byte{}
+		return nil
+	}
+
+	if !m.GetSysEx(&data) {
+		return invalid(m)
+	}
+	v.Data = append([]byte{}, data...)
+	return nil
+}
@@ -0,0 +1,177 @@
+package typed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+//go:generate go run gen.go
+
+// ErrUnsupported is returned, if a message has no typed value
+var ErrUnsupported = errors.New("unsupported message type")
+
+// Message is the typed value of a MIDI message or a SMF meta message.
+type Message interface {
+	// Type returns the type of the message.
+	Type() midi.Type
+
+	// Bytes returns the bytes of the message (that can be converted to midi.Message or smf.Message).
+	Bytes() []byte
+
+	// String represents the message as a string, like midi.Message.String and smf.Message.String.
+	String() string
+}
+
+// types are the constructors of the empty values per type name
+var types = map[string]func() Message{}
+
+func register(name string, fn func() Message) {
+	types[name] = fn
+}
+
+// Decode returns the typed value of the given message (midi.Message or smf.Message).
+func Decode(msg []byte) (Message, error) {
+	m := smf.Message(msg)
+	typ := typeOf(m)
+
+	fn, has := types[typ.String()]
+	if !has {
+		return nil, fmt.Errorf("%w: % X", ErrUnsupported, msg)
+	}
+
+	v := fn()
+	if err := v.(decoder).decode(m); err != nil {
+		return nil, err
+	}
+
+	return reflectValue(v), nil
+}
+
+// typeOf returns the type of the given message (midi.Message or smf.Message)
+func typeOf(m smf.Message) midi.Type {
+	// the reset message has the same status byte as meta messages
+	if len(m) == 1 {
+		return midi.Message(m).Type()
+	}
+	return m.Type()
+}
+
+// decoder is implemented by the pointers to the typed values
+type decoder interface {
+	Message
+	decode(smf.Message) error
+}
+
+// reflectValue returns the value of the pointer of a typed value
+func reflectValue(v Message) Message {
+	return v.(interface{ value() Message }).value()
+}
+
+// str returns the string of the given typed value, like midi.Message.String and smf.Message.String.
+func str(v Message) string {
+	bt := v.Bytes()
+
+	// the reset message has the same status byte as meta messages
+	if len(bt) > 1 && bt[0] == 0xFF {
+		return smf.Message(bt).String()
+	}
+
+	return midi.Message(bt).String()
+}
+
+var errInvalid = errors.New("invalid message")
+
+func invalid(m smf.Message) error {
+	return fmt.Errorf("%w: % X", errInvalid, []byte(m))
+}
+
+// Parse parses a string, as it is returned by the String methods, to the typed value (see smf.ParseMessage).
+func Parse(s string) (Message, error) {
+	msg, err := smf.ParseMessage(s)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(msg)
+}
+
+// UnmarshalJSON returns the typed value of the given JSON object, as it is returned by json.Marshal.
+func UnmarshalJSON(data []byte) (Message, error) {
+	var t struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+
+	fn, has := types[t.Type]
+	if !has {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, t.Type)
+	}
+
+	v := fn()
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	return reflectValue(v), nil
+}
+
+// Messages is a list of typed values that can be marshalled to and unmarshalled from a JSON array.
+type Messages []Message
+
+// UnmarshalJSON unmarshals a JSON array of typed values.
+func (m *Messages) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	res := make(Messages, len(raw))
+
+	for i, r := range raw {
+		v, err := UnmarshalJSON(r)
+		if err != nil {
+			return fmt.Errorf("message %v: %w", i, err)
+		}
+		res[i] = v
+	}
+
+	*m = res
+	return nil
+}
+
+// marshalJSON returns the JSON object of the given fields with the additional type name.
+func marshalJSON(m Message, fields interface{}) ([]byte, error) {
+	bt, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := fmt.Sprintf(`{"type":%q`, m.Type().String())
+
+	if string(bt) == "{}" {
+		return []byte(typ + "}"), nil
+	}
+
+	return append([]byte(typ+","), bt[1:]...), nil
+}
+
+// unmarshalText parses the text to the typed value that must be of the same type as the given one.
+func unmarshalText(v decoder, text []byte) error {
+	msg, err := smf.ParseMessage(string(text))
+	if err != nil {
+		return err
+	}
+
+	if typ := typeOf(msg); typ != v.Type() {
+		return fmt.Errorf("can't unmarshal %s into %s", typ, v.Type())
+	}
+
+	return v.decode(msg)
+}
@@ -0,0 +1,184 @@
+package typed
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+)
+
+var all = []Message{
+	NoteOn{Channel: 2, Key: 60, Velocity: 100},
+	NoteOff{Channel: 2, Key: 60},
+	NoteOff{Channel: 2, Key: 60, Velocity: 20},
+	PolyAfterTouch{Channel: 3, Key: 61, Pressure: 4},
+	AfterTouch{Channel: 3, Pressure: 5},
+	ControlChange{Channel: 15, Controller: midi.VolumeMSB, Value: 127},
+	ProgramChange{Channel: 1, Program: 10},
+	PitchBend{Channel: 1, Value: -200},
+	Tick{},
+	TimingClock{},
+	Start{},
+	Continue{},
+	Stop{},
+	ActiveSense{},
+	Reset{},
+	MTC{QuarterFrame: 0x21},
+	SPP{Pointer: 300},
+	SongSelect{Song: 3},
+	Tune{},
+	SysEx{Data: []byte{0x7E, 0x7F, 0x09, 0x01}},
+	MetaTempo{BPM: 120},
+	MetaTimeSig{Numerator: 3, Denominator: 4, ClocksPerClick: 8, DemiSemiQuaverPerQuarter: 8},
+	MetaKeySig{Key: 7, Num: 1, IsMajor: true},
+	MetaChannel{Channel: 4},
+	MetaPort{Port: 2},
+	MetaSeqNumber{Number: 300},
+	MetaSeqData{Data: []byte{1, 2, 3}},
+	MetaSMPTEOffset{Hour: 1, Minute: 2, Second: 3, Frame: 4, FractFrame: 5},
+	MetaEndOfTrack{},
+	MetaText{Text: "text"},
+	MetaLyric{Text: `a "quoted" lyric: la`},
+	MetaMarker{Text: "marker"},
+	MetaCopyright{Text: "copyright"},
+	MetaCuepoint{Text: "cue"},
+	MetaDevice{Text: "device"},
+	MetaInstrument{Text: "piano"},
+	MetaProgramName{Text: "program"},
+	MetaTrackName{Text: "track"},
+}
+
+func TestDecode(t *testing.T) {
+	for _, m := range all {
+		got, err := Decode(m.Bytes())
+		if err != nil {
+			t.Errorf("Decode(% X) returned error: %s", m.Bytes(), err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, m) {
+			t.Errorf("Decode(% X) = %#v; want %#v", m.Bytes(), got, m)
+		}
+	}
+
+	if _, err := Decode(midi.Message{0xF4}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, m := range all {
+		got, err := Parse(m.String())
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %s", m.String(), err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, m) {
+			t.Errorf("Parse(%q) = %#v; want %#v", m.String(), got, m)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	text, err := NoteOn{Channel: 1, Key: 2, Velocity: 3}.MarshalText()
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if got, want := string(text), "NoteOn channel: 1 key: 2 velocity: 3"; got != want {
+		t.Errorf("MarshalText() = %q; want %q", got, want)
+	}
+
+	var n NoteOn
+	if err := n.UnmarshalText(text); err != nil || n != (NoteOn{Channel: 1, Key: 2, Velocity: 3}) {
+		t.Errorf("UnmarshalText(%q) = %v, %v", text, n, err)
+	}
+
+	var c ControlChange
+	if err := c.UnmarshalText(text); err == nil {
+		t.Errorf("expected error when unmarshalling a NoteOn into a ControlChange")
+	}
+}
+
+// lossy are values that can't be recreated from their String or Bytes
+var lossy = []Message{
+	MetaTempo{BPM: 123.456},
+	MetaTimeSig{Numerator: 6, Denominator: 8, ClocksPerClick: 36, DemiSemiQuaverPerQuarter: 8},
+	SysEx{Data: []byte{}},
+}
+
+func TestTextRoundTrip(t *testing.T) {
+	for _, m := range append(append([]Message{}, all...), lossy...) {
+		text, err := m.(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			t.Errorf("%#v.MarshalText() returned error: %s", m, err)
+			continue
+		}
+
+		v := types[m.Type().String()]()
+		if err := v.(encoding.TextUnmarshaler).UnmarshalText(text); err != nil {
+			t.Errorf("UnmarshalText(%q) returned error: %s", text, err)
+			continue
+		}
+
+		if got := reflectValue(v); !reflect.DeepEqual(got, m) {
+			t.Errorf("UnmarshalText(%q) = %#v; want %#v", text, got, m)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(NoteOn{Channel: 1, Key: 2, Velocity: 3})
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if got, want := string(data), `{"type":"NoteOn","channel":1,"key":2,"velocity":3}`; got != want {
+		t.Errorf("json.Marshal() = %s; want %s", got, want)
+	}
+
+	want := append(append(Messages{}, all...), lossy...)
+
+	data, err = json.Marshal(want)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	var msgs Messages
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if !reflect.DeepEqual(msgs, want) {
+		t.Errorf("json round trip\ngot:  %#v\nwant: %#v", msgs, want)
+	}
+
+	if _, err := UnmarshalJSON([]byte(`{"type":"Unknown"}`)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	var tr smf.Track
+	tr.Add(0, MetaTrackName{Text: "piano"}.Bytes())
+	tr.Add(0, NoteOn{Channel: 0, Key: 60, Velocity: 100}.Bytes())
+	tr.Close(10)
+
+	var names []string
+	for _, ev := range tr {
+		m, err := Decode(ev.Message)
+		if err != nil {
+			t.Fatalf("ERROR: %s", err)
+		}
+		names = append(names, m.Type().String())
+	}
+
+	if got, want := names, []string{"MetaTrackName", "NoteOn", "MetaEndOfTrack"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,422 @@
+package ump
+
+// opcodes of MIDI 2.0 channel voice messages
+const (
+	opRegisteredPerNoteController = 0x0
+	opAssignablePerNoteController = 0x1
+	opRegisteredController        = 0x2
+	opAssignableController        = 0x3
+	opRelativeRegisteredCtrl      = 0x4
+	opRelativeAssignableCtrl      = 0x5
+	opPerNotePitchBend            = 0x6
+	opNoteOff                     = 0x8
+	opNoteOn                      = 0x9
+	opPolyPressure                = 0xA
+	opControlChange               = 0xB
+	opProgramChange               = 0xC
+	opChannelPressure             = 0xD
+	opPitchBend                   = 0xE
+	opPerNoteManagement           = 0xF
+)
+
+const (
+	// PitchReset is the 32-bit pitch bend value for the neutral position
+	PitchReset uint32 = 0x80000000
+
+	// VelocityDefault is the 16-bit velocity that corresponds to a MIDI 1.0 velocity of 64
+	VelocityDefault uint16 = 0x8000
+)
+
+// channelVoice returns a MIDI 2.0 channel voice message
+func channelVoice(group, opcode, channel, index1, index2 uint8, data uint32) Message {
+	if channel > 15 {
+		channel = 15
+	}
+	return Message{word0(MIDI2ChannelVoiceMsg, group, opcode<<4|channel, index1&0x7F, index2), data}
+}
+
+// getChannelVoice returns true, if the message is a MIDI 2.0 channel voice message of the given opcode.
+// Then it also extracts group and channel to the given arguments.
+func (m Message) getChannelVoice(opcode uint8, group, channel *uint8) bool {
+	if len(m) != 2 || m.Type() != MIDI2ChannelVoiceMsg || m.status()>>4 != opcode {
+		return false
+	}
+
+	if group != nil {
+		*group = m.Group()
+	}
+
+	if channel != nil {
+		*channel = m.status() & 0x0F
+	}
+	return true
+}
+
+// NoteOn returns a MIDI 2.0 note on message with a 16-bit velocity.
+// In contrast to MIDI 1.0, a velocity of 0 does not mean a note off.
+func NoteOn(group, channel, key uint8, velocity uint16) Message {
+	return NoteOnWithAttribute(group, channel, key, velocity, 0, 0)
+}
+
+// NoteOnWithAttribute returns a MIDI 2.0 note on message with a 16-bit velocity and an attribute.
+func NoteOnWithAttribute(group, channel, key uint8, velocity uint16, attrType uint8, attr uint16) Message {
+	return channelVoice(group, opNoteOn, channel, key, attrType, uint32(velocity)<<16|uint32(attr))
+}
+
+// NoteOff returns a MIDI 2.0 note off message with a 16-bit velocity.
+func NoteOff(group, channel, key uint8, velocity uint16) Message {
+	return NoteOffWithAttribute(group, channel, key, velocity, 0, 0)
+}
+
+// NoteOffWithAttribute returns a MIDI 2.0 note off message with a 16-bit velocity and an attribute.
+func NoteOffWithAttribute(group, channel, key uint8, velocity uint16, attrType uint8, attr uint16) Message {
+	return channelVoice(group, opNoteOff, channel, key, attrType, uint32(velocity)<<16|uint32(attr))
+}
+
+// PolyAfterTouch returns a MIDI 2.0 polyphonic aftertouch message with a 32-bit pressure.
+func PolyAfterTouch(group, channel, key uint8, pressure uint32) Message {
+	return channelVoice(group, opPolyPressure, channel, key, 0, pressure)
+}
+
+// ControlChange returns a MIDI 2.0 control change message with a 32-bit value.
+func ControlChange(group, channel, controller uint8, value uint32) Message {
+	return channelVoice(group, opControlChange, channel, controller, 0, value)
+}
+
+// RegisteredController returns a MIDI 2.0 registered controller (RPN) message with a 32-bit value.
+func RegisteredController(group, channel, bank, index uint8, value uint32) Message {
+	return channelVoice(group, opRegisteredController, channel, bank, index&0x7F, value)
+}
+
+// AssignableController returns a MIDI 2.0 assignable controller (NRPN) message with a 32-bit value.
+func AssignableController(group, channel, bank, index uint8, value uint32) Message {
+	return channelVoice(group, opAssignableController, channel, bank, index&0x7F, value)
+}
+
+// RelativeRegisteredController returns a MIDI 2.0 relative registered controller message.
+func RelativeRegisteredController(group, channel, bank, index uint8, value int32) Message {
+	return channelVoice(group, opRelativeRegisteredCtrl, channel, bank, index&0x7F, uint32(value))
+}
+
+// RelativeAssignableController returns a MIDI 2.0 relative assignable controller message.
+func RelativeAssignableController(group, channel, bank, index uint8, value int32) Message {
+	return channelVoice(group, opRelativeAssignableCtrl, channel, bank, index&0x7F, uint32(value))
+}
+
+// RegisteredPerNoteController returns a MIDI 2.0 registered per-note controller message.
+func RegisteredPerNoteController(group, channel, key, index uint8, value uint32) Message {
+	return channelVoice(group, opRegisteredPerNoteController, channel, key, index, value)
+}
+
+// AssignablePerNoteController returns a MIDI 2.0 assignable per-note controller message.
+func AssignablePerNoteController(group, channel, key, index uint8, value uint32) Message {
+	return channelVoice(group, opAssignablePerNoteController, channel, key, index, value)
+}
+
+// PerNotePitchbend returns a MIDI 2.0 per-note pitch bend message. A value of PitchReset is the neutral position.
+func PerNotePitchbend(group, channel, key uint8, value uint32) Message {
+	return channelVoice(group, opPerNotePitchBend, channel, key, 0, value)
+}
+
+// PerNoteManagement returns a MIDI 2.0 per-note management message.
+// If detach is true, the per-note controllers are detached from previously received notes.
+// If reset is true, the per-note controllers are reset to their defaults.
+func PerNoteManagement(group, channel, key uint8, detach, reset bool) Message {
+	var flags uint8
+	if detach {
+		flags |= 0x02
+	}
+	if reset {
+		flags |= 0x01
+	}
+	return channelVoice(group, opPerNoteManagement, channel, key, flags, 0)
+}
+
+// ProgramChange returns a MIDI 2.0 program change message without bank select.
+func ProgramChange(group, channel, program uint8) Message {
+	return channelVoice(group, opProgramChange, channel, 0, 0, uint32(program&0x7F)<<24)
+}
+
+// ProgramChangeWithBank returns a MIDI 2.0 program change message that also selects the bank.
+func ProgramChangeWithBank(group, channel, program, bankMSB, bankLSB uint8) Message {
+	return channelVoice(group, opProgramChange, channel, 0, 0x01, uint32(program&0x7F)<<24|uint32(bankMSB&0x7F)<<8|uint32(bankLSB&0x7F))
+}
+
+// AfterTouch returns a MIDI 2.0 aftertouch (channel pressure) message with a 32-bit pressure.
+func AfterTouch(group, channel uint8, pressure uint32) Message {
+	return channelVoice(group, opChannelPressure, channel, 0, 0, pressure)
+}
+
+// Pitchbend returns a MIDI 2.0 pitch bend message with a 32-bit value. A value of PitchReset is the neutral position.
+func Pitchbend(group, channel uint8, value uint32) Message {
+	return channelVoice(group, opPitchBend, channel, 0, 0, value)
+}
+
+// GetNoteOn returns true if (and only if) the message is a MIDI 2.0 NoteOn message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetNoteOn(group, channel, key *uint8, velocity *uint16) (is bool) {
+	if !m.getChannelVoice(opNoteOn, group, channel) {
+		return false
+	}
+
+	if key != nil {
+		*key = m.byte3() & 0x7F
+	}
+
+	if velocity != nil {
+		*velocity = uint16(m[1] >> 16)
+	}
+	return true
+}
+
+// GetNoteOff returns true if (and only if) the message is a MIDI 2.0 NoteOff message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetNoteOff(group, channel, key *uint8, velocity *uint16) (is bool) {
+	if !m.getChannelVoice(opNoteOff, group, channel) {
+		return false
+	}
+
+	if key != nil {
+		*key = m.byte3() & 0x7F
+	}
+
+	if velocity != nil {
+		*velocity = uint16(m[1] >> 16)
+	}
+	return true
+}
+
+// GetNoteAttribute returns true if (and only if) the message is a MIDI 2.0 NoteOn or NoteOff message.
+// Then it also extracts the attribute type and attribute data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetNoteAttribute(attrType *uint8, attr *uint16) (is bool) {
+	if !m.getChannelVoice(opNoteOn, nil, nil) && !m.getChannelVoice(opNoteOff, nil, nil) {
+		return false
+	}
+
+	if attrType != nil {
+		*attrType = m.byte4()
+	}
+
+	if attr != nil {
+		*attr = uint16(m[1])
+	}
+	return true
+}
+
+// GetPolyAfterTouch returns true if (and only if) the message is a MIDI 2.0 PolyAfterTouch message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetPolyAfterTouch(group, channel, key *uint8, pressure *uint32) (is bool) {
+	if !m.getChannelVoice(opPolyPressure, group, channel) {
+		return false
+	}
+
+	if key != nil {
+		*key = m.byte3() & 0x7F
+	}
+
+	if pressure != nil {
+		*pressure = m[1]
+	}
+	return true
+}
+
+// GetControlChange returns true if (and only if) the message is a MIDI 2.0 ControlChange message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetControlChange(group, channel, controller *uint8, value *uint32) (is bool) {
+	if !m.getChannelVoice(opControlChange, group, channel) {
+		return false
+	}
+
+	if controller != nil {
+		*controller = m.byte3() & 0x7F
+	}
+
+	if value != nil {
+		*value = m[1]
+	}
+	return true
+}
+
+func (m Message) getController(opcode uint8, group, channel, bank, index *uint8, value *uint32) (is bool) {
+	if !m.getChannelVoice(opcode, group, channel) {
+		return false
+	}
+
+	if bank != nil {
+		*bank = m.byte3() & 0x7F
+	}
+
+	if index != nil {
+		*index = m.byte4() & 0x7F
+	}
+
+	if value != nil {
+		*value = m[1]
+	}
+	return true
+}
+
+// GetRegisteredController returns true if (and only if) the message is a MIDI 2.0 registered controller (RPN) message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetRegisteredController(group, channel, bank, index *uint8, value *uint32) (is bool) {
+	return m.getController(opRegisteredController, group, channel, bank, index, value)
+}
+
+// GetAssignableController returns true if (and only if) the message is a MIDI 2.0 assignable controller (NRPN) message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetAssignableController(group, channel, bank, index *uint8, value *uint32) (is bool) {
+	return m.getController(opAssignableController, group, channel, bank, index, value)
+}
+
+// GetRelativeRegisteredController returns true if (and only if) the message is a MIDI 2.0 relative registered controller message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetRelativeRegisteredController(group, channel, bank, index *uint8, value *int32) (is bool) {
+	var v uint32
+	if !m.getController(opRelativeRegisteredCtrl, group, channel, bank, index, &v) {
+		return false
+	}
+
+	if value != nil {
+		*value = int32(v)
+	}
+	return true
+}
+
+// GetRelativeAssignableController returns true if (and only if) the message is a MIDI 2.0 relative assignable controller message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetRelativeAssignableController(group, channel, bank, index *uint8, value *int32) (is bool) {
+	var v uint32
+	if !m.getController(opRelativeAssignableCtrl, group, channel, bank, index, &v) {
+		return false
+	}
+
+	if value != nil {
+		*value = int32(v)
+	}
+	return true
+}
+
+func (m Message) getPerNoteController(opcode uint8, group, channel, key, index *uint8, value *uint32) (is bool) {
+	if !m.getChannelVoice(opcode, group, channel) {
+		return false
+	}
+
+	if key != nil {
+		*key = m.byte3() & 0x7F
+	}
+
+	if index != nil {
+		*index = m.byte4()
+	}
+
+	if value != nil {
+		*value = m[1]
+	}
+	return true
+}
+
+// GetRegisteredPerNoteController returns true if (and only if) the message is a MIDI 2.0 registered per-note controller message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetRegisteredPerNoteController(group, channel, key, index *uint8, value *uint32) (is bool) {
+	return m.getPerNoteController(opRegisteredPerNoteController, group, channel, key, index, value)
+}
+
+// GetAssignablePerNoteController returns true if (and only if) the message is a MIDI 2.0 assignable per-note controller message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetAssignablePerNoteController(group, channel, key, index *uint8, value *uint32) (is bool) {
+	return m.getPerNoteController(opAssignablePerNoteController, group, channel, key, index, value)
+}
+
+// GetPerNotePitchbend returns true if (and only if) the message is a MIDI 2.0 per-note pitch bend message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetPerNotePitchbend(group, channel, key *uint8, value *uint32) (is bool) {
+	return m.getPerNoteController(opPerNotePitchBend, group, channel, key, nil, value)
+}
+
+// GetPerNoteManagement returns true if (and only if) the message is a MIDI 2.0 per-note management message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetPerNoteManagement(group, channel, key *uint8, detach, reset *bool) (is bool) {
+	if !m.getChannelVoice(opPerNoteManagement, group, channel) {
+		return false
+	}
+
+	if key != nil {
+		*key = m.byte3() & 0x7F
+	}
+
+	if detach != nil {
+		*detach = m.byte4()&0x02 != 0
+	}
+
+	if reset != nil {
+		*reset = m.byte4()&0x01 != 0
+	}
+	return true
+}
+
+// GetProgramChange returns true if (and only if) the message is a MIDI 2.0 ProgramChange message.
+// Then it also extracts the data to the given arguments. bankValid reports, if the message also selects a bank.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetProgramChange(group, channel, program *uint8, bankValid *bool, bankMSB, bankLSB *uint8) (is bool) {
+	if !m.getChannelVoice(opProgramChange, group, channel) {
+		return false
+	}
+
+	if program != nil {
+		*program = uint8(m[1]>>24) & 0x7F
+	}
+
+	if bankValid != nil {
+		*bankValid = m.byte4()&0x01 != 0
+	}
+
+	if bankMSB != nil {
+		*bankMSB = uint8(m[1]>>8) & 0x7F
+	}
+
+	if bankLSB != nil {
+		*bankLSB = uint8(m[1]) & 0x7F
+	}
+	return true
+}
+
+// GetAfterTouch returns true if (and only if) the message is a MIDI 2.0 AfterTouch (channel pressure) message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetAfterTouch(group, channel *uint8, pressure *uint32) (is bool) {
+	if !m.getChannelVoice(opChannelPressure, group, channel) {
+		return false
+	}
+
+	if pressure != nil {
+		*pressure = m[1]
+	}
+	return true
+}
+
+// GetPitchBend returns true if (and only if) the message is a MIDI 2.0 PitchBend message.
+// Then it also extracts the data to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetPitchBend(group, channel *uint8, value *uint32) (is bool) {
+	if !m.getChannelVoice(opPitchBend, group, channel) {
+		return false
+	}
+
+	if value != nil {
+		*value = m[1]
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2022 Marc René Arns. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+/*
+Package ump helps with reading and writing of MIDI 2.0 Universal MIDI Packets (UMP).
+
+A UMP `Message` is a slice of one to four 32-bit words. The first 4 bits of the first word define the message type
+and by that the size of the packet. The next 4 bits define the group (0-15), which is an additional addressing
+layer on top of the 16 channels.
+
+The data can be retrieved with the corresponding Get* method, which mirror the ones of midi.Message,
+but take the group as the first argument.
+
+	msg := ump.NoteOn(0, 1, 60, 0xC000) // group, channel, key, velocity (16-bit)
+
+	var group, channel, key uint8
+	var velocity uint16
+	if msg.GetNoteOn(&group, &channel, &key, &velocity) {
+	  fmt.Printf("got %s: group: %v channel: %v key: %v, velocity: %v\n", msg.Type(), group, channel, key, velocity)
+	}
+
+MIDI 1.0 messages (midi.Message) can be carried inside UMPs in two ways:
+
+  - Wrap packs them as they are (MIDI 1.0 protocol inside UMP), which is lossless.
+  - Translator.FromMIDI1 upscales them to MIDI 2.0 channel voice messages (e.g. 16-bit velocities and 32-bit controller values),
+    following the default translation of the MIDI 2.0 specification.
+
+Translator.ToMIDI1 converts any UMP back to midi.Message values.
+*/
+package ump
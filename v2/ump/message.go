@@ -0,0 +1,172 @@
+package ump
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// Message is a Universal MIDI Packet, consisting of one to four 32-bit words.
+type Message []uint32
+
+// MessageType is the type of a Universal MIDI Packet (the first 4 bits of the first word).
+type MessageType uint8
+
+const (
+	// UtilityMsg is a 32-bit utility message (NOOP, jitter reduction etc.)
+	UtilityMsg MessageType = 0x0
+
+	// SystemMsg is a 32-bit system realtime or system common message.
+	SystemMsg MessageType = 0x1
+
+	// MIDI1ChannelVoiceMsg is a 32-bit MIDI 1.0 channel voice message.
+	MIDI1ChannelVoiceMsg MessageType = 0x2
+
+	// SysEx7Msg is a 64-bit data message carrying 7-bit system exclusive data.
+	SysEx7Msg MessageType = 0x3
+
+	// MIDI2ChannelVoiceMsg is a 64-bit MIDI 2.0 channel voice message.
+	MIDI2ChannelVoiceMsg MessageType = 0x4
+
+	// SysEx8Msg is a 128-bit data message carrying 8-bit system exclusive data or mixed data sets.
+	SysEx8Msg MessageType = 0x5
+
+	// FlexDataMsg is a 128-bit flex data message.
+	FlexDataMsg MessageType = 0xD
+
+	// StreamMsg is a 128-bit UMP stream message.
+	StreamMsg MessageType = 0xF
+)
+
+var messageTypeNames = map[MessageType]string{
+	UtilityMsg:           "Utility",
+	SystemMsg:            "System",
+	MIDI1ChannelVoiceMsg: "MIDI1ChannelVoice",
+	SysEx7Msg:            "SysEx7",
+	MIDI2ChannelVoiceMsg: "MIDI2ChannelVoice",
+	SysEx8Msg:            "SysEx8",
+	FlexDataMsg:          "FlexData",
+	StreamMsg:            "Stream",
+}
+
+// String returns the name of the message type.
+func (t MessageType) String() string {
+	if s, has := messageTypeNames[t]; has {
+		return s
+	}
+	return fmt.Sprintf("reserved%v", uint8(t))
+}
+
+// Words returns the number of 32-bit words of a packet of the message type.
+func (t MessageType) Words() int {
+	switch t & 0xF {
+	case 0x0, 0x1, 0x2, 0x6, 0x7:
+		return 1
+	case 0x3, 0x4, 0x8, 0x9, 0xA:
+		return 2
+	case 0xB, 0xC:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Type returns the message type of the packet.
+func (m Message) Type() MessageType {
+	if len(m) == 0 {
+		return UtilityMsg
+	}
+	return MessageType(m[0] >> 28)
+}
+
+// Group returns the group (0-15) of the packet.
+func (m Message) Group() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+	return uint8(m[0]>>24) & 0xF
+}
+
+// status returns the status byte of the packet (the second byte of the first word).
+func (m Message) status() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+	return uint8(m[0] >> 16)
+}
+
+// byte3 returns the third byte of the first word.
+func (m Message) byte3() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+	return uint8(m[0] >> 8)
+}
+
+// byte4 returns the fourth byte of the first word.
+func (m Message) byte4() uint8 {
+	if len(m) == 0 {
+		return 0
+	}
+	return uint8(m[0])
+}
+
+// IsValid returns true, if the number of words matches the message type.
+func (m Message) IsValid() bool {
+	return len(m) > 0 && len(m) == m.Type().Words()
+}
+
+// Bytes returns the packet as bytes in big endian order.
+func (m Message) Bytes() []byte {
+	var b = make([]byte, len(m)*4)
+	for i, w := range m {
+		binary.BigEndian.PutUint32(b[i*4:], w)
+	}
+	return b
+}
+
+// String represents the Message as a string that contains the type, the group and the words.
+func (m Message) String() string {
+	var bf bytes.Buffer
+	fmt.Fprintf(&bf, "%s group: %v", m.Type(), m.Group())
+
+	for _, w := range m {
+		fmt.Fprintf(&bf, " %08X", w)
+	}
+
+	return bf.String()
+}
+
+// word0 builds the first word out of its four bytes.
+func word0(mt MessageType, group, status, b3, b4 uint8) uint32 {
+	return uint32(mt&0xF)<<28 | uint32(group&0xF)<<24 | uint32(status)<<16 | uint32(b3)<<8 | uint32(b4)
+}
+
+// Parse splits the given words into packets.
+// It returns an error, if the last packet is incomplete.
+func Parse(words []uint32) (msgs []Message, err error) {
+	for len(words) > 0 {
+		n := MessageType(words[0] >> 28).Words()
+		if len(words) < n {
+			return msgs, fmt.Errorf("incomplete packet: got %v words, need %v", len(words), n)
+		}
+		msgs = append(msgs, Message(words[:n:n]))
+		words = words[n:]
+	}
+	return msgs, nil
+}
+
+// ParseBytes splits the given bytes (in big endian order) into packets.
+// It returns an error, if the bytes are not a multiple of 4 or if the last packet is incomplete.
+func ParseBytes(b []byte) ([]Message, error) {
+	if len(b)%4 != 0 {
+		return nil, fmt.Errorf("invalid length %v: must be a multiple of 4", len(b))
+	}
+
+	var words = make([]uint32, len(b)/4)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint32(b[i*4:])
+	}
+
+	return Parse(words)
+}
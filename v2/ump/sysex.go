@@ -0,0 +1,146 @@
+package ump
+
+// status of a sysex packet within a (possibly multi packet) system exclusive message
+const (
+	SysExComplete uint8 = 0x0
+	SysExStart    uint8 = 0x1
+	SysExContinue uint8 = 0x2
+	SysExEnd      uint8 = 0x3
+)
+
+func sysexStatus(i, n int) uint8 {
+	switch {
+	case n == 1:
+		return SysExComplete
+	case i == 0:
+		return SysExStart
+	case i == n-1:
+		return SysExEnd
+	default:
+		return SysExContinue
+	}
+}
+
+// SysEx7 returns the 64-bit packets for the given system exclusive data.
+// Only the inner bytes must be passed (without the starting 0xF0 and the ending 0xF7).
+// Every packet carries up to 6 bytes.
+func SysEx7(group uint8, data []byte) (msgs []Message) {
+	n := (len(data) + 5) / 6
+	if n == 0 {
+		n = 1
+	}
+
+	for i := 0; i < n; i++ {
+		var chunk []byte
+		if len(data) > 6 {
+			chunk, data = data[:6], data[6:]
+		} else {
+			chunk, data = data, nil
+		}
+
+		var b [6]byte
+		copy(b[:], chunk)
+
+		msgs = append(msgs, Message{
+			word0(SysEx7Msg, group, sysexStatus(i, n)<<4|uint8(len(chunk)), b[0]&0x7F, b[1]&0x7F),
+			uint32(b[2]&0x7F)<<24 | uint32(b[3]&0x7F)<<16 | uint32(b[4]&0x7F)<<8 | uint32(b[5]&0x7F),
+		})
+	}
+	return msgs
+}
+
+// GetSysEx7 returns true if (and only if) the message is a 7-bit system exclusive packet.
+// Then it also extracts the data to the given arguments. The status is one of SysExComplete, SysExStart,
+// SysExContinue or SysExEnd.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetSysEx7(group, status *uint8, data *[]byte) (is bool) {
+	if len(m) != 2 || m.Type() != SysEx7Msg {
+		return false
+	}
+
+	if group != nil {
+		*group = m.Group()
+	}
+
+	if status != nil {
+		*status = m.status() >> 4
+	}
+
+	if data != nil {
+		n := int(m.status() & 0x0F)
+		if n > 6 {
+			n = 6
+		}
+		b := []byte{m.byte3(), m.byte4(), byte(m[1] >> 24), byte(m[1] >> 16), byte(m[1] >> 8), byte(m[1])}
+		*data = b[:n]
+	}
+	return true
+}
+
+// SysEx8 returns the 128-bit packets for the given 8-bit system exclusive data.
+// Every packet carries the stream id and up to 13 bytes.
+func SysEx8(group, streamID uint8, data []byte) (msgs []Message) {
+	n := (len(data) + 12) / 13
+	if n == 0 {
+		n = 1
+	}
+
+	for i := 0; i < n; i++ {
+		var chunk []byte
+		if len(data) > 13 {
+			chunk, data = data[:13], data[13:]
+		} else {
+			chunk, data = data, nil
+		}
+
+		var b [13]byte
+		copy(b[:], chunk)
+
+		msgs = append(msgs, Message{
+			word0(SysEx8Msg, group, sysexStatus(i, n)<<4|uint8(len(chunk)+1), streamID, b[0]),
+			uint32(b[1])<<24 | uint32(b[2])<<16 | uint32(b[3])<<8 | uint32(b[4]),
+			uint32(b[5])<<24 | uint32(b[6])<<16 | uint32(b[7])<<8 | uint32(b[8]),
+			uint32(b[9])<<24 | uint32(b[10])<<16 | uint32(b[11])<<8 | uint32(b[12]),
+		})
+	}
+	return msgs
+}
+
+// GetSysEx8 returns true if (and only if) the message is a 8-bit system exclusive packet.
+// Then it also extracts the data to the given arguments. The status is one of SysExComplete, SysExStart,
+// SysExContinue or SysExEnd.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetSysEx8(group, status, streamID *uint8, data *[]byte) (is bool) {
+	if len(m) != 4 || m.Type() != SysEx8Msg || m.status()>>4 > SysExEnd {
+		return false
+	}
+
+	if group != nil {
+		*group = m.Group()
+	}
+
+	if status != nil {
+		*status = m.status() >> 4
+	}
+
+	if streamID != nil {
+		*streamID = m.byte3()
+	}
+
+	if data != nil {
+		n := int(m.status()&0x0F) - 1
+		if n < 0 {
+			n = 0
+		}
+		if n > 13 {
+			n = 13
+		}
+		b := make([]byte, 0, 13)
+		b = append(b, m.byte4())
+		for _, w := range m[1:] {
+			b = append(b, byte(w>>24), byte(w>>16), byte(w>>8), byte(w))
+		}
+		*data = b[:n]
+	}
+	return true
+}
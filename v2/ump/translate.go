@@ -0,0 +1,417 @@
+package ump
+
+import (
+	"gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/internal/utils"
+	"gitlab.com/gomidi/midi/v2/nrpn"
+	"gitlab.com/gomidi/midi/v2/rpn"
+)
+
+// ScaleUp scales the given value of srcBits resolution up to dstBits resolution, using the
+// min-center-max algorithm of the MIDI 2.0 specification, so that the minimum, the center and the maximum
+// values are preserved.
+func ScaleUp(value uint32, srcBits, dstBits uint8) uint32 {
+	if srcBits >= dstBits {
+		return value
+	}
+
+	scaleBits := dstBits - srcBits
+
+	if srcBits == 1 {
+		if value == 0 {
+			return 0
+		}
+		return uint32(uint64(1)<<dstBits - 1)
+	}
+
+	shifted := value << scaleBits
+	center := uint32(1) << (srcBits - 1)
+
+	if value <= center {
+		return shifted
+	}
+
+	repeatBits := srcBits - 1
+	repeatMask := uint32(1)<<repeatBits - 1
+	repeat := value & repeatMask
+
+	if scaleBits > repeatBits {
+		repeat <<= scaleBits - repeatBits
+	} else {
+		repeat >>= repeatBits - scaleBits
+	}
+
+	for repeat != 0 {
+		shifted |= repeat
+		repeat >>= repeatBits
+	}
+
+	return shifted
+}
+
+// ScaleDown scales the given value of srcBits resolution down to dstBits resolution.
+func ScaleDown(value uint32, srcBits, dstBits uint8) uint32 {
+	if srcBits <= dstBits {
+		return value
+	}
+	return value >> (srcBits - dstBits)
+}
+
+// Wrap packs the given MIDI 1.0 message into Universal MIDI Packets of the MIDI 1.0 protocol for the given group.
+// Channel messages become MIDI1ChannelVoiceMsg packets, realtime and system common messages become SystemMsg
+// packets and sysex messages become SysEx7Msg packets. This is lossless.
+// For unknown messages nil is returned.
+func Wrap(group uint8, msg midi.Message) []Message {
+	var data []byte
+
+	switch {
+	case msg.Is(midi.ChannelMsg):
+		return []Message{{word0(MIDI1ChannelVoiceMsg, group, msg[0], dataByte(msg, 1), dataByte(msg, 2))}}
+	case msg.GetSysEx(&data):
+		return SysEx7(group, data)
+	case msg.Is(midi.RealTimeMsg), msg.Is(midi.SysCommonMsg):
+		return []Message{{word0(SystemMsg, group, msg[0], dataByte(msg, 1), dataByte(msg, 2))}}
+	default:
+		return nil
+	}
+}
+
+func dataByte(msg midi.Message, i int) byte {
+	if len(msg) <= i {
+		return 0
+	}
+	return msg[i] & 0x7F
+}
+
+const (
+	paramNone = iota
+	paramRPN
+	paramNRPN
+)
+
+// channelState is the translation state of a single channel within a group
+type channelState struct {
+	bankValid bool
+	bankMSB   uint8
+	bankLSB   uint8
+
+	param            int
+	rpnMSB, rpnLSB   uint8
+	nrpnMSB, nrpnLSB uint8
+	dataMSB, dataLSB uint8
+}
+
+// Translator translates between MIDI 1.0 messages and Universal MIDI Packets.
+// Since bank selection, RPN / NRPN sequences and multi packet sysex messages span multiple messages,
+// the translator keeps track of the state per group and channel.
+// A Translator is not threadsafe.
+type Translator struct {
+	channels [16][16]channelState
+	sysex    [16][]byte
+}
+
+// NewTranslator returns a new Translator
+func NewTranslator() *Translator {
+	return &Translator{}
+}
+
+// FromMIDI1 translates the given MIDI 1.0 message to MIDI 2.0 protocol packets for the given group
+// (default translation of the MIDI 2.0 specification):
+//
+//   - velocities, pressures, controller values and pitch bend are scaled up to 16 or 32-bit
+//   - a note on with a velocity of 0 becomes a note off with a velocity of VelocityDefault
+//   - bank select controllers are combined with the following program change
+//   - RPN / NRPN sequences become registered and assignable controller messages
+//
+// Messages that are only changing the state of the translator (e.g. bank select) return nil.
+// Non channel messages are wrapped (see Wrap).
+func (t *Translator) FromMIDI1(group uint8, msg midi.Message) []Message {
+	var ch, key, val, ctl uint8
+	var abs uint16
+
+	group &= 0xF
+
+	switch {
+	case msg.GetNoteStart(&ch, &key, &val):
+		return []Message{NoteOn(group, ch, key, uint16(ScaleUp(uint32(val), 7, 16)))}
+	case msg.GetNoteOn(&ch, &key, &val):
+		return []Message{NoteOff(group, ch, key, VelocityDefault)}
+	case msg.GetNoteOff(&ch, &key, &val):
+		return []Message{NoteOff(group, ch, key, uint16(ScaleUp(uint32(val), 7, 16)))}
+	case msg.GetPolyAfterTouch(&ch, &key, &val):
+		return []Message{PolyAfterTouch(group, ch, key, ScaleUp(uint32(val), 7, 32))}
+	case msg.GetAfterTouch(&ch, &val):
+		return []Message{AfterTouch(group, ch, ScaleUp(uint32(val), 7, 32))}
+	case msg.GetPitchBend(&ch, nil, &abs):
+		return []Message{Pitchbend(group, ch, ScaleUp(uint32(abs), 14, 32))}
+	case msg.GetProgramChange(&ch, &val):
+		st := &t.channels[group][ch]
+		if st.bankValid {
+			return []Message{ProgramChangeWithBank(group, ch, val, st.bankMSB, st.bankLSB)}
+		}
+		return []Message{ProgramChange(group, ch, val)}
+	case msg.GetControlChange(&ch, &ctl, &val):
+		return t.controlChange(group, ch, ctl, val)
+	default:
+		return Wrap(group, msg)
+	}
+}
+
+func (t *Translator) controlChange(group, ch, ctl, val uint8) []Message {
+	st := &t.channels[group][ch]
+
+	switch ctl {
+	case midi.BankSelectMSB:
+		st.bankValid = true
+		st.bankMSB = val
+		return nil
+	case midi.BankSelectLSB:
+		st.bankValid = true
+		st.bankLSB = val
+		return nil
+	case midi.RegisteredParameterMSB:
+		st.param = paramRPN
+		st.rpnMSB = val
+		st.checkNull()
+		return nil
+	case midi.RegisteredParameterLSB:
+		st.param = paramRPN
+		st.rpnLSB = val
+		st.checkNull()
+		return nil
+	case midi.NonRegisteredParameterMSB:
+		st.param = paramNRPN
+		st.nrpnMSB = val
+		st.checkNull()
+		return nil
+	case midi.NonRegisteredParameterLSB:
+		st.param = paramNRPN
+		st.nrpnLSB = val
+		st.checkNull()
+		return nil
+	case midi.DataEntryMSB, midi.DataEntryLSB:
+		if st.param == paramNone {
+			break
+		}
+		if ctl == midi.DataEntryMSB {
+			st.dataMSB = val
+			st.dataLSB = 0
+		} else {
+			st.dataLSB = val
+		}
+		v := ScaleUp(uint32(st.dataMSB)<<7|uint32(st.dataLSB), 14, 32)
+		if st.param == paramRPN {
+			return []Message{RegisteredController(group, ch, st.rpnMSB, st.rpnLSB, v)}
+		}
+		return []Message{AssignableController(group, ch, st.nrpnMSB, st.nrpnLSB, v)}
+	case midi.DataButtonIncrement, midi.DataButtonDecrement:
+		if st.param == paramNone {
+			break
+		}
+		// one step of the 14-bit resolution
+		var v int32 = 1 << 18
+		if ctl == midi.DataButtonDecrement {
+			v = -v
+		}
+		if st.param == paramRPN {
+			return []Message{RelativeRegisteredController(group, ch, st.rpnMSB, st.rpnLSB, v)}
+		}
+		return []Message{RelativeAssignableController(group, ch, st.nrpnMSB, st.nrpnLSB, v)}
+	}
+
+	return []Message{ControlChange(group, ch, ctl, ScaleUp(uint32(val), 7, 32))}
+}
+
+// checkNull deselects the parameter, if the null RPN / NRPN (127/127) was selected
+func (st *channelState) checkNull() {
+	switch st.param {
+	case paramRPN:
+		if st.rpnMSB == 127 && st.rpnLSB == 127 {
+			st.param = paramNone
+		}
+	case paramNRPN:
+		if st.nrpnMSB == 127 && st.nrpnLSB == 127 {
+			st.param = paramNone
+		}
+	}
+}
+
+// ToMIDI1 translates the given Universal MIDI Packet to MIDI 1.0 messages. The group is lost.
+// MIDI 2.0 channel voice messages are scaled down; registered and assignable controllers become RPN and NRPN
+// sequences and a program change with a bank becomes a bank select followed by a program change.
+// Multi packet 7-bit sysex messages are collected and returned, when the last packet has been received.
+// Messages that have no equivalent in MIDI 1.0 (e.g. per-note controllers, utility messages and 8-bit sysex)
+// return nil.
+func (t *Translator) ToMIDI1(msg Message) []midi.Message {
+	if !msg.IsValid() {
+		return nil
+	}
+
+	switch msg.Type() {
+	case SystemMsg:
+		return systemToMIDI1(msg)
+	case MIDI1ChannelVoiceMsg:
+		var m midi.Message
+		if msg.GetMIDI1ChannelVoice(nil, &m) {
+			return []midi.Message{m}
+		}
+		return nil
+	case SysEx7Msg:
+		return t.sysexToMIDI1(msg)
+	case MIDI2ChannelVoiceMsg:
+		return channelVoiceToMIDI1(msg)
+	default:
+		return nil
+	}
+}
+
+// GetMIDI1ChannelVoice returns true if (and only if) the message is a MIDI 1.0 channel voice message.
+// Then it also extracts the group and the MIDI 1.0 message to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetMIDI1ChannelVoice(group *uint8, msg *midi.Message) (is bool) {
+	if len(m) != 1 || m.Type() != MIDI1ChannelVoiceMsg || m.status() < 0x80 || m.status() >= 0xF0 {
+		return false
+	}
+
+	if group != nil {
+		*group = m.Group()
+	}
+
+	if msg != nil {
+		typ, _ := utils.ParseStatus(m.status())
+		switch typ {
+		case 0xC, 0xD:
+			*msg = midi.Message{m.status(), m.byte3() & 0x7F}
+		default:
+			*msg = midi.Message{m.status(), m.byte3() & 0x7F, m.byte4() & 0x7F}
+		}
+	}
+	return true
+}
+
+// GetSystem returns true if (and only if) the message is a system realtime or system common message.
+// Then it also extracts the group and the MIDI 1.0 message to the given arguments.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetSystem(group *uint8, msg *midi.Message) (is bool) {
+	if len(m) != 1 || m.Type() != SystemMsg || m.status() <= 0xF0 || m.status() == 0xF7 {
+		return false
+	}
+
+	if group != nil {
+		*group = m.Group()
+	}
+
+	if msg != nil {
+		switch m.status() {
+		case 0xF1, 0xF3:
+			*msg = midi.Message{m.status(), m.byte3() & 0x7F}
+		case 0xF2:
+			*msg = midi.Message{m.status(), m.byte3() & 0x7F, m.byte4() & 0x7F}
+		default:
+			*msg = midi.Message{m.status()}
+		}
+	}
+	return true
+}
+
+func systemToMIDI1(msg Message) []midi.Message {
+	var m midi.Message
+	if msg.GetSystem(nil, &m) {
+		return []midi.Message{m}
+	}
+	return nil
+}
+
+func (t *Translator) sysexToMIDI1(msg Message) []midi.Message {
+	var group, status uint8
+	var data []byte
+
+	if !msg.GetSysEx7(&group, &status, &data) {
+		return nil
+	}
+
+	switch status {
+	case SysExComplete:
+		t.sysex[group] = nil
+		return []midi.Message{midi.SysEx(data)}
+	case SysExStart:
+		t.sysex[group] = append([]byte{}, data...)
+		return nil
+	case SysExContinue:
+		if t.sysex[group] == nil {
+			return nil
+		}
+		t.sysex[group] = append(t.sysex[group], data...)
+		return nil
+	case SysExEnd:
+		if t.sysex[group] == nil {
+			return nil
+		}
+		complete := append(t.sysex[group], data...)
+		t.sysex[group] = nil
+		return []midi.Message{midi.SysEx(complete)}
+	default:
+		return nil
+	}
+}
+
+func channelVoiceToMIDI1(msg Message) []midi.Message {
+	var ch, key, bank, index, prog, msb, lsb uint8
+	var vel uint16
+	var val uint32
+	var rel int32
+	var bankValid bool
+
+	switch {
+	case msg.GetNoteOn(nil, &ch, &key, &vel):
+		v := uint8(ScaleDown(uint32(vel), 16, 7))
+		// a MIDI 2.0 note on never means a note off
+		if v == 0 {
+			v = 1
+		}
+		return []midi.Message{midi.NoteOn(ch, key, v)}
+	case msg.GetNoteOff(nil, &ch, &key, &vel):
+		return []midi.Message{midi.NoteOffVelocity(ch, key, uint8(ScaleDown(uint32(vel), 16, 7)))}
+	case msg.GetPolyAfterTouch(nil, &ch, &key, &val):
+		return []midi.Message{midi.PolyAfterTouch(ch, key, uint8(ScaleDown(val, 32, 7)))}
+	case msg.GetControlChange(nil, &ch, &index, &val):
+		return []midi.Message{midi.ControlChange(ch, index, uint8(ScaleDown(val, 32, 7)))}
+	case msg.GetRegisteredController(nil, &ch, &bank, &index, &val):
+		v := ScaleDown(val, 32, 14)
+		return rpn.RPN(ch, bank, index, uint8(v>>7), uint8(v&0x7F))
+	case msg.GetAssignableController(nil, &ch, &bank, &index, &val):
+		v := ScaleDown(val, 32, 14)
+		return nrpn.NRPN(ch, bank, index, uint8(v>>7), uint8(v&0x7F))
+	case msg.GetRelativeRegisteredController(nil, &ch, &bank, &index, &rel):
+		switch {
+		case rel > 0:
+			return rpn.Increment(ch, bank, index)
+		case rel < 0:
+			return rpn.Decrement(ch, bank, index)
+		}
+		return nil
+	case msg.GetRelativeAssignableController(nil, &ch, &bank, &index, &rel):
+		switch {
+		case rel > 0:
+			return nrpn.Increment(ch, bank, index)
+		case rel < 0:
+			return nrpn.Decrement(ch, bank, index)
+		}
+		return nil
+	case msg.GetProgramChange(nil, &ch, &prog, &bankValid, &msb, &lsb):
+		if bankValid {
+			return []midi.Message{
+				midi.ControlChange(ch, midi.BankSelectMSB, msb),
+				midi.ControlChange(ch, midi.BankSelectLSB, lsb),
+				midi.ProgramChange(ch, prog),
+			}
+		}
+		return []midi.Message{midi.ProgramChange(ch, prog)}
+	case msg.GetAfterTouch(nil, &ch, &val):
+		return []midi.Message{midi.AfterTouch(ch, uint8(ScaleDown(val, 32, 7)))}
+	case msg.GetPitchBend(nil, &ch, &val):
+		return []midi.Message{midi.Pitchbend(ch, int16(ScaleDown(val, 32, 14))-8192)}
+	default:
+		return nil
+	}
+}
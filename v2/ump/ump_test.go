@@ -0,0 +1,224 @@
+package ump
+
+import (
+	"fmt"
+	"testing"
+
+	"gitlab.com/gomidi/midi/v2"
+)
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		value    uint32
+		src, dst uint8
+		expected uint32
+	}{
+		{0, 7, 16, 0},
+		{64, 7, 16, 0x8000},
+		{127, 7, 16, 0xFFFF},
+		{1, 7, 16, 0x0200},
+		{0, 7, 32, 0},
+		{64, 7, 32, 0x80000000},
+		{127, 7, 32, 0xFFFFFFFF},
+		{8192, 14, 32, 0x80000000},
+		{16383, 14, 32, 0xFFFFFFFF},
+		{1, 1, 16, 0xFFFF},
+	}
+
+	for n, test := range tests {
+		if got, want := ScaleUp(test.value, test.src, test.dst), test.expected; got != want {
+			t.Errorf("[%v] ScaleUp(%v, %v, %v) = %08X; want %08X", n, test.value, test.src, test.dst, got, want)
+		}
+
+		if got, want := ScaleDown(test.expected, test.dst, test.src), test.value; test.src > 1 && got != want {
+			t.Errorf("[%v] ScaleDown(%08X, %v, %v) = %v; want %v", n, test.expected, test.dst, test.src, got, want)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	tests := []struct {
+		msg      Message
+		expected string
+	}{
+		{NOOP(), "Utility group: 0 00000000"},
+		{JRTimestamp(0x1234), "Utility group: 0 00201234"},
+		{NoteOn(1, 2, 60, 0xC000), "MIDI2ChannelVoice group: 1 41923C00 C0000000"},
+		{NoteOffWithAttribute(0, 3, 61, 0x8000, 3, 0x0102), "MIDI2ChannelVoice group: 0 40833D03 80000102"},
+		{ControlChange(0, 0, 7, 0x12345678), "MIDI2ChannelVoice group: 0 40B00700 12345678"},
+		{ProgramChangeWithBank(2, 1, 5, 1, 2), "MIDI2ChannelVoice group: 2 42C10001 05000102"},
+		{Pitchbend(0, 0, PitchReset), "MIDI2ChannelVoice group: 0 40E00000 80000000"},
+		{PerNoteManagement(0, 0, 60, true, true), "MIDI2ChannelVoice group: 0 40F03C03 00000000"},
+		{Wrap(3, midi.NoteOn(1, 60, 100))[0], "MIDI1ChannelVoice group: 3 23913C64"},
+		{Wrap(0, midi.SPP(300))[0], "System group: 0 10F2022C"},
+	}
+
+	for n, test := range tests {
+		if got, want := test.msg.String(), test.expected; got != want {
+			t.Errorf("[%v] String() = %q; want %q", n, got, want)
+		}
+	}
+}
+
+func TestGetters(t *testing.T) {
+	var group, ch, key, bank, index, prog, msb, lsb uint8
+	var vel uint16
+	var val uint32
+	var bankValid bool
+
+	if !NoteOn(4, 5, 62, 0x1234).GetNoteOn(&group, &ch, &key, &vel) || group != 4 || ch != 5 || key != 62 || vel != 0x1234 {
+		t.Errorf("GetNoteOn: %v %v %v %04X", group, ch, key, vel)
+	}
+
+	if NoteOn(0, 0, 60, 100).GetNoteOff(nil, nil, nil, nil) {
+		t.Errorf("NoteOn must not be a NoteOff")
+	}
+
+	if !RegisteredController(1, 2, 0, 1, 0xABCDEF01).GetRegisteredController(&group, &ch, &bank, &index, &val) ||
+		group != 1 || ch != 2 || bank != 0 || index != 1 || val != 0xABCDEF01 {
+		t.Errorf("GetRegisteredController: %v %v %v %v %08X", group, ch, bank, index, val)
+	}
+
+	if !ProgramChangeWithBank(0, 1, 5, 1, 2).GetProgramChange(nil, &ch, &prog, &bankValid, &msb, &lsb) ||
+		ch != 1 || prog != 5 || !bankValid || msb != 1 || lsb != 2 {
+		t.Errorf("GetProgramChange: %v %v %v %v %v", ch, prog, bankValid, msb, lsb)
+	}
+
+	// the accessors must not panic on an empty message
+	var empty Message
+	var msg midi.Message
+	var data []byte
+
+	if empty.GetNoteOn(nil, nil, nil, nil) || empty.GetSysEx7(nil, nil, &data) || empty.GetSysEx8(nil, nil, nil, &data) ||
+		empty.GetMIDI1ChannelVoice(nil, &msg) || empty.GetSystem(nil, &msg) || empty.IsNOOP() || empty.IsValid() {
+		t.Errorf("empty message must not match")
+	}
+
+	if empty.status() != 0 || empty.byte3() != 0 || empty.byte4() != 0 {
+		t.Errorf("empty message: status %v, byte3 %v, byte4 %v", empty.status(), empty.byte3(), empty.byte4())
+	}
+
+	_ = empty.String()
+}
+
+func TestParse(t *testing.T) {
+	var words []uint32
+	words = append(words, NOOP()...)
+	words = append(words, NoteOn(0, 0, 60, 100)...)
+	words = append(words, SysEx8(0, 1, []byte{1, 2, 3})[0]...)
+
+	msgs, err := Parse(words)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	if len(msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %v", len(msgs))
+	}
+
+	_, err = Parse(words[:len(words)-1])
+	if err == nil {
+		t.Errorf("expected error for incomplete packet")
+	}
+
+	msgs, err = ParseBytes(NoteOn(0, 0, 60, 100).Bytes())
+	if err != nil || len(msgs) != 1 || !msgs[0].GetNoteOn(nil, nil, nil, nil) {
+		t.Errorf("ParseBytes: %v %v", msgs, err)
+	}
+}
+
+func TestSysEx(t *testing.T) {
+	data := []byte{0x7E, 0x7F, 0x09, 0x01, 0x10, 0x11, 0x12, 0x13}
+
+	pkts := SysEx7(0, data)
+	if len(pkts) != 2 {
+		t.Fatalf("expected 2 packets, got %v", len(pkts))
+	}
+
+	tr := NewTranslator()
+	var res []midi.Message
+	for _, p := range pkts {
+		res = append(res, tr.ToMIDI1(p)...)
+	}
+
+	if len(res) != 1 {
+		t.Fatalf("expected 1 message, got %v", len(res))
+	}
+
+	if got, want := fmt.Sprintf("% X", []byte(res[0])), "F0 7E 7F 09 01 10 11 12 13 F7"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	var status, stream uint8
+	var bt []byte
+	pkts = SysEx8(2, 9, []byte{0xFF, 0x80, 0x01})
+	if len(pkts) != 1 || !pkts[0].GetSysEx8(nil, &status, &stream, &bt) || status != SysExComplete || stream != 9 || fmt.Sprintf("% X", bt) != "FF 80 01" {
+		t.Errorf("GetSysEx8: %v %v % X", status, stream, bt)
+	}
+}
+
+func TestTranslation(t *testing.T) {
+	tests := []struct {
+		input    []midi.Message
+		expected string
+	}{
+		{
+			[]midi.Message{midi.NoteOn(1, 60, 64)},
+			"MIDI2ChannelVoice group: 0 40913C00 80000000 -> 91 3C 40",
+		},
+		{
+			[]midi.Message{midi.NoteOn(1, 60, 0)},
+			"MIDI2ChannelVoice group: 0 40813C00 80000000 -> 81 3C 40",
+		},
+		{
+			[]midi.Message{midi.Pitchbend(2, 0)},
+			"MIDI2ChannelVoice group: 0 40E20000 80000000 -> E2 00 40",
+		},
+		{
+			[]midi.Message{
+				midi.ControlChange(0, midi.BankSelectMSB, 1),
+				midi.ControlChange(0, midi.BankSelectLSB, 2),
+				midi.ProgramChange(0, 5),
+			},
+			"MIDI2ChannelVoice group: 0 40C00001 05000102 -> B0 00 01 | B0 20 02 | C0 05",
+		},
+		{
+			[]midi.Message{
+				midi.ControlChange(0, midi.RegisteredParameterMSB, 0),
+				midi.ControlChange(0, midi.RegisteredParameterLSB, 0),
+				midi.ControlChange(0, midi.DataEntryMSB, 12),
+			},
+			"MIDI2ChannelVoice group: 0 40200000 18000000 -> B0 65 00 | B0 64 00 | B0 06 0C | B0 26 00 | B0 65 7F | B0 64 7F",
+		},
+		{
+			[]midi.Message{midi.Start()},
+			"System group: 0 10FA0000 -> FA",
+		},
+	}
+
+	for n, test := range tests {
+		tr := NewTranslator()
+		var pkts []Message
+
+		for _, msg := range test.input {
+			pkts = append(pkts, tr.FromMIDI1(0, msg)...)
+		}
+
+		if len(pkts) != 1 {
+			t.Errorf("[%v] expected 1 packet, got %v", n, len(pkts))
+			continue
+		}
+
+		var got = pkts[0].String() + " ->"
+		for i, m := range tr.ToMIDI1(pkts[0]) {
+			if i > 0 {
+				got += " |"
+			}
+			got += fmt.Sprintf(" % X", []byte(m))
+		}
+
+		if got != test.expected {
+			t.Errorf("[%v]\ngot:  %q\nwant: %q", n, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ump
+
+const (
+	utilityNOOP        = 0x0
+	utilityJRClock     = 0x1
+	utilityJRTimestamp = 0x2
+)
+
+// NOOP returns a utility message that does nothing.
+func NOOP() Message {
+	return Message{word0(UtilityMsg, 0, utilityNOOP<<4, 0, 0)}
+}
+
+// JRClock returns a jitter reduction clock message with the given sender clock time
+// (in units of 1/31250 seconds).
+func JRClock(senderTime uint16) Message {
+	return Message{word0(UtilityMsg, 0, utilityJRClock<<4, uint8(senderTime>>8), uint8(senderTime))}
+}
+
+// JRTimestamp returns a jitter reduction timestamp message with the given sender clock timestamp
+// (in units of 1/31250 seconds).
+func JRTimestamp(timestamp uint16) Message {
+	return Message{word0(UtilityMsg, 0, utilityJRTimestamp<<4, uint8(timestamp>>8), uint8(timestamp))}
+}
+
+func (m Message) isUtility(status uint8) bool {
+	return len(m) == 1 && m.Type() == UtilityMsg && m.status()>>4 == status
+}
+
+// IsNOOP returns true, if the message is a NOOP utility message.
+func (m Message) IsNOOP() bool {
+	return m.isUtility(utilityNOOP)
+}
+
+// GetJRClock returns true if (and only if) the message is a jitter reduction clock message.
+// Then it also extracts the sender clock time to the given argument.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetJRClock(senderTime *uint16) (is bool) {
+	if !m.isUtility(utilityJRClock) {
+		return false
+	}
+
+	if senderTime != nil {
+		*senderTime = uint16(m[0])
+	}
+	return true
+}
+
+// GetJRTimestamp returns true if (and only if) the message is a jitter reduction timestamp message.
+// Then it also extracts the timestamp to the given argument.
+// Only arguments that are not nil are parsed and filled.
+func (m Message) GetJRTimestamp(timestamp *uint16) (is bool) {
+	if !m.isUtility(utilityJRTimestamp) {
+		return false
+	}
+
+	if timestamp != nil {
+		*timestamp = uint16(m[0])
+	}
+	return true
+}
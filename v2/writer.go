@@ -0,0 +1,156 @@
+package midi
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// ErrNoRawOut is the error when an out port should send data without status bytes (see RunningStatusOut),
+// but does not implement drivers.RawOut.
+var ErrNoRawOut = errors.New("out port does not implement drivers.RawOut")
+
+// WriterOption is an option for the Writer
+type WriterOption func(*Writer)
+
+// NoteOffAsNoteOn is an option to write note off messages as note on messages with a velocity of 0,
+// which allows longer running status sequences. The note off velocity is lost.
+func NoteOffAsNoteOn() WriterOption {
+	return func(w *Writer) {
+		w.noteOffAsNoteOn = true
+	}
+}
+
+// RefreshStatus is an option to write the full status byte again, if the last status byte was written
+// more than the given duration ago. That helps receivers that missed a status byte (e.g. when being connected later).
+func RefreshStatus(every time.Duration) WriterOption {
+	return func(w *Writer) {
+		w.refresh = every
+	}
+}
+
+// Writer writes complete messages to an io.Writer, while omitting repeated status bytes ("running status").
+// Realtime messages don't affect the running status, while sysex and system common messages reset it.
+// A Writer is not threadsafe.
+type Writer struct {
+	wr              io.Writer
+	status          byte
+	noteOffAsNoteOn bool
+	refresh         time.Duration
+	lastStatus      time.Time
+	now             func() time.Time
+}
+
+// NewWriter returns a Writer that writes to the given io.Writer.
+func NewWriter(wr io.Writer, opts ...WriterOption) *Writer {
+	w := &Writer{wr: wr, now: time.Now}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// ResetStatus resets the running status, so that the next channel message is written with its status byte.
+func (w *Writer) ResetStatus() {
+	w.status = 0
+}
+
+// Write writes the given message, which must be one complete message, with running status.
+// It returns len(msg), if there was no error.
+func (w *Writer) Write(msg []byte) (n int, err error) {
+	if len(msg) == 0 {
+		return 0, nil
+	}
+
+	m := Message(msg)
+
+	// realtime messages don't affect the running status
+	if m.Is(RealTimeMsg) {
+		return w.write(msg, len(msg))
+	}
+
+	// sysex and system common messages reset the running status
+	if !m.Is(ChannelMsg) {
+		w.status = 0
+		return w.write(msg, len(msg))
+	}
+
+	var channel, key, velocity uint8
+	if w.noteOffAsNoteOn && m.GetNoteOff(&channel, &key, &velocity) {
+		m = NoteOn(channel, key, 0)
+	}
+
+	if m[0] == w.status && (w.refresh <= 0 || w.now().Sub(w.lastStatus) < w.refresh) {
+		return w.write(m[1:], len(msg))
+	}
+
+	w.status = m[0]
+	w.lastStatus = w.now()
+	return w.write(m, len(msg))
+}
+
+func (w *Writer) write(b []byte, n int) (int, error) {
+	_, err := w.wr.Write(b)
+	if err != nil {
+		// we don't know what has been written, so the next message must have its status
+		w.status = 0
+		return 0, err
+	}
+	return n, nil
+}
+
+// rawWriter writes to the out port as it is, since the data may lack the status byte
+type rawWriter struct {
+	out drivers.RawOut
+}
+
+func (o rawWriter) Write(b []byte) (int, error) {
+	return len(b), o.out.SendRaw(b)
+}
+
+type runningStatusOut struct {
+	drivers.Out
+	raw drivers.RawOut
+	wr  *Writer
+}
+
+// Send sends the given message with running status.
+func (o *runningStatusOut) Send(data []byte) error {
+	_, err := o.wr.Write(data)
+	return err
+}
+
+// SendRaw sends the given bytes as they are and resets the running status.
+func (o *runningStatusOut) SendRaw(data []byte) error {
+	o.wr.ResetStatus()
+	return o.raw.SendRaw(data)
+}
+
+// Open opens the underlying port and resets the running status.
+func (o *runningStatusOut) Open() error {
+	o.wr.ResetStatus()
+	return o.Out.Open()
+}
+
+// Close closes the underlying port and resets the running status.
+func (o *runningStatusOut) Close() error {
+	o.wr.ResetStatus()
+	return o.Out.Close()
+}
+
+// RunningStatusOut wraps the given out port, so that every message is sent with running status (see Writer).
+// The messages passed to Send must be complete messages.
+// Since the drivers treat the data passed to Send as complete messages, the messages without status byte are sent
+// via drivers.RawOut. If the port does not implement it, ErrNoRawOut is returned.
+func RunningStatusOut(out drivers.Out, opts ...WriterOption) (drivers.Out, error) {
+	raw, ok := out.(drivers.RawOut)
+	if !ok {
+		return nil, ErrNoRawOut
+	}
+
+	return &runningStatusOut{Out: out, raw: raw, wr: NewWriter(rawWriter{raw}, opts...)}, nil
+}
@@ -0,0 +1,137 @@
+package midi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		descr    string
+		opts     []WriterOption
+		msgs     []Message
+		expected string
+	}{
+		{
+			"running status",
+			nil,
+			[]Message{NoteOn(2, 65, 120), NoteOn(2, 55, 120), NoteOn(2, 65, 0), NoteOn(1, 65, 20), NoteOn(1, 66, 20)},
+			"92 41 78 37 78 41 00 91 41 14 42 14",
+		},
+		{
+			"realtime does not affect status",
+			nil,
+			[]Message{NoteOn(2, 65, 120), TimingClock(), NoteOn(2, 55, 120)},
+			"92 41 78 F8 37 78",
+		},
+		{
+			"sysex and syscommon reset status",
+			nil,
+			[]Message{NoteOn(2, 65, 120), SysEx([]byte{0x7E}), NoteOn(2, 55, 120), Tune(), NoteOn(2, 56, 120)},
+			"92 41 78 F0 7E F7 92 37 78 F6 92 38 78",
+		},
+		{
+			"note off as note on",
+			[]WriterOption{NoteOffAsNoteOn()},
+			[]Message{NoteOn(2, 65, 120), NoteOffVelocity(2, 65, 30), NoteOff(2, 55)},
+			"92 41 78 41 00 37 00",
+		},
+		{
+			"note off without option",
+			nil,
+			[]Message{NoteOn(2, 65, 120), NoteOff(2, 65)},
+			"92 41 78 82 41 00",
+		},
+	}
+
+	for _, test := range tests {
+		var bf bytes.Buffer
+		wr := NewWriter(&bf, test.opts...)
+
+		for _, msg := range test.msgs {
+			n, err := wr.Write(msg)
+			if err != nil {
+				t.Fatalf("[%s] ERROR: %s", test.descr, err)
+			}
+			if n != len(msg) {
+				t.Errorf("[%s] Write returned %v, want %v", test.descr, n, len(msg))
+			}
+		}
+
+		if got, want := fmt.Sprintf("% X", bf.Bytes()), test.expected; got != want {
+			t.Errorf("[%s]\ngot:  %q\nwant: %q", test.descr, got, want)
+		}
+	}
+}
+
+func TestWriterRefreshStatus(t *testing.T) {
+	var bf bytes.Buffer
+	var now time.Time
+
+	wr := NewWriter(&bf, RefreshStatus(100*time.Millisecond))
+	wr.now = func() time.Time { return now }
+
+	wr.Write(NoteOn(0, 60, 100))
+	now = now.Add(50 * time.Millisecond)
+	wr.Write(NoteOn(0, 61, 100))
+	now = now.Add(60 * time.Millisecond)
+	wr.Write(NoteOn(0, 62, 100))
+	wr.Write(NoteOn(0, 63, 100))
+
+	if got, want := fmt.Sprintf("% X", bf.Bytes()), "90 3C 64 3D 64 90 3E 64 3F 64"; got != want {
+		t.Errorf("\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+// recordingOut records the data passed to Send and SendRaw
+type recordingOut struct {
+	drivers.Out
+	log strings.Builder
+}
+
+func (o *recordingOut) Send(b []byte) error {
+	fmt.Fprintf(&o.log, "Send % X\n", b)
+	return nil
+}
+
+func (o *recordingOut) SendRaw(b []byte) error {
+	fmt.Fprintf(&o.log, "SendRaw % X\n", b)
+	return nil
+}
+
+func TestRunningStatusOut(t *testing.T) {
+	rec := &recordingOut{}
+
+	out, err := RunningStatusOut(rec)
+	if err != nil {
+		t.Fatalf("ERROR: %s", err)
+	}
+
+	out.Send(NoteOn(0, 60, 100))
+	out.Send(NoteOn(0, 61, 100))
+	out.(drivers.RawOut).SendRaw([]byte{0xF0, 0x41})
+	out.Send(NoteOn(0, 62, 100))
+
+	want := `SendRaw 90 3C 64
+SendRaw 3D 64
+SendRaw F0 41
+SendRaw 90 3E 64
+`
+
+	if got := rec.log.String(); got != want {
+		t.Errorf("\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	// a port without SendRaw would get the data without status byte as complete messages
+	plain := struct{ drivers.Out }{rec}
+
+	if _, err := RunningStatusOut(plain); !errors.Is(err, ErrNoRawOut) {
+		t.Errorf("error: %v; want %v", err, ErrNoRawOut)
+	}
+}